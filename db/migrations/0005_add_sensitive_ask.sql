@@ -0,0 +1,6 @@
+-- step-up 재인증이 필요한 ask 표시
+ALTER TABLE notifications ADD COLUMN sensitive BOOLEAN NOT NULL DEFAULT false;
+
+-- 리액션 시점의 로그인 정보 (감사용)
+ALTER TABLE notifications ADD COLUMN reaction_auth_method TEXT NULL;
+ALTER TABLE notifications ADD COLUMN reaction_auth_at TIMESTAMP NULL;
@@ -0,0 +1,34 @@
+-- 리액션 코멘트 및 응답자 정보
+ALTER TABLE notifications ADD COLUMN reaction_comment TEXT NULL;
+ALTER TABLE notifications ADD COLUMN reaction_user_id UUID NULL REFERENCES users (id) ON DELETE SET NULL;
+ALTER TABLE notifications ADD COLUMN reaction_push_token_id UUID NULL REFERENCES push_tokens (id) ON DELETE SET NULL;
+ALTER TABLE notifications ADD COLUMN reaction_ip TEXT NULL;
+ALTER TABLE notifications ADD COLUMN reaction_user_agent TEXT NULL;
+
+-- 리액션 이력 (append-only). 리액션이 덮어써져도 이전 값이 남는다.
+-- 감사 기록이라 user/push token이 지워져도 값을 유지하도록 FK를 걸지 않음.
+CREATE TABLE notification_reactions (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    notification_id UUID NOT NULL REFERENCES notifications (id) ON DELETE CASCADE,
+    reaction TEXT NOT NULL,
+    comment TEXT NULL,
+    user_id UUID NULL,
+    push_token_id UUID NULL,
+    ip TEXT NULL,
+    user_agent TEXT NULL,
+    auth_method TEXT NULL,
+    auth_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX notification_reactions_notification_id_idx ON notification_reactions (notification_id);
+
+CREATE FUNCTION reject_notification_reactions_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'notification_reactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notification_reactions_immutable
+BEFORE UPDATE ON notification_reactions
+FOR EACH ROW EXECUTE FUNCTION reject_notification_reactions_update();
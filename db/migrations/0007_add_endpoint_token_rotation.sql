@@ -0,0 +1,14 @@
+-- 토큰 재발급 시 유예기간 동안 이전 토큰도 허용
+ALTER TABLE endpoints ADD COLUMN previous_token TEXT NULL UNIQUE;
+ALTER TABLE endpoints ADD COLUMN previous_token_expires_at TIMESTAMP NULL;
+
+-- 토큰 재발급 이력
+CREATE TABLE endpoint_token_rotations (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    endpoint_id UUID NOT NULL REFERENCES endpoints (id) ON DELETE CASCADE,
+    rotated_by UUID NULL REFERENCES users (id) ON DELETE SET NULL,
+    grace_until TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX endpoint_token_rotations_endpoint_id_idx ON endpoint_token_rotations (endpoint_id);
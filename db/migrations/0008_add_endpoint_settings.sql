@@ -0,0 +1,7 @@
+-- endpoint 표시 정보 및 기본 push 옵션
+ALTER TABLE endpoints ADD COLUMN description TEXT NULL;
+ALTER TABLE endpoints ADD COLUMN icon TEXT NULL;
+ALTER TABLE endpoints ADD COLUMN color TEXT NULL;
+ALTER TABLE endpoints ADD COLUMN default_ttl INTEGER NULL;
+ALTER TABLE endpoints ADD COLUMN default_urgency TEXT NULL;
+ALTER TABLE endpoints ADD COLUMN default_click_url TEXT NULL;
@@ -0,0 +1,6 @@
+-- endpoint별 접근 정책. 기본값은 기존 동작(제한 없음)과 동일
+ALTER TABLE endpoints ADD COLUMN allowed_cidrs TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE endpoints ADD COLUMN ask_enabled BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE endpoints ADD COLUMN max_ask_timeout INTEGER NULL;
+ALTER TABLE endpoints ADD COLUMN max_body_size INTEGER NULL;
+ALTER TABLE endpoints ADD COLUMN expires_at TIMESTAMP NULL;
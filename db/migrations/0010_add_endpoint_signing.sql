@@ -0,0 +1,3 @@
+-- 서명 요청(HMAC-SHA256) 강제 옵션. secret은 활성화 시 한 번만 응답으로 노출
+ALTER TABLE endpoints ADD COLUMN signing_secret TEXT NULL;
+ALTER TABLE endpoints ADD COLUMN signing_required BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,8 @@
+-- endpoint 토큰을 서버 키로 만든 HMAC으로 저장.
+-- 해시 키(AUTH_ENDPOINT_TOKEN_KEY)는 서버에만 있으므로 기존 평문 토큰은 서버 시작 시 backfill이 해시로 옮기고 NULL로 지운다.
+-- 조회는 해시로만 하므로 backfill이 끝나기 전에는 요청을 받지 않는다 (실패하면 서버가 시작되지 않음).
+-- (backfill 완료 후 token, previous_token 컬럼은 제거 예정)
+ALTER TABLE endpoints ALTER COLUMN token DROP NOT NULL;
+ALTER TABLE endpoints ADD COLUMN token_hash TEXT NULL UNIQUE;
+ALTER TABLE endpoints ADD COLUMN token_prefix TEXT NULL;
+ALTER TABLE endpoints ADD COLUMN previous_token_hash TEXT NULL UNIQUE;
@@ -0,0 +1,18 @@
+-- endpoint 하나를 여러 시스템(CI, cron, 에이전트 등)이 나눠 쓸 수 있도록 보조 키 추가.
+-- 키는 endpoint 토큰과 같은 방식(HMAC)으로 해시해서 저장하고, 폐기는 revoked_at으로 기록한다.
+CREATE TABLE endpoint_sender_keys (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    endpoint_id UUID NOT NULL REFERENCES endpoints (id) ON DELETE CASCADE,
+    label TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    key_prefix TEXT NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL
+);
+
+CREATE INDEX endpoint_sender_keys_endpoint_id_idx ON endpoint_sender_keys (endpoint_id);
+
+ALTER TABLE notifications ADD COLUMN sender_key_id UUID NULL REFERENCES endpoint_sender_keys (id) ON DELETE SET NULL;
+ALTER TABLE notifications ADD COLUMN sender_key_label TEXT NULL;
@@ -0,0 +1,42 @@
+-- endpoint 공유: 초대 링크로 다른 유저가 참여하고 같은 알림을 받는다.
+-- 알림은 멤버마다 한 row씩 만들어 읽음/삭제/음소거 상태를 따로 가진다.
+CREATE TABLE endpoint_members (
+    endpoint_id UUID NOT NULL REFERENCES endpoints (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member',
+    notification_enabled BOOLEAN NOT NULL DEFAULT true,
+    notification_disabled_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (endpoint_id, user_id),
+    CONSTRAINT endpoint_members_role_check CHECK (role IN ('owner', 'member'))
+);
+
+CREATE INDEX endpoint_members_user_id_idx ON endpoint_members (user_id);
+
+-- 기존 endpoint는 소유자를 owner로 등록하고 음소거 상태를 옮긴다.
+-- (endpoints.notification_enabled, notification_disabled_at은 더 이상 쓰지 않음)
+INSERT INTO endpoint_members (endpoint_id, user_id, role, notification_enabled, notification_disabled_at, created_at)
+SELECT id, user_id, 'owner', notification_enabled, notification_disabled_at, created_at
+FROM endpoints;
+
+CREATE TABLE endpoint_invites (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    endpoint_id UUID NOT NULL REFERENCES endpoints (id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL UNIQUE,
+    code_prefix TEXT NOT NULL,
+    created_by UUID NULL REFERENCES users (id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    max_uses INTEGER NULL,
+    use_count INTEGER NOT NULL DEFAULT 0,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX endpoint_invites_endpoint_id_idx ON endpoint_invites (endpoint_id);
+
+-- 같은 발송으로 만들어진 멤버별 알림을 묶는 ID. ask 대기와 리액션은 이 단위로 처리
+ALTER TABLE notifications ADD COLUMN message_id UUID NULL;
+UPDATE notifications SET message_id = id;
+ALTER TABLE notifications ALTER COLUMN message_id SET NOT NULL;
+
+CREATE INDEX notifications_message_id_idx ON notifications (message_id);
@@ -0,0 +1,23 @@
+-- 조직: 여러 유저가 함께 소유하는 endpoint. 만든 사람이 탈퇴해도 endpoint는 조직에 남는다.
+CREATE TABLE organizations (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE organization_members (
+    org_id UUID NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (org_id, user_id),
+    CONSTRAINT organization_members_role_check CHECK (role IN ('owner', 'admin', 'member'))
+);
+
+CREATE INDEX organization_members_user_id_idx ON organization_members (user_id);
+
+-- endpoint는 개인(user_id) 또는 조직(org_id) 중 하나가 소유
+ALTER TABLE endpoints ADD COLUMN org_id UUID NULL REFERENCES organizations (id) ON DELETE CASCADE;
+ALTER TABLE endpoints ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE endpoints ADD CONSTRAINT endpoints_owner_check CHECK ((user_id IS NULL) <> (org_id IS NULL));
+ALTER TABLE endpoints ADD CONSTRAINT endpoints_org_name_uniq UNIQUE (org_id, name);
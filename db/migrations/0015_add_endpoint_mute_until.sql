@@ -0,0 +1,6 @@
+-- 기한이 있는 음소거. muted_until이 지나면 백그라운드 작업이 음소거를 해제한다.
+-- 해제 전이라도 지난 기한은 음소거로 취급하지 않는다.
+ALTER TABLE endpoint_members ADD COLUMN muted_until TIMESTAMP NULL;
+
+CREATE INDEX endpoint_members_muted_until_idx ON endpoint_members (muted_until)
+WHERE muted_until IS NOT NULL;
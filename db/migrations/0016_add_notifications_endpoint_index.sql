@@ -0,0 +1,2 @@
+-- endpoint별 통계와 마지막 사용 시각 조회용
+CREATE INDEX notifications_endpoint_id_created_at_idx ON notifications (endpoint_id, created_at);
@@ -0,0 +1,41 @@
+-- 알림 전문 검색. 한국어는 형태소 분석이 안 되므로 'simple' 설정으로 토큰만 나누고,
+-- 조사가 붙은 단어 같은 부분 일치는 trigram 인덱스로 처리한다.
+-- 생성 컬럼(STORED)은 추가할 때 ACCESS EXCLUSIVE로 테이블을 다시 쓰므로 일반 컬럼을 트리거로 채운다.
+-- 기존 알림은 나눠서 채우고 (COMMIT) 인덱스는 0026에서 CONCURRENTLY로 만든다. 이 파일은 트랜잭션 없이 실행한다
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- 기본값 없이 추가해 테이블을 다시 쓰지 않는다. 기존 알림은 아래에서 채운다
+ALTER TABLE notifications ADD COLUMN search_vector tsvector NULL;
+
+CREATE FUNCTION set_notification_search_vector() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := to_tsvector('simple', NEW.body || ' ' || NEW.endpoint_name);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notifications_search_vector
+BEFORE INSERT OR UPDATE OF body, endpoint_name ON notifications
+FOR EACH ROW EXECUTE FUNCTION set_notification_search_vector();
+
+-- 기존 알림을 id 순으로 1만 건씩. 배치마다 커밋해 잠금을 오래 쥐지 않는다
+DO $$
+DECLARE
+    last_id UUID := '00000000-0000-0000-0000-000000000000';
+    batch_last UUID;
+BEGIN
+    LOOP
+        SELECT max(id) INTO batch_last
+        FROM (
+            SELECT id FROM notifications WHERE id > last_id ORDER BY id LIMIT 10000
+        ) batch;
+        EXIT WHEN batch_last IS NULL;
+
+        UPDATE notifications SET search_vector = to_tsvector('simple', body || ' ' || endpoint_name)
+        WHERE id > last_id AND id <= batch_last AND search_vector IS NULL;
+
+        last_id := batch_last;
+        COMMIT;
+    END LOOP;
+END;
+$$;
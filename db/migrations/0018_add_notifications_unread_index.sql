@@ -0,0 +1,2 @@
+-- 읽지 않은 알림 수 집계용. 읽은 알림이 대부분이라 partial index로 작게 유지
+CREATE INDEX notifications_unread_idx ON notifications (user_id, endpoint_id) WHERE read_at IS NULL AND is_deleted = false;
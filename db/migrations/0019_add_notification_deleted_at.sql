@@ -0,0 +1,6 @@
+-- 휴지통. 지운 시각 기준으로 오래된 항목을 영구 삭제한다.
+-- 이미 지운 알림은 시각을 알 수 없으므로 지금 지운 것으로 본다
+ALTER TABLE notifications ADD COLUMN deleted_at TIMESTAMP NULL;
+UPDATE notifications SET deleted_at = now() WHERE is_deleted = true;
+
+CREATE INDEX notifications_trash_idx ON notifications (user_id, id) WHERE is_deleted = true;
@@ -0,0 +1,15 @@
+-- 알림 보관 정책. 유저 전체 또는 endpoint 단위 중 하나. 비어있는 항목은 제한 없음
+CREATE TABLE retention_policies (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    user_id UUID NULL REFERENCES users (id) ON DELETE CASCADE,
+    endpoint_id UUID NULL REFERENCES endpoints (id) ON DELETE CASCADE,
+    keep_days INTEGER NULL,
+    keep_count INTEGER NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT retention_policies_target_check CHECK ((user_id IS NULL) <> (endpoint_id IS NULL)),
+    CONSTRAINT retention_policies_user_uniq UNIQUE (user_id),
+    CONSTRAINT retention_policies_endpoint_uniq UNIQUE (endpoint_id)
+);
+
+-- 보관 기간이 지난 알림 삭제용
+CREATE INDEX notifications_created_at_idx ON notifications (created_at);
@@ -0,0 +1,107 @@
+-- 오프라인 클라이언트 동기화용 변경 피드.
+-- 알림이 만들어지거나 바뀔 때마다 change_seq와 바꾼 트랜잭션(change_xid)을 새로 받고, 영구 삭제되면 tombstone을 남긴다.
+-- 피드는 (change_xid, change_seq) 순서이며 진행 중인 트랜잭션보다 앞선 변경까지만 내려준다.
+-- 기존 알림은 나눠서 채우고 (COMMIT) 인덱스는 CONCURRENTLY로 만드므로 이 파일은 트랜잭션 없이 실행한다
+CREATE SEQUENCE notification_change_seq;
+
+-- 기본값 없이 추가해 테이블을 다시 쓰지 않는다. 기존 알림은 아래에서 채운다
+ALTER TABLE notifications
+    ADD COLUMN change_seq BIGINT NULL,
+    ADD COLUMN created_seq BIGINT NULL,
+    ADD COLUMN change_xid xid8 NULL,
+    ADD COLUMN created_xid xid8 NULL;
+
+-- 채우는 동안에는 아직 값이 없는 알림을 바꾸면 그때를 만들어진 시점으로 본다
+CREATE FUNCTION bump_notification_change_seq() RETURNS trigger AS $$
+BEGIN
+    NEW.change_seq := nextval('notification_change_seq');
+    NEW.change_xid := pg_current_xact_id();
+    IF TG_OP = 'INSERT' OR OLD.created_seq IS NULL THEN
+        NEW.created_seq := NEW.change_seq;
+        NEW.created_xid := NEW.change_xid;
+    ELSE
+        NEW.created_seq := OLD.created_seq;
+        NEW.created_xid := OLD.created_xid;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notifications_change_seq
+BEFORE INSERT OR UPDATE ON notifications
+FOR EACH ROW EXECUTE FUNCTION bump_notification_change_seq();
+
+-- 기존 알림을 id 순으로 1만 건씩. 값은 트리거가 채우고 배치마다 커밋해 잠금을 오래 쥐지 않는다
+DO $$
+DECLARE
+    last_id UUID := '00000000-0000-0000-0000-000000000000';
+    batch_last UUID;
+BEGIN
+    LOOP
+        SELECT max(id) INTO batch_last
+        FROM (
+            SELECT id FROM notifications WHERE id > last_id ORDER BY id LIMIT 10000
+        ) batch;
+        EXIT WHEN batch_last IS NULL;
+
+        UPDATE notifications SET change_seq = NULL
+        WHERE id > last_id AND id <= batch_last AND change_seq IS NULL;
+
+        last_id := batch_last;
+        COMMIT;
+    END LOOP;
+END;
+$$;
+
+-- 검증된 CHECK가 있으면 SET NOT NULL이 테이블을 다시 읽지 않는다
+ALTER TABLE notifications ADD CONSTRAINT notifications_change_seq_not_null
+    CHECK (change_seq IS NOT NULL AND created_seq IS NOT NULL AND change_xid IS NOT NULL AND created_xid IS NOT NULL) NOT VALID;
+ALTER TABLE notifications VALIDATE CONSTRAINT notifications_change_seq_not_null;
+ALTER TABLE notifications
+    ALTER COLUMN change_seq SET NOT NULL,
+    ALTER COLUMN created_seq SET NOT NULL,
+    ALTER COLUMN change_xid SET NOT NULL,
+    ALTER COLUMN created_xid SET NOT NULL;
+ALTER TABLE notifications DROP CONSTRAINT notifications_change_seq_not_null;
+
+CREATE OR REPLACE FUNCTION bump_notification_change_seq() RETURNS trigger AS $$
+BEGIN
+    NEW.change_seq := nextval('notification_change_seq');
+    NEW.change_xid := pg_current_xact_id();
+    IF TG_OP = 'INSERT' THEN
+        NEW.created_seq := NEW.change_seq;
+        NEW.created_xid := NEW.change_xid;
+    ELSE
+        NEW.created_seq := OLD.created_seq;
+        NEW.created_xid := OLD.created_xid;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE INDEX CONCURRENTLY notifications_change_idx ON notifications (user_id, change_xid, change_seq);
+
+-- 유저가 지워져도 남아있다가 보관 기간이 지나면 삭제되므로 FK를 걸지 않음
+CREATE TABLE notification_tombstones (
+    notification_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    change_seq BIGINT NOT NULL,
+    deleted_at TIMESTAMP NOT NULL DEFAULT now(),
+    change_xid xid8 NOT NULL DEFAULT pg_current_xact_id()
+);
+
+CREATE INDEX notification_tombstones_change_idx ON notification_tombstones (user_id, change_xid, change_seq);
+CREATE INDEX notification_tombstones_deleted_at_idx ON notification_tombstones (deleted_at);
+
+CREATE FUNCTION record_notification_tombstone() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO notification_tombstones (notification_id, user_id, change_seq)
+    VALUES (OLD.id, OLD.user_id, nextval('notification_change_seq'))
+    ON CONFLICT (notification_id) DO NOTHING;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notifications_tombstone
+AFTER DELETE ON notifications
+FOR EACH ROW EXECUTE FUNCTION record_notification_tombstone();
@@ -0,0 +1,35 @@
+-- 알림이 영구 삭제(휴지통 비우기, 보관 정책)돼도 리액션 이력은 남긴다.
+-- 알림 FK는 SET NULL로 바꾸고, 같은 발송의 이력을 계속 찾을 수 있도록 message_id와 endpoint_id를 이력에 직접 둔다
+ALTER TABLE notification_reactions ADD COLUMN message_id UUID NULL;
+ALTER TABLE notification_reactions ADD COLUMN endpoint_id UUID NULL;
+
+-- 기존 행 채우기. append-only 트리거는 잠시 끈다
+ALTER TABLE notification_reactions DISABLE TRIGGER notification_reactions_immutable;
+UPDATE notification_reactions r
+SET message_id = n.message_id,
+    endpoint_id = n.endpoint_id
+FROM notifications n
+WHERE n.id = r.notification_id;
+ALTER TABLE notification_reactions ENABLE TRIGGER notification_reactions_immutable;
+
+ALTER TABLE notification_reactions ALTER COLUMN message_id SET NOT NULL;
+ALTER TABLE notification_reactions ALTER COLUMN notification_id DROP NOT NULL;
+ALTER TABLE notification_reactions DROP CONSTRAINT notification_reactions_notification_id_fkey;
+ALTER TABLE notification_reactions
+ADD CONSTRAINT notification_reactions_notification_id_fkey
+FOREIGN KEY (notification_id) REFERENCES notifications (id) ON DELETE SET NULL;
+
+CREATE INDEX notification_reactions_message_id_idx ON notification_reactions (message_id);
+
+-- 알림이 지워질 때 FK가 notification_id를 비우는 것만 허용
+CREATE OR REPLACE FUNCTION reject_notification_reactions_update() RETURNS trigger AS $$
+DECLARE
+    detached notification_reactions := OLD;
+BEGIN
+    detached.notification_id := NULL;
+    IF NEW IS NOT DISTINCT FROM detached THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'notification_reactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
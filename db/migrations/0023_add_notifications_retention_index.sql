@@ -0,0 +1,7 @@
+-- 보관 개수 정리에서 유저 전체, 멤버별 endpoint의 keep_count번째 알림을 인덱스로 찾는다.
+-- CONCURRENTLY는 트랜잭션 안에서 실행할 수 없으므로 이 파일은 트랜잭션 없이 실행한다
+CREATE INDEX CONCURRENTLY IF NOT EXISTS notifications_user_id_id_idx
+ON notifications (user_id, id) WHERE is_deleted = false;
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS notifications_user_endpoint_id_idx
+ON notifications (user_id, endpoint_id, id) WHERE is_deleted = false;
@@ -0,0 +1,15 @@
+-- 조직 초대. 이메일로 초대를 남기고 그 이메일로 가입한 유저가 수락해야 멤버가 된다.
+-- 가입 여부와 관계없이 같은 응답을 주므로 초대로 가입한 이메일인지 알아낼 수 없다
+CREATE TABLE organization_invites (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    org_id UUID NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    email TEXT NOT NULL, -- 소문자로 저장
+    role TEXT NOT NULL DEFAULT 'member',
+    invited_by UUID NULL REFERENCES users (id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT organization_invites_role_check CHECK (role IN ('owner', 'admin', 'member')),
+    CONSTRAINT organization_invites_email_uniq UNIQUE (org_id, email)
+);
+
+CREATE INDEX organization_invites_email_idx ON organization_invites (email);
@@ -0,0 +1,4 @@
+-- web push 결과 (mute, sent, failed)를 status와 따로 남긴다.
+-- status는 ask 응답, 타임아웃, 취소로 덮어써지므로 통계는 delivery를 센다.
+-- 이전 알림은 채우지 않는다 (변경 피드에 모두 바뀐 것으로 올라가지 않도록). 통계는 비어 있으면 status를 본다
+ALTER TABLE notifications ADD COLUMN delivery TEXT NULL;
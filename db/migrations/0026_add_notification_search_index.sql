@@ -0,0 +1,7 @@
+-- 0017의 검색 컬럼용 GIN 인덱스. 이미 만들어진 곳에서는 건너뛴다.
+-- CONCURRENTLY는 트랜잭션 안에서 실행할 수 없으므로 이 파일은 트랜잭션 없이 실행한다
+CREATE INDEX CONCURRENTLY IF NOT EXISTS notifications_search_vector_idx
+ON notifications USING GIN (search_vector);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS notifications_body_trgm_idx
+ON notifications USING GIN (body gin_trgm_ops);
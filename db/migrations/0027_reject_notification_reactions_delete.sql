@@ -0,0 +1,14 @@
+-- 리액션 이력은 지울 수 없다. 알림이 영구 삭제되면 FK가 notification_id만 비운다 (0022)
+CREATE FUNCTION reject_notification_reactions_delete() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'notification_reactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notification_reactions_undeletable
+BEFORE DELETE ON notification_reactions
+FOR EACH ROW EXECUTE FUNCTION reject_notification_reactions_delete();
+
+CREATE TRIGGER notification_reactions_untruncatable
+BEFORE TRUNCATE ON notification_reactions
+FOR EACH STATEMENT EXECUTE FUNCTION reject_notification_reactions_delete();
@@ -0,0 +1,4 @@
+-- endpoint 피드(poll)가 알림이 영구 삭제된 응답 이력도 endpoint_id로 찾는다.
+-- CONCURRENTLY는 트랜잭션 안에서 실행할 수 없으므로 이 파일은 트랜잭션 없이 실행한다
+CREATE INDEX CONCURRENTLY IF NOT EXISTS notification_reactions_endpoint_created_idx
+ON notification_reactions (endpoint_id, created_at, id);
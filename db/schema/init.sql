@@ -1,3 +1,6 @@
+-- 알림 부분 일치 검색용
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
 -- users
 CREATE TABLE users (
     id UUID PRIMARY KEY DEFAULT uuidv7(),
@@ -23,18 +26,134 @@ CREATE TABLE push_tokens (
     UNIQUE (p256dh_key, auth_key, endpoint)
 );
 
+-- organizations (조직 소유 endpoint)
+CREATE TABLE organizations (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- organization_members
+CREATE TABLE organization_members (
+    org_id UUID NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member', -- owner, admin, member
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (org_id, user_id),
+    CONSTRAINT organization_members_role_check CHECK (role IN ('owner', 'admin', 'member'))
+);
+
+CREATE INDEX organization_members_user_id_idx ON organization_members (user_id);
+
+-- organization_invites (수락해야 멤버가 된다. 수락, 거절, 취소하면 삭제)
+CREATE TABLE organization_invites (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    org_id UUID NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    email TEXT NOT NULL, -- 소문자로 저장
+    role TEXT NOT NULL DEFAULT 'member',
+    invited_by UUID NULL REFERENCES users (id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT organization_invites_role_check CHECK (role IN ('owner', 'admin', 'member')),
+    CONSTRAINT organization_invites_email_uniq UNIQUE (org_id, email)
+);
+
+CREATE INDEX organization_invites_email_idx ON organization_invites (email);
+
 -- endpoints
 CREATE TABLE endpoints (
     id UUID PRIMARY KEY DEFAULT uuidv7(),
-    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    user_id UUID NULL REFERENCES users (id) ON DELETE CASCADE, -- 개인 endpoint 소유자
     name TEXT NOT NULL,
-    token TEXT NOT NULL UNIQUE,
+    token TEXT NULL UNIQUE, -- 해시 이전의 평문 토큰 (backfill 대상)
+    notification_enabled BOOLEAN NOT NULL DEFAULT true, -- 사용 안 함 (endpoint_members로 이동)
+    notification_disabled_at TIMESTAMP NULL, -- 사용 안 함 (endpoint_members로 이동)
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    previous_token TEXT NULL UNIQUE,
+    previous_token_expires_at TIMESTAMP NULL,
+    description TEXT NULL,
+    icon TEXT NULL,
+    color TEXT NULL,
+    default_ttl INTEGER NULL,
+    default_urgency TEXT NULL,
+    default_click_url TEXT NULL,
+    allowed_cidrs TEXT[] NOT NULL DEFAULT '{}',
+    ask_enabled BOOLEAN NOT NULL DEFAULT true,
+    max_ask_timeout INTEGER NULL,
+    max_body_size INTEGER NULL,
+    expires_at TIMESTAMP NULL,
+    signing_secret TEXT NULL,
+    signing_required BOOLEAN NOT NULL DEFAULT false,
+    token_hash TEXT NULL UNIQUE, -- HMAC-SHA256(서버 키, 토큰)
+    token_prefix TEXT NULL, -- 화면 표시용 앞자리
+    previous_token_hash TEXT NULL UNIQUE,
+    org_id UUID NULL REFERENCES organizations (id) ON DELETE CASCADE, -- 조직 endpoint 소유 조직
+    CONSTRAINT endpoints_user_name_uniq UNIQUE (user_id, name),
+    CONSTRAINT endpoints_owner_check CHECK ((user_id IS NULL) <> (org_id IS NULL)),
+    CONSTRAINT endpoints_org_name_uniq UNIQUE (org_id, name)
+);
+
+-- endpoint_members (공유 endpoint 멤버. 음소거는 멤버별)
+CREATE TABLE endpoint_members (
+    endpoint_id UUID NOT NULL REFERENCES endpoints (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member', -- owner, member
     notification_enabled BOOLEAN NOT NULL DEFAULT true,
     notification_disabled_at TIMESTAMP NULL,
+    muted_until TIMESTAMP NULL, -- 기한이 있는 음소거면 자동 해제 시각
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (endpoint_id, user_id),
+    CONSTRAINT endpoint_members_role_check CHECK (role IN ('owner', 'member'))
+);
+
+CREATE INDEX endpoint_members_user_id_idx ON endpoint_members (user_id);
+CREATE INDEX endpoint_members_muted_until_idx ON endpoint_members (muted_until) WHERE muted_until IS NOT NULL;
+
+-- endpoint_invites (초대 링크. 코드는 해시로 저장)
+CREATE TABLE endpoint_invites (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    endpoint_id UUID NOT NULL REFERENCES endpoints (id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL UNIQUE,
+    code_prefix TEXT NOT NULL,
+    created_by UUID NULL REFERENCES users (id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    max_uses INTEGER NULL, -- NULL이면 횟수 제한 없음
+    use_count INTEGER NOT NULL DEFAULT 0,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX endpoint_invites_endpoint_id_idx ON endpoint_invites (endpoint_id);
+
+-- endpoint_token_rotations
+CREATE TABLE endpoint_token_rotations (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    endpoint_id UUID NOT NULL REFERENCES endpoints (id) ON DELETE CASCADE,
+    rotated_by UUID NULL REFERENCES users (id) ON DELETE SET NULL,
+    grace_until TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX endpoint_token_rotations_endpoint_id_idx ON endpoint_token_rotations (endpoint_id);
+
+-- endpoint_sender_keys (endpoint별 보조 키. 알림에 어떤 키로 보냈는지 기록)
+CREATE TABLE endpoint_sender_keys (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    endpoint_id UUID NOT NULL REFERENCES endpoints (id) ON DELETE CASCADE,
+    label TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE, -- HMAC-SHA256(서버 키, 키)
+    key_prefix TEXT NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}', -- push, ask, read
     created_at TIMESTAMP NOT NULL DEFAULT now(),
-    CONSTRAINT endpoints_user_name_uniq UNIQUE (user_id, name)
+    last_used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL
 );
 
+CREATE INDEX endpoint_sender_keys_endpoint_id_idx ON endpoint_sender_keys (endpoint_id);
+
+-- 알림 변경 피드 순서
+CREATE SEQUENCE notification_change_seq;
+
 -- notifications
 CREATE TABLE notifications (
     id UUID PRIMARY KEY DEFAULT uuidv7(),
@@ -48,6 +167,156 @@ CREATE TABLE notifications (
     status TEXT DEFAULT 'pending',
     read_at TIMESTAMP NULL,
     is_deleted BOOLEAN NOT NULL DEFAULT false,
-    created_at TIMESTAMP NOT NULL DEFAULT now()
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    sensitive BOOLEAN NOT NULL DEFAULT false,
+    reaction_auth_method TEXT NULL,
+    reaction_auth_at TIMESTAMP NULL,
+    reaction_comment TEXT NULL,
+    reaction_user_id UUID NULL REFERENCES users (id) ON DELETE SET NULL,
+    reaction_push_token_id UUID NULL REFERENCES push_tokens (id) ON DELETE SET NULL,
+    reaction_ip TEXT NULL,
+    reaction_user_agent TEXT NULL,
+    sender_key_id UUID NULL REFERENCES endpoint_sender_keys (id) ON DELETE SET NULL,
+    sender_key_label TEXT NULL,
+    message_id UUID NOT NULL, -- 같은 발송으로 만들어진 멤버별 알림을 묶는 ID
+    search_vector tsvector NULL, -- body와 endpoint_name의 'simple' 검색 토큰 (trigger)
+    deleted_at TIMESTAMP NULL, -- 휴지통으로 옮긴 시각
+    change_seq BIGINT NOT NULL, -- 만들어지거나 바뀔 때마다 notification_change_seq에서 새로 받음 (trigger)
+    created_seq BIGINT NOT NULL, -- 만들어질 때의 change_seq
+    change_xid xid8 NOT NULL, -- 바꾼 트랜잭션. 변경 피드는 (change_xid, change_seq) 순서 (trigger)
+    created_xid xid8 NOT NULL, -- 만들어질 때의 change_xid
+    delivery TEXT NULL -- web push 결과 (mute, sent, failed). status와 달리 ask 응답으로 바뀌지 않음
+);
+
+CREATE INDEX notifications_message_id_idx ON notifications (message_id);
+CREATE INDEX notifications_endpoint_id_created_at_idx ON notifications (endpoint_id, created_at);
+CREATE INDEX notifications_search_vector_idx ON notifications USING GIN (search_vector);
+CREATE INDEX notifications_body_trgm_idx ON notifications USING GIN (body gin_trgm_ops);
+CREATE INDEX notifications_unread_idx ON notifications (user_id, endpoint_id) WHERE read_at IS NULL AND is_deleted = false;
+CREATE INDEX notifications_trash_idx ON notifications (user_id, id) WHERE is_deleted = true;
+CREATE INDEX notifications_created_at_idx ON notifications (created_at);
+CREATE INDEX notifications_change_idx ON notifications (user_id, change_xid, change_seq);
+CREATE INDEX notifications_user_id_id_idx ON notifications (user_id, id) WHERE is_deleted = false;
+CREATE INDEX notifications_user_endpoint_id_idx ON notifications (user_id, endpoint_id, id) WHERE is_deleted = false;
+
+CREATE FUNCTION set_notification_search_vector() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := to_tsvector('simple', NEW.body || ' ' || NEW.endpoint_name);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notifications_search_vector
+BEFORE INSERT OR UPDATE OF body, endpoint_name ON notifications
+FOR EACH ROW EXECUTE FUNCTION set_notification_search_vector();
+
+CREATE FUNCTION bump_notification_change_seq() RETURNS trigger AS $$
+BEGIN
+    NEW.change_seq := nextval('notification_change_seq');
+    NEW.change_xid := pg_current_xact_id();
+    IF TG_OP = 'INSERT' THEN
+        NEW.created_seq := NEW.change_seq;
+        NEW.created_xid := NEW.change_xid;
+    ELSE
+        NEW.created_seq := OLD.created_seq;
+        NEW.created_xid := OLD.created_xid;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notifications_change_seq
+BEFORE INSERT OR UPDATE ON notifications
+FOR EACH ROW EXECUTE FUNCTION bump_notification_change_seq();
+
+-- notification_tombstones (영구 삭제된 알림. 변경 피드에서 deleted로 내려주고 보관 기간이 지나면 삭제)
+-- 유저가 지워져도 남아있다가 함께 정리되므로 FK를 걸지 않음
+CREATE TABLE notification_tombstones (
+    notification_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    change_seq BIGINT NOT NULL,
+    deleted_at TIMESTAMP NOT NULL DEFAULT now(),
+    change_xid xid8 NOT NULL DEFAULT pg_current_xact_id()
 );
 
+CREATE INDEX notification_tombstones_change_idx ON notification_tombstones (user_id, change_xid, change_seq);
+CREATE INDEX notification_tombstones_deleted_at_idx ON notification_tombstones (deleted_at);
+
+CREATE FUNCTION record_notification_tombstone() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO notification_tombstones (notification_id, user_id, change_seq)
+    VALUES (OLD.id, OLD.user_id, nextval('notification_change_seq'))
+    ON CONFLICT (notification_id) DO NOTHING;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notifications_tombstone
+AFTER DELETE ON notifications
+FOR EACH ROW EXECUTE FUNCTION record_notification_tombstone();
+
+-- notification_reactions (append-only 리액션 이력. 알림이 영구 삭제돼도 남는다)
+CREATE TABLE notification_reactions (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    notification_id UUID NULL REFERENCES notifications (id) ON DELETE SET NULL, -- 알림이 영구 삭제되면 NULL
+    reaction TEXT NOT NULL,
+    comment TEXT NULL,
+    user_id UUID NULL,
+    push_token_id UUID NULL,
+    ip TEXT NULL,
+    user_agent TEXT NULL,
+    auth_method TEXT NULL,
+    auth_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    message_id UUID NOT NULL, -- 응답한 알림의 발송 ID
+    endpoint_id UUID NULL -- 응답한 알림의 endpoint. 감사 기록이라 FK를 걸지 않음
+);
+
+CREATE INDEX notification_reactions_notification_id_idx ON notification_reactions (notification_id);
+CREATE INDEX notification_reactions_message_id_idx ON notification_reactions (message_id);
+CREATE INDEX notification_reactions_endpoint_created_idx ON notification_reactions (endpoint_id, created_at, id);
+
+-- 알림이 지워질 때 FK가 notification_id를 비우는 것만 허용
+CREATE FUNCTION reject_notification_reactions_update() RETURNS trigger AS $$
+DECLARE
+    detached notification_reactions := OLD;
+BEGIN
+    detached.notification_id := NULL;
+    IF NEW IS NOT DISTINCT FROM detached THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'notification_reactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notification_reactions_immutable
+BEFORE UPDATE ON notification_reactions
+FOR EACH ROW EXECUTE FUNCTION reject_notification_reactions_update();
+
+-- 이력은 지울 수 없다
+CREATE FUNCTION reject_notification_reactions_delete() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'notification_reactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notification_reactions_undeletable
+BEFORE DELETE ON notification_reactions
+FOR EACH ROW EXECUTE FUNCTION reject_notification_reactions_delete();
+
+CREATE TRIGGER notification_reactions_untruncatable
+BEFORE TRUNCATE ON notification_reactions
+FOR EACH STATEMENT EXECUTE FUNCTION reject_notification_reactions_delete();
+
+-- retention_policies (알림 보관 정책. 유저 전체 또는 endpoint 단위 중 하나, 비어있는 항목은 제한 없음)
+CREATE TABLE retention_policies (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    user_id UUID NULL REFERENCES users (id) ON DELETE CASCADE,
+    endpoint_id UUID NULL REFERENCES endpoints (id) ON DELETE CASCADE,
+    keep_days INTEGER NULL,
+    keep_count INTEGER NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT retention_policies_target_check CHECK ((user_id IS NULL) <> (endpoint_id IS NULL)),
+    CONSTRAINT retention_policies_user_uniq UNIQUE (user_id),
+    CONSTRAINT retention_policies_endpoint_uniq UNIQUE (endpoint_id)
+);
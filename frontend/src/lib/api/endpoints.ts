@@ -2,16 +2,106 @@ import { api, catchError, type Result } from '$lib/pkg/fetch';
 export interface Endpoint {
 	id: string;
 	name: string;
-	token: string;
+	token_prefix: string; // 토큰 원문은 생성/재발급 응답에서만 받을 수 있음
 	active: boolean;
+	muted_until: string | null; // 기한이 있는 음소거면 자동 해제 시각
+	mute_remaining: number | null; // 남은 음소거 시간(초)
+	description: string | null;
+	icon: string | null;
+	color: string | null;
+	default_ttl: number | null;
+	default_urgency: 'very-low' | 'low' | 'normal' | 'high' | null;
+	default_click_url: string | null;
+	policy: EndpointPolicy;
+	signing_required: boolean;
+	role: EndpointRole; // member는 알림 수신과 본인 음소거만 가능
+	can_manage: boolean; // 조직 endpoint는 조직 owner, admin이 관리
+	org_id: string | null;
+	org_name: string | null;
+}
+
+export type EndpointRole = 'owner' | 'member';
+
+export interface EndpointPolicy {
+	allowed_cidrs: string[];
+	ask_enabled: boolean;
+	max_ask_timeout: number | null;
+	max_body_size: number | null;
+	expires_at: string | null;
+}
+
+// 생략한 필드는 변경하지 않음. 빈 문자열이나 0은 설정 해제
+export type EndpointUpdate = Partial<
+	Pick<
+		Endpoint,
+		'name' | 'description' | 'icon' | 'color' | 'default_urgency' | 'default_click_url'
+	> & { default_ttl: number }
+>;
+
+export type SenderKeyScope = 'push' | 'ask' | 'read';
+
+// endpoint 보조 키. 보내는 쪽(CI, cron 등)마다 따로 발급하고 따로 폐기
+export interface SenderKey {
+	id: string;
+	label: string;
+	key_prefix: string;
+	scopes: SenderKeyScope[];
+	created_at: string;
+	last_used_at: string | null;
+	revoked_at: string | null;
+}
+
+// endpoint를 공유받는 유저. 읽음, 삭제, 음소거 상태는 멤버마다 따로 가짐
+export interface Member {
+	user_id: string;
+	role: EndpointRole;
+	email: string | null;
+	guest: boolean;
+	joined_at: string;
+}
+
+// 초대 링크. 코드 원문은 생성 응답에서만 확인 가능
+export interface Invite {
+	id: string;
+	code_prefix: string;
+	expires_at: string;
+	max_uses: number | null;
+	use_count: number;
+	revoked_at: string | null;
+	created_at: string;
 }
 
-export async function addEndpoint(name: string): Promise<Result<void>> {
+// 발송(message) 단위 집계. muted, failed는 멤버별 알림 수
+export interface StatsCount {
+	sent: number;
+	muted: number;
+	failed: number;
+	asks: number;
+	asks_answered: number;
+	asks_timed_out: number;
+	median_reaction_seconds: number | null;
+}
+
+export interface EndpointStats {
+	since: string;
+	total: StatsCount;
+	daily: (StatsCount & { day: string })[]; // day: YYYY-MM-DD (UTC), 발송이 없는 날도 포함
+	last_used_at: string | null;
+}
+
+export interface IssuedToken {
+	id: string;
+	token: string;
+}
+
+// orgId가 있으면 조직 endpoint로 생성
+export async function addEndpoint(name: string, orgId?: string): Promise<Result<IssuedToken>> {
 	return await catchError(
-		api<void>(`/endpoints`, {
+		api<IssuedToken>(`/endpoints`, {
 			method: 'POST',
 			body: {
 				serviceName: name.trim(),
+				org_id: orgId,
 			},
 		}),
 	);
@@ -22,24 +112,188 @@ export async function fetchEndpoints(): Promise<Endpoint[]> {
 	return res;
 }
 
-export async function deleteEndpoint(token: string): Promise<void> {
-	await api<void>(`/endpoints/${token}`, {
+export async function updateEndpoint(id: string, update: EndpointUpdate): Promise<Result<Endpoint>> {
+	return await catchError(
+		api<Endpoint>(`/endpoints/${id}`, {
+			method: 'PATCH',
+			body: update,
+		}),
+	);
+}
+
+// 정책 전체를 교체. null은 제한 없음
+export async function updateEndpointPolicy(
+	id: string,
+	policy: EndpointPolicy,
+): Promise<Result<Endpoint>> {
+	return await catchError(
+		api<Endpoint>(`/endpoints/${id}/policy`, {
+			method: 'PUT',
+			body: policy,
+		}),
+	);
+}
+
+// 새 secret은 이 응답에서만 확인 가능
+export async function enableEndpointSigning(id: string): Promise<Result<{ secret: string }>> {
+	return await catchError(
+		api<{ secret: string }>(`/endpoints/${id}/signing`, {
+			method: 'POST',
+		}),
+	);
+}
+
+export async function disableEndpointSigning(id: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/endpoints/${id}/signing`, {
+			method: 'DELETE',
+		}),
+	);
+}
+
+export async function deleteEndpoint(id: string): Promise<void> {
+	await api<void>(`/endpoints/${id}`, {
 		method: 'DELETE',
 	});
 }
 
-export async function muteEndpoint(token: string): Promise<Result<void>> {
+// duration(초)을 생략하면 직접 해제할 때까지 음소거
+export async function muteEndpoint(id: string, duration?: number): Promise<Result<void>> {
 	return await catchError(
-		api<void>(`/endpoints/${token}/mute`, {
+		api<void>(`/endpoints/${id}/mute`, {
 			method: 'POST',
+			body: duration ? { duration } : undefined,
 		}),
 	);
 }
 
-export async function unmuteEndpoint(token: string): Promise<Result<void>> {
+export async function unmuteEndpoint(id: string): Promise<Result<void>> {
 	return catchError(
-		api<void>(`/endpoints/${token}/mute`, {
+		api<void>(`/endpoints/${id}/mute`, {
+			method: 'DELETE',
+		}),
+	);
+}
+
+// gracePeriod(초) 동안은 이전 토큰도 허용
+export async function rotateEndpoint(
+	id: string,
+	gracePeriod = 0,
+): Promise<Result<IssuedToken & { previous_token_expires_at: string | null }>> {
+	return await catchError(
+		api<IssuedToken & { previous_token_expires_at: string | null }>(`/endpoints/${id}/rotate`, {
+			method: 'POST',
+			body: { grace_period: gracePeriod },
+		}),
+	);
+}
+
+export async function fetchSenderKeys(endpointId: string): Promise<SenderKey[]> {
+	return await api<SenderKey[]>(`/endpoints/${endpointId}/keys`);
+}
+
+// 키 원문은 이 응답에서만 확인 가능. scopes를 생략하면 push, ask만 허용 (read는 지정해야 함)
+export async function addSenderKey(
+	endpointId: string,
+	label: string,
+	scopes?: SenderKeyScope[],
+): Promise<Result<SenderKey & { key: string }>> {
+	return await catchError(
+		api<SenderKey & { key: string }>(`/endpoints/${endpointId}/keys`, {
+			method: 'POST',
+			body: { label: label.trim(), scopes },
+		}),
+	);
+}
+
+export async function revokeSenderKey(endpointId: string, keyId: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/endpoints/${endpointId}/keys/${keyId}`, {
+			method: 'DELETE',
+		}),
+	);
+}
+
+// days를 생략하면 최근 30일 (최대 90일)
+export async function fetchEndpointStats(id: string, days?: number): Promise<EndpointStats> {
+	const query = days ? `?days=${days}` : '';
+	return await api<EndpointStats>(`/endpoints/${id}/stats${query}`);
+}
+
+export async function fetchMembers(endpointId: string): Promise<Member[]> {
+	return await api<Member[]>(`/endpoints/${endpointId}/members`);
+}
+
+// owner만 다른 멤버를 내보낼 수 있음
+export async function removeMember(endpointId: string, userId: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/endpoints/${endpointId}/members/${userId}`, {
+			method: 'DELETE',
+		}),
+	);
+}
+
+// 공유받은 endpoint에서 나가기. owner는 나갈 수 없음
+export async function leaveEndpoint(endpointId: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/endpoints/${endpointId}/members/me`, {
+			method: 'DELETE',
+		}),
+	);
+}
+
+// expiresIn(초)를 생략하면 7일, maxUses를 생략하면 횟수 제한 없음
+export async function createInvite(
+	endpointId: string,
+	expiresIn?: number,
+	maxUses?: number,
+): Promise<Result<Invite & { code: string }>> {
+	return await catchError(
+		api<Invite & { code: string }>(`/endpoints/${endpointId}/invites`, {
+			method: 'POST',
+			body: { expires_in: expiresIn, max_uses: maxUses },
+		}),
+	);
+}
+
+export async function fetchInvites(endpointId: string): Promise<Invite[]> {
+	return await api<Invite[]>(`/endpoints/${endpointId}/invites`);
+}
+
+export async function revokeInvite(endpointId: string, inviteId: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/endpoints/${endpointId}/invites/${inviteId}`, {
 			method: 'DELETE',
 		}),
 	);
 }
+
+export async function joinEndpoint(
+	code: string,
+): Promise<Result<{ endpoint_id: string; endpoint_name: string }>> {
+	return await catchError(
+		api<{ endpoint_id: string; endpoint_name: string }>(`/endpoints/join`, {
+			method: 'POST',
+			body: { code },
+		}),
+	);
+}
+
+// 개인 endpoint를 조직으로 이전. 되돌릴 수 없음
+export async function transferEndpoint(id: string, orgId: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/endpoints/${id}/transfer`, {
+			method: 'POST',
+			body: { org_id: orgId },
+		}),
+	);
+}
+
+// 조직 멤버는 초대 없이 조직 endpoint 알림을 받을 수 있음
+export async function subscribeEndpoint(id: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/endpoints/${id}/members/me`, {
+			method: 'POST',
+		}),
+	);
+}
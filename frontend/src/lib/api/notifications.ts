@@ -1,4 +1,5 @@
 import { api } from '$lib/pkg/fetch';
+import { debugLog } from '$lib/pkg/util';
 
 export type NotificationStatus =
 	| 'pending'
@@ -21,6 +22,7 @@ export interface NotificationApiResponse {
 	mute: boolean;
 	actions: string[] | null;
 	reaction: string | null;
+	sender_key_label: string | null; // sender key로 보낸 경우 그 키의 이름
 }
 
 export interface PaginatedNotiResponse {
@@ -29,6 +31,12 @@ export interface PaginatedNotiResponse {
 	has_more: boolean;
 }
 
+export interface SearchNotiResponse {
+	items: (NotificationApiResponse & { rank: number; snippet: string })[]; // snippet: HTML 이스케이프됨, 일치 부분은 <mark>
+	next_cursor: string | null;
+	has_more: boolean;
+}
+
 export interface DisplayNotification {
 	id: string;
 	endpointName: string;
@@ -42,6 +50,7 @@ export interface DisplayNotification {
 	reaction: string | null; // 반응한 리액션 값, 없으면 null
 	isExpired: boolean; // timeout_reply면 true → 버튼 비활성화용
 	isCancelled: boolean; // 요청자가 취소한 경우
+	senderKeyLabel: string | null;
 }
 
 function formatTimestamp(dateString: string): string {
@@ -72,23 +81,96 @@ export function transformNotification(apiData: NotificationApiResponse): Display
 		reaction: apiData.reaction,
 		isExpired: apiData.status === 'timeout_reply',
 		isCancelled: apiData.status === 'cancelled',
+		senderKeyLabel: apiData.sender_key_label ?? null,
 	};
 }
 
+// 목록 필터. 지정한 조건은 모두 AND로 조합
+export interface NotificationFilter {
+	status?: NotificationStatus[];
+	unread?: boolean;
+	hasActions?: boolean;
+	pending?: boolean; // 아직 응답하지 않은 ask만
+	from?: string; // RFC3339 또는 YYYY-MM-DD
+	to?: string; // 날짜만 주면 그 날 전체 포함
+	reaction?: string;
+}
+
 // 실제 API 호출 (Cursor 지원)
 export async function getNotifications(
 	cursor?: string,
 	endpointID?: string,
 	searchQuery?: string,
+	filter: NotificationFilter = {},
 ): Promise<PaginatedNotiResponse> {
 	let path = `/notifications?limit=20`;
 
 	if (cursor) path += `&cursor=${encodeURIComponent(cursor)}`;
-	if (endpointID && endpointID !== 'ALL') path += `&endpoint_id=${encodeURIComponent(endpointID)}`;
-	if (searchQuery) path += `&query=${encodeURIComponent(searchQuery)}`;
+	path += filterParams(endpointID, searchQuery, filter);
 	return await api<PaginatedNotiResponse>(path);
 }
 
+// 목록과 내보내기가 같이 쓰는 필터 쿼리 (& 로 시작)
+function filterParams(endpointID?: string, searchQuery?: string, filter: NotificationFilter = {}): string {
+	let params = '';
+	if (endpointID && endpointID !== 'ALL') params += `&endpoint_id=${encodeURIComponent(endpointID)}`;
+	if (searchQuery) params += `&query=${encodeURIComponent(searchQuery)}`;
+	if (filter.status?.length) params += `&status=${filter.status.join(',')}`;
+	if (filter.unread) params += `&unread=true`;
+	if (filter.hasActions) params += `&has_actions=true`;
+	if (filter.pending) params += `&pending=true`;
+	if (filter.from) params += `&from=${encodeURIComponent(filter.from)}`;
+	if (filter.to) params += `&to=${encodeURIComponent(filter.to)}`;
+	if (filter.reaction) params += `&reaction=${encodeURIComponent(filter.reaction)}`;
+	return params;
+}
+
+export type ExportFormat = 'csv' | 'json' | 'ndjson';
+
+// 내보내기는 파일로 바로 받도록 링크 URL만 만든다 (응답이 커서 fetch로 읽지 않음)
+export function exportNotificationsUrl(
+	format: ExportFormat,
+	endpointID?: string,
+	searchQuery?: string,
+	filter: NotificationFilter = {},
+): string {
+	return `/api/notifications/export?format=${format}` + filterParams(endpointID, searchQuery, filter);
+}
+
+export async function getNotification(id: string): Promise<NotificationApiResponse> {
+	return await api<NotificationApiResponse>(`/notifications/${id}`);
+}
+
+// 관련도순 검색. "구문", -제외, or 문법 지원. 커서는 응답의 next_cursor를 그대로 넘긴다
+export async function searchNotifications(
+	query: string,
+	cursor?: string,
+	endpointID?: string,
+): Promise<SearchNotiResponse> {
+	let path = `/notifications/search?limit=20&q=${encodeURIComponent(query)}`;
+
+	if (cursor) path += `&cursor=${encodeURIComponent(cursor)}`;
+	if (endpointID && endpointID !== 'ALL') path += `&endpoint_id=${encodeURIComponent(endpointID)}`;
+	return await api<SearchNotiResponse>(path);
+}
+
+// 읽지 않은 알림 수. endpoints에는 읽지 않은 알림이 있는 endpoint만 들어 있다
+export interface UnreadCount {
+	total: number;
+	endpoints: Record<string, number>;
+}
+
+export async function getUnreadCount(): Promise<UnreadCount> {
+	return await api<UnreadCount>('/notifications/unread-count');
+}
+
+// 앱 아이콘 배지 동기화. Badging API를 지원하지 않는 브라우저는 무시
+export function syncAppBadge(total: number) {
+	if (!('setAppBadge' in navigator)) return;
+	const update = total > 0 ? navigator.setAppBadge(total) : navigator.clearAppBadge();
+	update.catch((e) => debugLog('app badge', e));
+}
+
 // 알림 읽음 처리
 export async function markAsReadUntil(lastId: string, endpointID?: string): Promise<void> {
 	const actualEndpointID = endpointID && endpointID !== 'ALL' ? endpointID : undefined;
@@ -105,6 +187,77 @@ export async function deleteNotification(id: string): Promise<void> {
 	});
 }
 
+// 알림 하나를 읽음 / 안 읽음으로
+export async function setNotificationRead(id: string, read: boolean): Promise<void> {
+	await api(`/notifications/${id}/${read ? 'read' : 'unread'}`, {
+		method: 'POST',
+	});
+}
+
+export type BulkAction = 'read' | 'unread' | 'delete' | 'restore';
+
+// ids와 filter 중 하나만 지정. restore는 ids로만 가능. 최대 500개
+export type BulkTarget =
+	| { ids: string[] }
+	| { filter: NotificationFilter & { endpoint_id?: string; query?: string } };
+
+export async function bulkNotifications(action: BulkAction, target: BulkTarget): Promise<number> {
+	const body: Record<string, unknown> = { action };
+	if ('ids' in target) {
+		body.ids = target.ids;
+	} else {
+		const { hasActions, ...rest } = target.filter;
+		body.filter = { ...rest, has_actions: hasActions };
+	}
+
+	const res = await api<{ affected: number }>(`/notifications/bulk`, {
+		method: 'POST',
+		body,
+	});
+	return res.affected;
+}
+
+export interface TrashNotiResponse {
+	items: (NotificationApiResponse & { deleted_at: string })[];
+	next_cursor: string | null;
+	has_more: boolean;
+}
+
+export interface NotificationChange {
+	type: 'created' | 'updated' | 'deleted';
+	id: string;
+	notification: NotificationApiResponse | null; // deleted면 null
+}
+
+export interface ChangesResponse {
+	changes: NotificationChange[];
+	next_token: string; // 다음 요청의 since
+	has_more: boolean;
+}
+
+// 변경 피드. since 없이 부르면 변경 없이 지금 시점의 토큰만 받는다 (목록보다 먼저 호출)
+// 410 SYNC_TOKEN_EXPIRED면 목록부터 다시 받아야 한다
+export async function getChanges(since?: string): Promise<ChangesResponse> {
+	let path = `/notifications/changes?limit=200`;
+	if (since) path += `&since=${encodeURIComponent(since)}`;
+	return await api<ChangesResponse>(path, { toastType: 'none' });
+}
+
+// 휴지통 목록
+export async function getTrash(cursor?: string): Promise<TrashNotiResponse> {
+	let path = `/notifications/trash?limit=20`;
+	if (cursor) path += `&cursor=${encodeURIComponent(cursor)}`;
+	return await api<TrashNotiResponse>(path);
+}
+
+// olderThanDays일보다 오래전에 지운 알림을 영구 삭제. 생략하면 휴지통 비우기
+export async function purgeTrash(olderThanDays?: number): Promise<number> {
+	let path = `/notifications/trash`;
+	if (olderThanDays !== undefined) path += `?older_than_days=${olderThanDays}`;
+	const res = await api<{ purged: number }>(path, { method: 'DELETE' });
+	return res.purged;
+}
+
 // 알림 리액션 api
 export async function postReaction(id: string, action: string): Promise<void> {
 	await api(`/v1/react/${id}`, {
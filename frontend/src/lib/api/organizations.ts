@@ -0,0 +1,147 @@
+import { api, catchError, type Result } from '$lib/pkg/fetch';
+import type { Endpoint } from './endpoints';
+
+export type OrganizationRole = 'owner' | 'admin' | 'member';
+
+export interface Organization {
+	id: string;
+	name: string;
+	role: OrganizationRole; // 내 역할
+	created_at: string;
+}
+
+export interface OrganizationMember {
+	user_id: string;
+	role: OrganizationRole;
+	email: string | null;
+	guest: boolean;
+	joined_at: string;
+}
+
+export async function fetchOrganizations(): Promise<Organization[]> {
+	return await api<Organization[]>('/orgs');
+}
+
+export async function createOrganization(name: string): Promise<Result<{ id: string }>> {
+	return await catchError(
+		api<{ id: string }>('/orgs', {
+			method: 'POST',
+			body: { name: name.trim() },
+		}),
+	);
+}
+
+// owner만 가능. 조직 endpoint도 함께 삭제됨
+export async function deleteOrganization(id: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/orgs/${id}`, {
+			method: 'DELETE',
+		}),
+	);
+}
+
+export async function fetchOrganizationMembers(id: string): Promise<OrganizationMember[]> {
+	return await api<OrganizationMember[]>(`/orgs/${id}/members`);
+}
+
+export interface OrganizationInvite {
+	id: string;
+	email: string;
+	role: OrganizationRole;
+	invited_by: string | null;
+	expires_at: string;
+	created_at: string;
+}
+
+// 내 이메일로 온 초대
+export interface MyOrganizationInvite {
+	id: string;
+	org_id: string;
+	org_name: string;
+	role: OrganizationRole;
+	expires_at: string;
+	created_at: string;
+}
+
+// 그 이메일로 가입한 유저가 수락해야 멤버가 됨. 가입 여부와 관계없이 같은 응답. admin은 owner로 초대할 수 없음
+export async function inviteOrganizationMember(
+	id: string,
+	email: string,
+	role: OrganizationRole = 'member',
+): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/orgs/${id}/invites`, {
+			method: 'POST',
+			body: { email: email.trim(), role },
+		}),
+	);
+}
+
+// owner/admin만 가능. 만료된 초대도 포함
+export async function fetchOrganizationInvites(id: string): Promise<OrganizationInvite[]> {
+	return await api<OrganizationInvite[]>(`/orgs/${id}/invites`);
+}
+
+export async function revokeOrganizationInvite(id: string, inviteId: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/orgs/${id}/invites/${inviteId}`, {
+			method: 'DELETE',
+		}),
+	);
+}
+
+export async function fetchMyOrganizationInvites(): Promise<MyOrganizationInvite[]> {
+	return await api<MyOrganizationInvite[]>('/orgs/invites');
+}
+
+export async function acceptOrganizationInvite(inviteId: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/orgs/invites/${inviteId}/accept`, {
+			method: 'POST',
+		}),
+	);
+}
+
+export async function declineOrganizationInvite(inviteId: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/orgs/invites/${inviteId}`, {
+			method: 'DELETE',
+		}),
+	);
+}
+
+// owner만 가능
+export async function updateOrganizationMemberRole(
+	id: string,
+	userId: string,
+	role: OrganizationRole,
+): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/orgs/${id}/members/${userId}`, {
+			method: 'PATCH',
+			body: { role },
+		}),
+	);
+}
+
+export async function removeOrganizationMember(id: string, userId: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/orgs/${id}/members/${userId}`, {
+			method: 'DELETE',
+		}),
+	);
+}
+
+// 마지막 owner는 나갈 수 없음
+export async function leaveOrganization(id: string): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/orgs/${id}/members/me`, {
+			method: 'DELETE',
+		}),
+	);
+}
+
+// role이 빈 문자열인 endpoint는 아직 알림을 받지 않는 것
+export async function fetchOrganizationEndpoints(id: string): Promise<Endpoint[]> {
+	return await api<Endpoint[]>(`/orgs/${id}/endpoints`);
+}
@@ -0,0 +1,46 @@
+import { api, catchError, type Result } from '$lib/pkg/fetch';
+
+// 알림 보관 정책. null이면 제한 없음, max_days(서버 최대)는 항상 적용
+export interface RetentionPolicy {
+	keep_days: number | null;
+	keep_count: number | null; // endpoint 정책은 멤버별 개수
+	max_days: number;
+	updated_at: string | null;
+}
+
+export interface RetentionInput {
+	keep_days: number | null;
+	keep_count: number | null;
+}
+
+// 내 알림 전체에 적용되는 정책
+export async function fetchRetention(): Promise<RetentionPolicy> {
+	return await api<RetentionPolicy>('/retention');
+}
+
+// 둘 다 null이면 설정 삭제
+export async function updateRetention(input: RetentionInput): Promise<Result<void>> {
+	return await catchError(
+		api<void>('/retention', {
+			method: 'PUT',
+			body: input,
+		}),
+	);
+}
+
+export async function fetchEndpointRetention(endpointId: string): Promise<RetentionPolicy> {
+	return await api<RetentionPolicy>(`/retention/endpoints/${endpointId}`);
+}
+
+// endpoint 관리 권한 필요
+export async function updateEndpointRetention(
+	endpointId: string,
+	input: RetentionInput,
+): Promise<Result<void>> {
+	return await catchError(
+		api<void>(`/retention/endpoints/${endpointId}`, {
+			method: 'PUT',
+			body: input,
+		}),
+	);
+}
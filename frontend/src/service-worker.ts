@@ -63,6 +63,7 @@ sw.addEventListener('push', (event) => {
 		url: '/app', // 기본 URL
 		tag: 'default-tag',
 		data: {},
+		unread: undefined as number | undefined,
 	};
 
 	// 데이터 파싱 (JSON 파싱 실패 대비)
@@ -83,7 +84,13 @@ sw.addEventListener('push', (event) => {
 		tag: `push-${Date.now()}`,
 	};
 
-	event.waitUntil(sw.registration.showNotification(data.title, options));
+	const tasks: Promise<void>[] = [sw.registration.showNotification(data.title, options)];
+	// 서버가 보낸 읽지 않은 수로 앱 아이콘 배지 갱신
+	if (typeof data.unread === 'number' && 'setAppBadge' in navigator) {
+		tasks.push(data.unread > 0 ? navigator.setAppBadge(data.unread) : navigator.clearAppBadge());
+	}
+
+	event.waitUntil(Promise.all(tasks));
 });
 
 sw.addEventListener('notificationclick', (event) => {
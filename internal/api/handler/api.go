@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -12,19 +13,30 @@ import (
 	"time"
 	"torchi/internal/api/wrapper"
 	"torchi/internal/domain/common"
+	"torchi/internal/domain/endpoint"
+	"torchi/internal/domain/notifications"
 	"torchi/internal/domain/push"
+	"torchi/internal/domain/sse"
 	"torchi/internal/pkg/config"
 	"torchi/internal/pkg/log"
+	"torchi/internal/pkg/signature"
+	authtoken "torchi/internal/pkg/token"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 type ApiHandler struct {
-	log     *log.Logger
-	service *push.PushService
-
-	frontUrl string
+	log             *log.Logger
+	service         *push.PushService
+	endpointService *endpoint.EndpointService
+	notiService     *notifications.NotiService
+	feedBroker      *sse.EndpointBroker
+	replayCache     *signature.ReplayCache
+
+	frontUrl         string
+	trustProxy       bool
+	signatureMaxSkew time.Duration
 }
 
 func NewApiHandler(
@@ -32,12 +44,22 @@ func NewApiHandler(
 	env config.Env,
 
 	service *push.PushService,
+	endpointService *endpoint.EndpointService,
+	notiService *notifications.NotiService,
+	feedBroker *sse.EndpointBroker,
+	replayCache *signature.ReplayCache,
 ) *ApiHandler {
 	return &ApiHandler{
-		log:     log,
-		service: service,
-
-		frontUrl: env.FrontUrl,
+		log:             log,
+		service:         service,
+		endpointService: endpointService,
+		notiService:     notiService,
+		feedBroker:      feedBroker,
+		replayCache:     replayCache,
+
+		frontUrl:         env.FrontUrl,
+		trustProxy:       env.Service.TrustProxy,
+		signatureMaxSkew: env.Auth.SignatureMaxSkew,
 	}
 }
 func (h *ApiHandler) Routes() chi.Router {
@@ -45,7 +67,15 @@ func (h *ApiHandler) Routes() chi.Router {
 	r.Post("/push/{token}", h.Push)
 	r.Post("/demo", h.Demo)
 	r.Post("/push/{token}/ask", h.Ask)
-	r.Post("/react/{notiID}", wrapper.WrapJson(h.React, h.log.Error))
+	r.Get("/push/{token}/stream", h.Stream)
+	r.Get("/push/{token}/poll", h.Poll)
+
+	// 토큰을 Authorization: Bearer 또는 X-Torchi-Token 헤더로 전달 (경로/로그에 남지 않음)
+	r.Post("/push", h.Push)
+	r.Post("/push/ask", h.Ask)
+	r.Get("/push/stream", h.Stream)
+	r.Get("/push/poll", h.Poll)
+	r.Post("/react/{notiID}", h.React)
 	r.Post("/push-test", wrapper.WrapJson(h.TestPush, h.log.Error))
 	r.Post("/push-demo", wrapper.WrapJson(h.DemoPush, h.log.Error))
 
@@ -107,16 +137,21 @@ type resPush struct {
 
 func (h *ApiHandler) Push(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	token := chi.URLParamFromCtx(ctx, "token")
+	token := endpointToken(r)
 
-	h.log.Info("...", "token", token)
+	h.log.Info("...", "token", authtoken.Redact(token))
 
-	bodyBytes, err := io.ReadAll(r.Body)
+	endpoint, err := h.authorizeEndpoint(r, token, endpoint.ScopePush)
 	if err != nil {
-		wrapper.RespondError(w, common.ErrInternalServer)
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	bodyBytes, err := h.readBody(w, r, endpoint)
+	if err != nil {
+		wrapper.RespondError(w, err)
 		return
 	}
-	defer r.Body.Close()
 
 	message := string(bodyBytes)
 	if message == "" {
@@ -124,7 +159,7 @@ func (h *ApiHandler) Push(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	count, err := h.service.Push(ctx, token, message)
+	count, err := h.service.Push(ctx, endpoint, message)
 	if err != nil {
 		wrapper.RespondError(w, err)
 		return
@@ -136,7 +171,21 @@ func (h *ApiHandler) Push(w http.ResponseWriter, r *http.Request) {
 }
 func (h *ApiHandler) Ask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	token := chi.URLParamFromCtx(ctx, "token")
+	token := endpointToken(r)
+
+	endpoint, err := h.authorizeEndpoint(r, token, endpoint.ScopeAsk)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	// 서명 검증을 위해 body를 먼저 읽고 form 파싱용으로 되돌려 둠
+	bodyBytes, err := h.readBody(w, r, endpoint)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 
 	if err := r.ParseForm(); err != nil {
 		wrapper.RespondError(w, common.ErrBadRequest)
@@ -144,6 +193,11 @@ func (h *ApiHandler) Ask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	msg := r.FormValue("msg")
+	// query string으로 온 msg는 body 제한에 걸리지 않으므로 따로 확인
+	if limit := endpoint.Policy.MaxBodySize; limit != nil && len(msg) > *limit {
+		wrapper.RespondError(w, common.ErrPayloadTooLarge)
+		return
+	}
 
 	var actions []string
 	if raw := r.FormValue("actions"); raw != "" {
@@ -154,18 +208,31 @@ func (h *ApiHandler) Ask(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	timeout := 300
+	timeout := defaultAskTimeout
+	if maxTimeout := endpoint.Policy.MaxAskTimeout; maxTimeout != nil && timeout > *maxTimeout {
+		timeout = *maxTimeout
+	}
 	if t := r.FormValue("timeout"); t != "" {
-		if v, err := strconv.Atoi(t); err == nil {
-			timeout = v
+		v, err := strconv.Atoi(t)
+		if err != nil {
+			wrapper.RespondError(w, common.ErrInvalidParam)
+			return
 		}
+		timeout = v
+	}
+	if err := endpoint.Policy.CheckAsk(timeout); err != nil {
+		wrapper.RespondError(w, err)
+		return
 	}
 
+	// sensitive=true 이면 응답 시 재로그인(step-up)이 필요
+	sensitive, _ := strconv.ParseBool(r.FormValue("sensitive"))
+
 	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
 	defer cancel()
 
-	h.log.Debug("...", "token", token, "msg", msg, "actions", actions, "timeout", timeout)
-	reaction, err := h.service.PushAndWait(timeoutCtx, token, msg, actions)
+	h.log.Debug("...", "token", authtoken.Redact(token), "msg", msg, "actions", actions, "timeout", timeout, "sensitive", sensitive)
+	result, err := h.service.PushAndWait(timeoutCtx, endpoint, msg, actions, sensitive)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			wrapper.RespondJSON(w, http.StatusRequestTimeout, map[string]string{
@@ -177,27 +244,177 @@ func (h *ApiHandler) Ask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 기본은 리액션 값만 plain text로 (curl 결과를 그대로 비교하는 스크립트 호환)
+	// Accept: application/json 이거나 format=json 이면 코멘트까지 JSON으로 반환
+	if wantsJSON(r) {
+		wrapper.RespondJSON(w, http.StatusOK, resAsk{
+			Reaction: result.Reaction,
+			Comment:  result.Comment,
+		})
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write([]byte(reaction)); err != nil {
+	if _, err := w.Write([]byte(result.Reaction)); err != nil {
 		h.log.Error("failed to write reaction response", "err", err)
 	}
 }
 
-type reqReact struct {
-	Reaction string `json:"reaction"`
+const defaultAskTimeout = 300
+
+const endpointTokenHeader = "X-Torchi-Token"
+
+// EndpointTokenFromHeader Authorization: Bearer 또는 X-Torchi-Token 헤더의 endpoint 토큰
+func EndpointTokenFromHeader(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		scheme, value, ok := strings.Cut(auth, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(value)
+		}
+	}
+	return strings.TrimSpace(r.Header.Get(endpointTokenHeader))
+}
+
+// endpointToken 경로의 {token}이 우선, 없으면 헤더
+func endpointToken(r *http.Request) string {
+	if t := chi.URLParam(r, "token"); t != "" {
+		return t
+	}
+	return EndpointTokenFromHeader(r)
 }
 
-func (h *ApiHandler) React(ctx context.Context, req reqReact) (interface{}, error) {
+// authorizeEndpoint 토큰으로 endpoint를 찾고 만료, 허용 IP 정책과 sender key의 scope를 확인
+func (h *ApiHandler) authorizeEndpoint(r *http.Request, token string, scope string) (*endpoint.Endpoint, error) {
+	if token == "" {
+		return nil, common.ErrUnauthorized
+	}
 
-	notiID := chi.URLParamFromCtx(ctx, "notiID")
-	notiUUID, err := uuid.Parse(notiID)
+	e, err := h.endpointService.FindByToken(r.Context(), token)
 	if err != nil {
 		return nil, err
 	}
+	if e == nil {
+		return nil, common.ErrEndpointNotFound
+	}
+	if err := e.CheckScope(scope); err != nil {
+		return nil, err
+	}
 
-	if err := h.service.React(ctx, notiUUID, req.Reaction); err != nil {
+	if err := e.Policy.CheckAccess(wrapper.ClientIP(r, h.trustProxy), time.Now()); err != nil {
 		return nil, err
 	}
+	return e, nil
+}
 
-	return nil, nil
+// readBody 정책의 크기 제한을 적용해 body를 읽고, 서명이 필요한 endpoint면 검증
+func (h *ApiHandler) readBody(w http.ResponseWriter, r *http.Request, e *endpoint.Endpoint) ([]byte, error) {
+	defer r.Body.Close()
+
+	body := r.Body
+	if e.Policy.MaxBodySize != nil {
+		body = http.MaxBytesReader(w, r.Body, int64(*e.Policy.MaxBodySize))
+	}
+
+	bodyBytes, err := io.ReadAll(body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, common.ErrPayloadTooLarge
+		}
+		return nil, common.ErrInternalServer
+	}
+
+	if e.SigningRequired {
+		if err := h.verifySignature(r, e, bodyBytes); err != nil {
+			return nil, err
+		}
+	}
+	return bodyBytes, nil
+}
+
+func (h *ApiHandler) verifySignature(r *http.Request, e *endpoint.Endpoint, body []byte) error {
+	if e.SigningSecret == nil {
+		return common.ErrSignatureInvalid
+	}
+
+	req := signature.Request{
+		Timestamp:  r.Header.Get(signature.HeaderTimestamp),
+		Signature:  r.Header.Get(signature.HeaderSignature),
+		Method:     r.Method,
+		RequestURI: r.URL.RequestURI(),
+		Body:       body,
+	}
+
+	now := time.Now()
+	if err := signature.Verify(*e.SigningSecret, req, now, h.signatureMaxSkew); err != nil {
+		switch {
+		case errors.Is(err, signature.ErrMissing):
+			return common.ErrSignatureRequired
+		case errors.Is(err, signature.ErrStale):
+			return common.ErrSignatureExpired
+		default:
+			return common.ErrSignatureInvalid
+		}
+	}
+
+	// timestamp가 미래로 skew만큼 어긋날 수 있으므로 2배 동안 기억
+	key := e.ID.String() + ":" + strings.ToLower(strings.TrimPrefix(req.Signature, "sha256="))
+	if h.replayCache.Seen(key, now, now.Add(2*h.signatureMaxSkew)) {
+		return common.ErrSignatureReplayed
+	}
+	return nil
+}
+
+type resAsk struct {
+	Reaction string `json:"reaction"`
+	Comment  string `json:"comment"`
+}
+
+func wantsJSON(r *http.Request) bool {
+	return r.FormValue("format") == "json" ||
+		strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+type reqReact struct {
+	Reaction     string `json:"reaction"`
+	Comment      string `json:"comment"`
+	PushEndpoint string `json:"push_endpoint"` // 응답한 기기의 구독 endpoint (선택)
+}
+
+const maxReactionCommentLength = 500
+
+func (h *ApiHandler) React(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	notiUUID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "notiID"))
+	if err != nil {
+		wrapper.RespondError(w, common.ErrInvalidParam)
+		return
+	}
+
+	var req reqReact
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Error("parse json error", "err", err)
+		wrapper.RespondError(w, common.ErrBadRequest)
+		return
+	}
+	if len([]rune(req.Comment)) > maxReactionCommentLength {
+		wrapper.RespondError(w, common.ErrInvalidParam)
+		return
+	}
+
+	if err := h.service.React(ctx, push.ReactParams{
+		NotiID:       notiUUID,
+		Reaction:     req.Reaction,
+		Comment:      strings.TrimSpace(req.Comment),
+		PushEndpoint: req.PushEndpoint,
+		IP:           wrapper.ClientIP(r, h.trustProxy),
+		UserAgent:    r.UserAgent(),
+	}); err != nil {
+		h.log.Error("handler error", "err", err)
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, nil)
 }
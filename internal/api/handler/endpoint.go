@@ -2,8 +2,13 @@ package handler
 
 import (
 	"context"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 	"torchi/internal/api/wrapper"
+	"torchi/internal/domain/common"
 	"torchi/internal/domain/endpoint"
 	"torchi/internal/pkg/log"
 
@@ -26,31 +31,117 @@ func (h *EndpointHandler) Routes() chi.Router {
 	r := chi.NewRouter()
 	r.Post("/", wrapper.WrapJson(h.Add, h.log.Error))
 	r.Get("/", h.GetList)
-	r.Delete("/{token}", wrapper.WrapJson(h.Delete, h.log.Error))
-	r.Post("/{token}/mute", wrapper.WrapJson(h.Mute, h.log.Error))
-	r.Delete("/{token}/mute", wrapper.WrapJson(h.Unmute, h.log.Error))
+	r.Post("/join", wrapper.WrapJson(h.Join, h.log.Error))
+	r.Patch("/{id}", wrapper.WrapJson(h.Update, h.log.Error))
+	r.Put("/{id}/policy", wrapper.WrapJson(h.UpdatePolicy, h.log.Error))
+	r.Post("/{id}/signing", wrapper.WrapJson(h.EnableSigning, h.log.Error))
+	r.Delete("/{id}/signing", wrapper.WrapJson(h.DisableSigning, h.log.Error))
+	r.Delete("/{id}", wrapper.WrapJson(h.Delete, h.log.Error))
+	r.Post("/{id}/mute", wrapper.WrapJson(h.Mute, h.log.Error))
+	r.Delete("/{id}/mute", wrapper.WrapJson(h.Unmute, h.log.Error))
+	r.Post("/{id}/rotate", wrapper.WrapJson(h.Rotate, h.log.Error))
+	r.Get("/{id}/keys", h.GetSenderKeys)
+	r.Post("/{id}/keys", wrapper.WrapJson(h.AddSenderKey, h.log.Error))
+	r.Delete("/{id}/keys/{keyID}", wrapper.WrapJson(h.RevokeSenderKey, h.log.Error))
+	r.Post("/{id}/transfer", wrapper.WrapJson(h.Transfer, h.log.Error))
+	r.Get("/{id}/stats", h.GetStats)
+	r.Get("/{id}/members", h.GetMembers)
+	r.Post("/{id}/members/me", wrapper.WrapJson(h.Subscribe, h.log.Error))
+	r.Delete("/{id}/members/me", wrapper.WrapJson(h.Leave, h.log.Error))
+	r.Delete("/{id}/members/{userID}", wrapper.WrapJson(h.RemoveMember, h.log.Error))
+	r.Get("/{id}/invites", h.GetInvites)
+	r.Post("/{id}/invites", wrapper.WrapJson(h.CreateInvite, h.log.Error))
+	r.Delete("/{id}/invites/{inviteID}", wrapper.WrapJson(h.RevokeInvite, h.log.Error))
 	return r
 }
 
 type reqAddEndpoint struct {
-	ServiceName string `json:"serviceName"`
+	ServiceName string     `json:"serviceName"`
+	OrgID       *uuid.UUID `json:"org_id"` // 있으면 조직 endpoint로 생성
+}
+
+// resAddEndpoint 토큰은 해시로 저장되므로 이 응답에서만 확인 가능
+type resAddEndpoint struct {
+	ID    uuid.UUID `json:"id"`
+	Token string    `json:"token"`
 }
 
 func (h *EndpointHandler) Add(ctx context.Context, req reqAddEndpoint) (interface{}, error) {
 
 	h.log.Info("req", "sub", req)
-	if err := h.service.Add(ctx, req.ServiceName); err != nil {
+	result, err := h.service.Add(ctx, req.ServiceName, req.OrgID)
+	if err != nil {
 		return nil, err
 	}
 
-	return nil, nil
+	return resAddEndpoint{
+		ID:    result.EndpointID,
+		Token: result.Token,
+	}, nil
 }
 
 type resListEndpoint struct {
-	ID     uuid.UUID `json:"id"`
-	Name   string    `json:"name"`
-	Token  string    `json:"token"`
-	Active bool      `json:"active"`
+	ID              uuid.UUID  `json:"id"`
+	Name            string     `json:"name"`
+	TokenPrefix     string     `json:"token_prefix"`
+	Active          bool       `json:"active"` // 내 음소거 상태
+	MutedUntil      *time.Time `json:"muted_until"`
+	MuteRemaining   *int       `json:"mute_remaining"` // 기한이 있는 음소거의 남은 시간(초)
+	Role            string     `json:"role"`           // owner, member. 알림을 받지 않으면 빈 문자열
+	CanManage       bool       `json:"can_manage"`
+	OrgID           *uuid.UUID `json:"org_id"`
+	OrgName         *string    `json:"org_name"`
+	Description     *string    `json:"description"`
+	Icon            *string    `json:"icon"`
+	Color           *string    `json:"color"`
+	DefaultTTL      *int       `json:"default_ttl"`
+	DefaultUrgency  *string    `json:"default_urgency"`
+	DefaultClickURL *string    `json:"default_click_url"`
+	Policy          resPolicy  `json:"policy"`
+	SigningRequired bool       `json:"signing_required"`
+}
+
+type resPolicy struct {
+	AllowedCIDRs  []string   `json:"allowed_cidrs"`
+	AskEnabled    bool       `json:"ask_enabled"`
+	MaxAskTimeout *int       `json:"max_ask_timeout"`
+	MaxBodySize   *int       `json:"max_body_size"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+func toResListEndpoint(e *endpoint.Endpoint) resListEndpoint {
+	var remaining *int
+	if e.MutedUntil != nil {
+		sec := int(math.Ceil(e.MuteRemaining(time.Now()).Seconds()))
+		remaining = &sec
+	}
+
+	return resListEndpoint{
+		ID:              e.ID,
+		Name:            e.Name,
+		TokenPrefix:     e.TokenPrefix,
+		Active:          e.NotificationEnable,
+		MutedUntil:      e.MutedUntil,
+		MuteRemaining:   remaining,
+		Role:            e.Role,
+		CanManage:       e.CanManage,
+		OrgID:           e.OrgID,
+		OrgName:         e.OrgName,
+		Description:     e.Description,
+		Icon:            e.Icon,
+		Color:           e.Color,
+		DefaultTTL:      e.DefaultTTL,
+		DefaultUrgency:  e.DefaultUrgency,
+		DefaultClickURL: e.DefaultClickURL,
+		Policy: resPolicy{
+			AllowedCIDRs:  e.Policy.AllowedCIDRs,
+			AskEnabled:    e.Policy.AskEnabled,
+			MaxAskTimeout: e.Policy.MaxAskTimeout,
+			MaxBodySize:   e.Policy.MaxBodySize,
+			ExpiresAt:     e.Policy.ExpiresAt,
+		},
+		SigningRequired: e.SigningRequired,
+	}
 }
 
 func (h *EndpointHandler) GetList(w http.ResponseWriter, r *http.Request) {
@@ -62,21 +153,118 @@ func (h *EndpointHandler) GetList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	result := make([]resListEndpoint, 0, len(endpoints))
-	for _, endpoint := range endpoints {
-		result = append(result, resListEndpoint{
-			ID:     endpoint.ID,
-			Name:   endpoint.Name,
-			Token:  endpoint.Token,
-			Active: endpoint.NotificationEnable,
-		})
+	for i := range endpoints {
+		result = append(result, toResListEndpoint(&endpoints[i]))
 	}
 
 	wrapper.RespondJSON(w, http.StatusOK, result)
 }
 
+// reqUpdateEndpoint 생략한 필드는 변경하지 않음. 빈 문자열이나 0을 보내면 설정 해제
+type reqUpdateEndpoint struct {
+	Name            *string `json:"name"`
+	Description     *string `json:"description"`
+	Icon            *string `json:"icon"`
+	Color           *string `json:"color"`
+	DefaultTTL      *int    `json:"default_ttl"`
+	DefaultUrgency  *string `json:"default_urgency"`
+	DefaultClickURL *string `json:"default_click_url"`
+}
+
+func (h *EndpointHandler) Update(ctx context.Context, req reqUpdateEndpoint) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.service.Update(ctx, id, endpoint.UpdateParams{
+		Name:            req.Name,
+		Description:     req.Description,
+		Icon:            req.Icon,
+		Color:           req.Color,
+		DefaultTTL:      req.DefaultTTL,
+		DefaultUrgency:  req.DefaultUrgency,
+		DefaultClickURL: req.DefaultClickURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toResListEndpoint(result), nil
+}
+
+// reqUpdatePolicy 정책 전체를 교체한다. 생략한 필드는 제한 없음으로 설정됨
+type reqUpdatePolicy struct {
+	AllowedCIDRs  []string   `json:"allowed_cidrs"` // CIDR 또는 단일 IP
+	AskEnabled    *bool      `json:"ask_enabled"`   // 생략 시 true
+	MaxAskTimeout *int       `json:"max_ask_timeout"`
+	MaxBodySize   *int       `json:"max_body_size"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+func (h *EndpointHandler) UpdatePolicy(ctx context.Context, req reqUpdatePolicy) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	askEnabled := true
+	if req.AskEnabled != nil {
+		askEnabled = *req.AskEnabled
+	}
+
+	result, err := h.service.UpdatePolicy(ctx, id, endpoint.Policy{
+		AllowedCIDRs:  req.AllowedCIDRs,
+		AskEnabled:    askEnabled,
+		MaxAskTimeout: req.MaxAskTimeout,
+		MaxBodySize:   req.MaxBodySize,
+		ExpiresAt:     req.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toResListEndpoint(result), nil
+}
+
+type resEnableSigning struct {
+	Secret string `json:"secret"`
+}
+
+// EnableSigning 이미 켜져 있으면 secret을 새로 발급 (이전 secret은 즉시 무효)
+func (h *EndpointHandler) EnableSigning(ctx context.Context, _ interface{}) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := h.service.EnableSigning(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return resEnableSigning{Secret: secret}, nil
+}
+
+func (h *EndpointHandler) DisableSigning(ctx context.Context, _ interface{}) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.service.DisableSigning(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
 func (h *EndpointHandler) Delete(ctx context.Context, _ interface{}) (interface{}, error) {
-	token := chi.URLParamFromCtx(ctx, "token")
-	if err := h.service.Remove(ctx, token); err != nil {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.service.Remove(ctx, id); err != nil {
 		return nil, err
 	}
 
@@ -84,10 +272,28 @@ func (h *EndpointHandler) Delete(ctx context.Context, _ interface{}) (interface{
 
 }
 
-func (h *EndpointHandler) Mute(ctx context.Context, _ interface{}) (interface{}, error) {
-	token := chi.URLParamFromCtx(ctx, "token")
+// reqMuteEndpoint 둘 다 생략하면 직접 해제할 때까지 음소거
+type reqMuteEndpoint struct {
+	Duration int        `json:"duration"` // 음소거할 시간(초)
+	Until    *time.Time `json:"until"`    // 이 시각까지 음소거
+}
+
+func (h *EndpointHandler) Mute(ctx context.Context, req reqMuteEndpoint) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	until := req.Until
+	if req.Duration != 0 {
+		if until != nil || req.Duration < 0 {
+			return nil, common.ErrInvalidParam
+		}
+		t := time.Now().Add(time.Duration(req.Duration) * time.Second)
+		until = &t
+	}
 
-	if err := h.service.UpdateMute(ctx, token, false); err != nil {
+	if err := h.service.UpdateMute(ctx, id, false, until); err != nil {
 		return nil, err
 	}
 
@@ -95,11 +301,426 @@ func (h *EndpointHandler) Mute(ctx context.Context, _ interface{}) (interface{},
 }
 
 func (h *EndpointHandler) Unmute(ctx context.Context, _ interface{}) (interface{}, error) {
-	token := chi.URLParamFromCtx(ctx, "token")
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.service.UpdateMute(ctx, id, true, nil); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+type reqRotateEndpoint struct {
+	GracePeriod int `json:"grace_period"` // 이전 토큰 유예 시간(초), 0이면 즉시 무효
+}
+
+type resRotateEndpoint struct {
+	ID                     uuid.UUID  `json:"id"`
+	Token                  string     `json:"token"`
+	PreviousTokenExpiresAt *time.Time `json:"previous_token_expires_at"`
+}
+
+func (h *EndpointHandler) Rotate(ctx context.Context, req reqRotateEndpoint) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.service.RotateToken(ctx, id, time.Duration(req.GracePeriod)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return resRotateEndpoint{
+		ID:                     result.EndpointID,
+		Token:                  result.Token,
+		PreviousTokenExpiresAt: result.PreviousTokenExpiresAt,
+	}, nil
+}
+
+type reqAddSenderKey struct {
+	Label  string   `json:"label"`
+	Scopes []string `json:"scopes"` // push, ask. 생략하면 모두 허용
+}
+
+type resSenderKey struct {
+	ID         uuid.UUID  `json:"id"`
+	Label      string     `json:"label"`
+	KeyPrefix  string     `json:"key_prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// resAddSenderKey 키는 해시로 저장되므로 이 응답에서만 확인 가능
+type resAddSenderKey struct {
+	resSenderKey
+	Key string `json:"key"`
+}
+
+func toResSenderKey(k *endpoint.SenderKey) resSenderKey {
+	return resSenderKey{
+		ID:         k.ID,
+		Label:      k.Label,
+		KeyPrefix:  k.KeyPrefix,
+		Scopes:     k.Scopes,
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+}
+
+func (h *EndpointHandler) GetSenderKeys(w http.ResponseWriter, r *http.Request) {
+	id, err := endpointID(r.Context())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	keys, err := h.service.ListSenderKeys(r.Context(), id)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	result := make([]resSenderKey, 0, len(keys))
+	for i := range keys {
+		result = append(result, toResSenderKey(&keys[i]))
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, result)
+}
+
+func (h *EndpointHandler) AddSenderKey(ctx context.Context, req reqAddSenderKey) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.service.AddSenderKey(ctx, id, req.Label, req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return resAddSenderKey{
+		resSenderKey: toResSenderKey(&result.SenderKey),
+		Key:          result.Key,
+	}, nil
+}
+
+func (h *EndpointHandler) RevokeSenderKey(ctx context.Context, _ interface{}) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keyID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "keyID"))
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+
+	if err := h.service.RevokeSenderKey(ctx, id, keyID); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+type resMember struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Role     string    `json:"role"`
+	Email    *string   `json:"email"`
+	Guest    bool      `json:"guest"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type resStatsCount struct {
+	Sent                  int64    `json:"sent"`   // 발송 수
+	Muted                 int64    `json:"muted"`  // 음소거로 push하지 않은 멤버별 알림 수
+	Failed                int64    `json:"failed"` // push에 실패한 멤버별 알림 수
+	Asks                  int64    `json:"asks"`
+	AsksAnswered          int64    `json:"asks_answered"`
+	AsksTimedOut          int64    `json:"asks_timed_out"`
+	MedianReactionSeconds *float64 `json:"median_reaction_seconds"`
+}
+
+type resDailyStats struct {
+	Day string `json:"day"` // YYYY-MM-DD (UTC)
+	resStatsCount
+}
+
+type resStats struct {
+	Since      time.Time       `json:"since"`
+	Total      resStatsCount   `json:"total"`
+	Daily      []resDailyStats `json:"daily"`
+	LastUsedAt *time.Time      `json:"last_used_at"`
+}
+
+func toResStatsCount(c endpoint.StatsCount) resStatsCount {
+	var median *float64
+	if c.MedianReaction != nil {
+		sec := c.MedianReaction.Seconds()
+		median = &sec
+	}
+
+	return resStatsCount{
+		Sent:                  c.Sent,
+		Muted:                 c.Muted,
+		Failed:                c.Failed,
+		Asks:                  c.Asks,
+		AsksAnswered:          c.Answered,
+		AsksTimedOut:          c.TimedOut,
+		MedianReactionSeconds: median,
+	}
+}
+
+// GetStats ?days=N (기본 30일, 최대 90일)
+func (h *EndpointHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	id, err := endpointID(r.Context())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	days := endpoint.DefaultStatsDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		days, err = strconv.Atoi(v)
+		if err != nil {
+			wrapper.RespondError(w, common.ErrInvalidParam)
+			return
+		}
+	}
+
+	stats, err := h.service.Stats(r.Context(), id, days)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	daily := make([]resDailyStats, 0, len(stats.Daily))
+	for _, d := range stats.Daily {
+		daily = append(daily, resDailyStats{
+			Day:           d.Day.Format(time.DateOnly),
+			resStatsCount: toResStatsCount(d.StatsCount),
+		})
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, resStats{
+		Since:      stats.Since,
+		Total:      toResStatsCount(stats.Total),
+		Daily:      daily,
+		LastUsedAt: stats.LastUsedAt,
+	})
+}
+
+func (h *EndpointHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	id, err := endpointID(r.Context())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	members, err := h.service.ListMembers(r.Context(), id)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	result := make([]resMember, 0, len(members))
+	for _, m := range members {
+		result = append(result, resMember{
+			UserID:   m.UserID,
+			Role:     m.Role,
+			Email:    m.Email,
+			Guest:    m.Guest,
+			JoinedAt: m.JoinedAt,
+		})
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, result)
+}
+
+func (h *EndpointHandler) RemoveMember(ctx context.Context, _ interface{}) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	memberID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "userID"))
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+
+	if err := h.service.RemoveMember(ctx, id, memberID); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Subscribe 조직 endpoint를 초대 없이 구독 (조직 멤버만)
+func (h *EndpointHandler) Subscribe(ctx context.Context, _ interface{}) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.service.Subscribe(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+type reqTransferEndpoint struct {
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+// Transfer 개인 endpoint를 조직으로 옮긴다. 토큰과 sender key는 그대로 유지
+func (h *EndpointHandler) Transfer(ctx context.Context, req reqTransferEndpoint) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.OrgID == uuid.Nil {
+		return nil, common.ErrInvalidParam
+	}
+
+	if err := h.service.TransferToOrg(ctx, id, req.OrgID); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (h *EndpointHandler) Leave(ctx context.Context, _ interface{}) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := h.service.UpdateMute(ctx, token, true); err != nil {
+	if err := h.service.Leave(ctx, id); err != nil {
 		return nil, err
 	}
 
 	return nil, nil
 }
+
+type reqCreateInvite struct {
+	ExpiresIn int  `json:"expires_in"` // 초. 0이면 7일
+	MaxUses   *int `json:"max_uses"`   // 생략하면 횟수 제한 없음
+}
+
+type resInvite struct {
+	ID         uuid.UUID  `json:"id"`
+	CodePrefix string     `json:"code_prefix"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	MaxUses    *int       `json:"max_uses"`
+	UseCount   int        `json:"use_count"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// resCreateInvite 코드는 해시로 저장되므로 이 응답에서만 확인 가능
+type resCreateInvite struct {
+	resInvite
+	Code string `json:"code"`
+}
+
+func toResInvite(i *endpoint.Invite) resInvite {
+	return resInvite{
+		ID:         i.ID,
+		CodePrefix: i.CodePrefix,
+		ExpiresAt:  i.ExpiresAt,
+		MaxUses:    i.MaxUses,
+		UseCount:   i.UseCount,
+		RevokedAt:  i.RevokedAt,
+		CreatedAt:  i.CreatedAt,
+	}
+}
+
+func (h *EndpointHandler) GetInvites(w http.ResponseWriter, r *http.Request) {
+	id, err := endpointID(r.Context())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	invites, err := h.service.ListInvites(r.Context(), id)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	result := make([]resInvite, 0, len(invites))
+	for i := range invites {
+		result = append(result, toResInvite(&invites[i]))
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, result)
+}
+
+func (h *EndpointHandler) CreateInvite(ctx context.Context, req reqCreateInvite) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.service.CreateInvite(ctx, id, time.Duration(req.ExpiresIn)*time.Second, req.MaxUses)
+	if err != nil {
+		return nil, err
+	}
+
+	return resCreateInvite{
+		resInvite: toResInvite(&result.Invite),
+		Code:      result.Code,
+	}, nil
+}
+
+func (h *EndpointHandler) RevokeInvite(ctx context.Context, _ interface{}) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inviteID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "inviteID"))
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+
+	if err := h.service.RevokeInvite(ctx, id, inviteID); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+type reqJoin struct {
+	Code string `json:"code"`
+}
+
+type resJoin struct {
+	EndpointID   uuid.UUID `json:"endpoint_id"`
+	EndpointName string    `json:"endpoint_name"`
+}
+
+func (h *EndpointHandler) Join(ctx context.Context, req reqJoin) (interface{}, error) {
+	result, err := h.service.Join(ctx, strings.TrimSpace(req.Code))
+	if err != nil {
+		return nil, err
+	}
+
+	return resJoin{
+		EndpointID:   result.EndpointID,
+		EndpointName: result.EndpointName,
+	}, nil
+}
+
+func endpointID(ctx context.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(chi.URLParamFromCtx(ctx, "id"))
+	if err != nil {
+		return uuid.Nil, common.ErrInvalidParam
+	}
+	return id, nil
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"torchi/internal/api/wrapper"
+	"torchi/internal/domain/common"
+	"torchi/internal/domain/endpoint"
+	"torchi/internal/domain/notifications"
+	"torchi/internal/domain/sse"
+)
+
+// feedHeartbeat 프록시가 유휴 연결을 끊지 않도록
+const feedHeartbeat = 30 * time.Second
+
+// Stream GET /push/{token}/stream?format=sse|ndjson. read scope 키로 endpoint에 오는 발송과 응답을 실시간으로 받는다.
+// sse는 Last-Event-ID로 놓친 이벤트를 다시 받고, 다 받을 수 없으면 resync → 마지막 cursor로 poll
+func (h *ApiHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	e, err := h.authorizeEndpoint(r, endpointToken(r), endpoint.ScopeRead)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	ndjson := false
+	switch r.URL.Query().Get("format") {
+	case "", "sse":
+	case "ndjson":
+		ndjson = true
+	default:
+		wrapper.RespondError(w, common.ErrInvalidParam)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	if ndjson {
+		w.Header().Set("Content-Type", "application/x-ndjson; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "text/event-stream")
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch, missed := h.feedBroker.Subscribe(e.ID, r.Header.Get("Last-Event-ID"))
+	defer h.feedBroker.Unsubscribe(e.ID, ch)
+
+	send := func(format string, args ...any) bool {
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			h.log.Info("feed client disconnected while writing", "endpoint_id", e.ID)
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	// ndjson은 한 줄에 이벤트 하나. 종류는 data의 type (message, reaction, resync, keepalive)
+	sendEvent := func(event sse.SSEEvent) bool {
+		data := event.Data
+		if event.Event == sse.EventResync {
+			data = map[string]any{"type": sse.EventResync}
+		}
+		b, err := json.Marshal(data)
+		if err != nil {
+			h.log.Error("feed marshal error", "err", err)
+			return true
+		}
+		if ndjson {
+			return send("%s\n", b)
+		}
+		return send("id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Event, b)
+	}
+	heartbeat := func() bool {
+		if ndjson {
+			return send("{\"type\":\"keepalive\"}\n")
+		}
+		return send(": heartbeat\n\n")
+	}
+
+	if !ndjson && !send("event: connected\ndata: {}\n\n") {
+		return
+	}
+	for _, event := range missed {
+		if !sendEvent(event) {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(feedHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.feedBroker.Done():
+			return
+		case <-ticker.C:
+			if !heartbeat() {
+				return
+			}
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if !sendEvent(event) {
+				return
+			}
+		}
+	}
+}
+
+type resFeed struct {
+	Events  []map[string]any `json:"events"`
+	Next    string           `json:"next"`     // 다음 요청의 since
+	HasMore bool             `json:"has_more"` // true면 next로 바로 다시 요청
+}
+
+// Poll GET /push/{token}/poll?since=&limit=. since는 이전 응답의 next나 이벤트의 cursor, 또는 "1h" 같은 기간.
+// 없으면 남아 있는 처음부터. 이벤트 항목은 stream과 같다
+func (h *ApiHandler) Poll(w http.ResponseWriter, r *http.Request) {
+	e, err := h.authorizeEndpoint(r, endpointToken(r), endpoint.ScopeRead)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	params := r.URL.Query()
+	since, err := notifications.ParseFeedSince(params.Get("since"), time.Now())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	limit := 100
+	if l, err := strconv.Atoi(params.Get("limit")); err == nil && l > 0 {
+		limit = min(l, 500)
+	}
+
+	page, err := h.notiService.Feed(r.Context(), e.ID, since, int32(limit))
+	if err != nil {
+		h.log.Error("feed poll", "endpoint_id", e.ID, "err", err)
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	events := make([]map[string]any, len(page.Events))
+	for i, event := range page.Events {
+		events[i] = event.Data()
+	}
+	wrapper.RespondJSON(w, http.StatusOK, resFeed{
+		Events:  events,
+		Next:    page.Next.Encode(),
+		HasMore: page.HasMore,
+	})
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 	"torchi/internal/api/wrapper"
@@ -39,8 +41,19 @@ func NewNotiHandler(
 func (h *NotiHandler) Routes() chi.Router {
 	r := chi.NewRouter()
 	r.Get("/", h.GetList)
+	r.Get("/search", h.Search)
+	r.Get("/export", h.Export)
+	r.Get("/changes", h.Changes)
+	r.Get("/unread-count", h.UnreadCount)
+	r.Get("/trash", h.GetTrash)
+	r.Delete("/trash", h.PurgeTrash)
+	r.Post("/bulk", wrapper.WrapJson(h.Bulk, h.log.Error))
+	r.Get("/{id}", h.Get)
 	r.Post("/read-until", wrapper.WrapJson(h.Read, h.log.Error))
+	r.Post("/{id}/read", wrapper.WrapJson(h.MarkRead, h.log.Error))
+	r.Post("/{id}/unread", wrapper.WrapJson(h.MarkUnread, h.log.Error))
 	r.Delete("/{id}", wrapper.WrapJson(h.Delete, h.log.Error))
+	r.Get("/{id}/reactions", h.GetReactions)
 
 	return r
 }
@@ -61,14 +74,140 @@ func (h *NotiHandler) Delete(ctx context.Context, _ interface{}) (interface{}, e
 		return nil, err
 	}
 
-	if ch, ok := h.waitMap.Get(parsed.String()); ok {
-		ch <- push.WaitResult{Deleted: true}
+	// 대기 중인 ask는 모든 멤버가 지웠을 때만 끝낸다
+	noti, err := h.service.FindByID(ctx, parsed)
+	if err != nil {
+		return nil, err
+	}
+	if noti == nil || noti.UserID != userClaim.UserID {
+		return nil, nil
+	}
+	if err := h.releaseAbandoned(ctx, noti.MessageID); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
 
 }
 
+// releaseAbandoned 모든 멤버가 지운 발송이면 대기 중인 ask를 끝낸다
+func (h *NotiHandler) releaseAbandoned(ctx context.Context, messageID uuid.UUID) error {
+	if _, ok := h.waitMap.Get(messageID.String()); !ok {
+		return nil
+	}
+
+	abandoned, err := h.service.IsAbandoned(ctx, messageID)
+	if err != nil {
+		return err
+	}
+	if abandoned {
+		h.waitMap.Deliver(messageID.String(), push.WaitResult{Deleted: true})
+	}
+	return nil
+}
+
+func (h *NotiHandler) MarkRead(ctx context.Context, _ interface{}) (interface{}, error) {
+	return nil, h.setRead(ctx, true)
+}
+
+func (h *NotiHandler) MarkUnread(ctx context.Context, _ interface{}) (interface{}, error) {
+	return nil, h.setRead(ctx, false)
+}
+
+func (h *NotiHandler) setRead(ctx context.Context, read bool) error {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(chi.URLParamFromCtx(ctx, "id"))
+	if err != nil {
+		return common.ErrInvalidParam
+	}
+
+	return h.service.SetRead(ctx, userClaim.UserID, id, read)
+}
+
+// reqBulkFilter 목록 조회 쿼리 파라미터와 같은 의미
+type reqBulkFilter struct {
+	EndpointID *uuid.UUID `json:"endpoint_id"`
+	Query      string     `json:"query"`
+	Status     []string   `json:"status"`
+	Unread     bool       `json:"unread"`
+	HasActions bool       `json:"has_actions"`
+	Pending    bool       `json:"pending"`
+	From       string     `json:"from"`
+	To         string     `json:"to"`
+	Reaction   string     `json:"reaction"`
+}
+
+type reqBulkNoti struct {
+	Action string         `json:"action"` // read, unread, delete, restore
+	IDs    []uuid.UUID    `json:"ids"`    // ids와 filter 중 하나만
+	Filter *reqBulkFilter `json:"filter"`
+}
+
+type resBulkNoti struct {
+	Affected int64 `json:"affected"`
+}
+
+// Bulk ID 목록 또는 필터로 여러 알림을 한 번에 읽음, 안 읽음, 삭제, 복원
+func (h *NotiHandler) Bulk(ctx context.Context, req reqBulkNoti) (interface{}, error) {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := notifications.BulkTarget{IDs: req.IDs}
+	if req.Filter != nil {
+		filter, err := req.Filter.toListFilter()
+		if err != nil {
+			return nil, err
+		}
+		target.Filter = &filter
+	}
+
+	result, err := h.service.Bulk(ctx, userClaim.UserID, notifications.BulkAction(req.Action), target)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, messageID := range result.MessageIDs {
+		if err := h.releaseAbandoned(ctx, messageID); err != nil {
+			return nil, err
+		}
+	}
+
+	return resBulkNoti{Affected: result.Affected}, nil
+}
+
+func (f *reqBulkFilter) toListFilter() (notifications.ListFilter, error) {
+	var filter notifications.ListFilter
+	var err error
+
+	if filter.Statuses, err = notifications.ParseStatuses(f.Status); err != nil {
+		return filter, err
+	}
+	if filter.CreatedFrom, err = parseTimeParam(f.From, false); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = parseTimeParam(f.To, true); err != nil {
+		return filter, err
+	}
+
+	if f.Query != "" {
+		filter.Query = &f.Query
+	}
+	if f.Reaction != "" {
+		filter.Reaction = &f.Reaction
+	}
+	filter.EndpointID = f.EndpointID
+	filter.UnreadOnly = f.Unread
+	filter.HasActions = f.HasActions
+	filter.PendingAsks = f.Pending
+	return filter, nil
+}
+
 type reqReadNoti struct {
 	LastID     uuid.UUID  `json:"last_id"`
 	EndpointID *uuid.UUID `json:"endpoint_id"`
@@ -101,6 +240,34 @@ type resNoti struct {
 	Reaction     *string    `json:"reaction"`
 	ReactionAt   *time.Time `json:"reaction_at"`
 	Status       string     `json:"status"`
+	Sensitive    bool       `json:"sensitive"`
+
+	ReactionComment *string `json:"reaction_comment"`
+
+	SenderKeyID    *uuid.UUID `json:"sender_key_id"`
+	SenderKeyLabel *string    `json:"sender_key_label"`
+}
+
+func toResNoti(noti *notifications.Noti) resNoti {
+	return resNoti{
+		ID:           noti.ID,
+		EndpointID:   noti.EndpointID,
+		EndpointName: noti.EndpointName,
+		Body:         noti.Body,
+		IsRead:       bool(noti.ReadAt != nil),
+		CreatedAt:    noti.CreatedAt,
+		Mute:         noti.IsMute(),
+		Actions:      noti.Actions,
+		Reaction:     noti.Reaction,
+		ReactionAt:   noti.ReactionAt,
+		Status:       string(noti.Status),
+		Sensitive:    noti.Sensitive,
+
+		ReactionComment: noti.ReactionComment,
+
+		SenderKeyID:    noti.SenderKeyID,
+		SenderKeyLabel: noti.SenderKeyLabel,
+	}
 }
 
 // 무한 스크롤 전용 응답 컨테이너
@@ -147,7 +314,16 @@ func (h *NotiHandler) GetList(w http.ResponseWriter, r *http.Request) {
 		limit = min(l, 100) // 최대치 제한
 	}
 
-	notis, err := h.service.GetListWithCursor(ctx, userClaim.UserID, lastID, int32(limit), endpointID, query)
+	// 2. 필터 파싱
+	filter, err := parseListFilter(r.URL.Query())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+	filter.EndpointID = endpointID
+	filter.Query = query
+
+	notis, err := h.service.GetListWithCursor(ctx, userClaim.UserID, lastID, int32(limit), filter)
 	if err != nil {
 		wrapper.RespondError(w, err)
 		return
@@ -155,20 +331,8 @@ func (h *NotiHandler) GetList(w http.ResponseWriter, r *http.Request) {
 
 	// 3. DTO 매핑
 	items := make([]resNoti, len(notis))
-	for i, noti := range notis {
-		items[i] = resNoti{
-			ID:           noti.ID,
-			EndpointID:   noti.EndpointID,
-			EndpointName: noti.EndpointName,
-			Body:         noti.Body,
-			IsRead:       bool(noti.ReadAt != nil),
-			CreatedAt:    noti.CreatedAt,
-			Mute:         noti.IsMute(),
-			Actions:      noti.Actions,
-			Reaction:     noti.Reaction,
-			ReactionAt:   noti.ReactionAt,
-			Status:       string(noti.Status),
-		}
+	for i := range notis {
+		items[i] = toResNoti(&notis[i])
 	}
 
 	// 4. 다음 커서 결정
@@ -186,3 +350,499 @@ func (h *NotiHandler) GetList(w http.ResponseWriter, r *http.Request) {
 		HasMore:    hasMore,
 	})
 }
+
+type resChange struct {
+	Type         string    `json:"type"` // created, updated, deleted
+	ID           uuid.UUID `json:"id"`
+	Notification *resNoti  `json:"notification"` // deleted면 null
+}
+
+type resChanges struct {
+	Changes   []resChange `json:"changes"`
+	NextToken string      `json:"next_token"` // 다음 요청의 since
+	HasMore   bool        `json:"has_more"`   // true면 next_token으로 바로 다시 요청
+}
+
+// Changes ?since=&limit= 변경 피드. since가 없으면 변경 없이 지금 시점의 next_token만 준다 (목록을 받기 전에 먼저 호출).
+// created, updated는 그대로 덮어쓰고 deleted는 지운다. 410 SYNC_TOKEN_EXPIRED면 목록부터 다시 받는다
+func (h *NotiHandler) Changes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		wrapper.RespondError(w, common.ErrUnauthorized)
+		return
+	}
+
+	params := r.URL.Query()
+
+	var since *notifications.SyncToken
+	if v := params.Get("since"); v != "" {
+		since, err = notifications.DecodeSyncToken(v)
+		if err != nil {
+			wrapper.RespondError(w, err)
+			return
+		}
+	}
+
+	limit := 100
+	if l, err := strconv.Atoi(params.Get("limit")); err == nil && l > 0 {
+		limit = min(l, 500)
+	}
+
+	set, err := h.service.Changes(ctx, userClaim.UserID, since, int32(limit))
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	changes := make([]resChange, len(set.Changes))
+	for i, c := range set.Changes {
+		changes[i] = resChange{Type: string(c.Type), ID: c.ID}
+		if c.Noti != nil {
+			noti := toResNoti(c.Noti)
+			changes[i].Notification = &noti
+		}
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, resChanges{
+		Changes:   changes,
+		NextToken: set.Next.Encode(),
+		HasMore:   set.HasMore,
+	})
+}
+
+const (
+	// exportFlushEvery 이 건수마다 클라이언트로 내보낸다
+	exportFlushEvery = 500
+	// exportWriteTimeout 한 번 내보낼 때 기다리는 최대 시간. 읽지 않는 클라이언트가 DB 연결을 계속 쥐고 있지 않게 한다
+	exportWriteTimeout = 30 * time.Second
+)
+
+// Export ?format=csv|json|ndjson 와 목록 조회와 같은 필터. 전체 이력을 DB에서 한 건씩 읽어 바로 내려보낸다
+func (h *NotiHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		wrapper.RespondError(w, common.ErrUnauthorized)
+		return
+	}
+
+	params := r.URL.Query()
+	format, err := notifications.ParseExportFormat(params.Get("format"))
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	filter, err := parseListFilter(params)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+	if v := params.Get("endpoint_id"); v != "" {
+		if parsed, err := uuid.Parse(v); err == nil {
+			filter.EndpointID = &parsed
+		}
+	}
+	if v := params.Get("query"); v != "" {
+		filter.Query = &v
+	}
+
+	rc := http.NewResponseController(w)
+	out := notifications.NewExportWriter(format, w)
+	started := false
+	count := 0
+
+	if err := extendWriteDeadline(rc); err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+	err = h.service.Export(ctx, userClaim.UserID, filter, func(noti *notifications.Noti) error {
+		if !started {
+			writeExportHeader(w, format)
+			started = true
+		}
+		if err := out.Write(noti); err != nil {
+			return err
+		}
+
+		count++
+		if count%exportFlushEvery == 0 {
+			if err := out.Flush(); err != nil {
+				return err
+			}
+			if err := flushResponse(rc); err != nil {
+				return err
+			}
+			// 내보낸 만큼은 진행된 것이므로 다음 묶음에 다시 시간을 준다
+			if err := extendWriteDeadline(rc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// 이미 보내기 시작했으면 상태 코드를 바꿀 수 없다. 잘린 응답은 클라이언트가 형식으로 알아챈다
+		if !started {
+			wrapper.RespondError(w, err)
+			return
+		}
+		h.log.Error("notification export aborted", "userID", userClaim.UserID, "rows", count, "err", err)
+		return
+	}
+
+	if !started {
+		writeExportHeader(w, format)
+	}
+	if err := out.Close(); err != nil {
+		h.log.Error("notification export close", "userID", userClaim.UserID, "err", err)
+	}
+}
+
+// extendWriteDeadline 지금부터 exportWriteTimeout 안에 써야 한다. 지원하지 않는 ResponseWriter면 무시
+func extendWriteDeadline(rc *http.ResponseController) error {
+	err := rc.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
+
+func flushResponse(rc *http.ResponseController) error {
+	err := rc.Flush()
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
+
+func writeExportHeader(w http.ResponseWriter, format notifications.ExportFormat) {
+	filename := "notifications-" + time.Now().Format("20060102") + "." + string(format)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
+
+type resTrashNoti struct {
+	resNoti
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type resTrashList struct {
+	Items      []resTrashNoti `json:"items"`
+	NextCursor *uuid.UUID     `json:"next_cursor"`
+	HasMore    bool           `json:"has_more"`
+}
+
+// GetTrash 휴지통 목록 (?cursor=&limit=)
+func (h *NotiHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		wrapper.RespondError(w, common.ErrUnauthorized)
+		return
+	}
+
+	var lastID *uuid.UUID
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		if parsed, err := uuid.Parse(v); err == nil {
+			lastID = &parsed
+		}
+	}
+
+	limit := 20
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = min(l, 100)
+	}
+
+	notis, err := h.service.GetTrash(ctx, userClaim.UserID, lastID, int32(limit))
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	items := make([]resTrashNoti, len(notis))
+	for i := range notis {
+		items[i] = resTrashNoti{
+			resNoti:   toResNoti(&notis[i]),
+			DeletedAt: notis[i].DeletedAt,
+		}
+	}
+
+	var nextCursor *uuid.UUID
+	hasMore := false
+	if len(items) > 0 && len(items) == limit {
+		nextCursor = &items[len(items)-1].ID
+		hasMore = true
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, resTrashList{
+		Items:      items,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+	})
+}
+
+type resPurgeTrash struct {
+	Purged int64 `json:"purged"`
+}
+
+// PurgeTrash ?older_than_days=N 보다 오래전에 지운 알림을 영구 삭제. 생략하면 휴지통 비우기
+func (h *NotiHandler) PurgeTrash(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		wrapper.RespondError(w, common.ErrUnauthorized)
+		return
+	}
+
+	days := 0
+	if v := r.URL.Query().Get("older_than_days"); v != "" {
+		if days, err = strconv.Atoi(v); err != nil {
+			wrapper.RespondError(w, common.ErrInvalidParam)
+			return
+		}
+	}
+
+	purged, err := h.service.PurgeTrash(ctx, userClaim.UserID, days)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, resPurgeTrash{Purged: purged})
+}
+
+// parseListFilter ?status=sent,mute&unread=true&has_actions=true&pending=true&from=&to=&reaction=
+// from, to는 RFC3339 또는 YYYY-MM-DD. 날짜만 주면 to는 그 날 전체를 포함한다
+func parseListFilter(params url.Values) (notifications.ListFilter, error) {
+	var filter notifications.ListFilter
+
+	statuses, err := notifications.ParseStatuses(params["status"])
+	if err != nil {
+		return filter, err
+	}
+	filter.Statuses = statuses
+
+	for key, dst := range map[string]*bool{
+		"unread":      &filter.UnreadOnly,
+		"has_actions": &filter.HasActions,
+		"pending":     &filter.PendingAsks,
+	} {
+		if v := params.Get(key); v != "" {
+			if *dst, err = strconv.ParseBool(v); err != nil {
+				return filter, common.ErrInvalidParam
+			}
+		}
+	}
+
+	if filter.CreatedFrom, err = parseTimeParam(params.Get("from"), false); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = parseTimeParam(params.Get("to"), true); err != nil {
+		return filter, err
+	}
+
+	if v := params.Get("reaction"); v != "" {
+		filter.Reaction = &v
+	}
+	return filter, nil
+}
+
+// parseTimeParam endOfDay면 날짜만 준 경우 다음 날 0시를 반환. 날짜만 주면 UTC 기준.
+// created_at은 시간대 없는 timestamp이고 pgx는 시간대를 버리고 시각만 보내므로 UTC로 맞춰서 반환한다
+func parseTimeParam(v string, endOfDay bool) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		t = t.UTC()
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// Get 알림 하나. 삭제한 알림이면 410
+func (h *NotiHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		wrapper.RespondError(w, common.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParamFromCtx(ctx, "id"))
+	if err != nil {
+		wrapper.RespondError(w, common.ErrInvalidParam)
+		return
+	}
+
+	noti, err := h.service.Get(ctx, userClaim.UserID, id)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, toResNoti(noti))
+}
+
+type resSearchNoti struct {
+	resNoti
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"` // HTML 이스케이프됨. 일치한 부분은 <mark>로 감쌈
+}
+
+type resSearchList struct {
+	Items      []resSearchNoti `json:"items"`
+	NextCursor *string         `json:"next_cursor"` // 불투명한 문자열. 그대로 cursor에 넘긴다
+	HasMore    bool            `json:"has_more"`
+}
+
+// Search ?q=&endpoint_id=&cursor=&limit=. 관련도순 정렬
+func (h *NotiHandler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		wrapper.RespondError(w, common.ErrUnauthorized)
+		return
+	}
+
+	params := r.URL.Query()
+
+	var endpointID *uuid.UUID
+	if v := params.Get("endpoint_id"); v != "" {
+		if parsed, err := uuid.Parse(v); err == nil {
+			endpointID = &parsed
+		}
+	}
+
+	var cursor *notifications.SearchCursor
+	if v := params.Get("cursor"); v != "" {
+		cursor, err = notifications.DecodeSearchCursor(v)
+		if err != nil {
+			wrapper.RespondError(w, err)
+			return
+		}
+	}
+
+	limit := 20
+	if l, err := strconv.Atoi(params.Get("limit")); err == nil && l > 0 {
+		limit = min(l, 100)
+	}
+
+	results, err := h.service.Search(ctx, userClaim.UserID, params.Get("q"), endpointID, cursor, int32(limit))
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	items := make([]resSearchNoti, len(results))
+	for i := range results {
+		items[i] = resSearchNoti{
+			resNoti: toResNoti(&results[i].Noti),
+			Rank:    results[i].Rank,
+			Snippet: results[i].Snippet,
+		}
+	}
+
+	var nextCursor *string
+	hasMore := len(results) > 0 && len(results) == limit
+	if hasMore {
+		last := results[len(results)-1]
+		c := notifications.SearchCursor{Rank: last.Rank, ID: last.ID}.Encode()
+		nextCursor = &c
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, resSearchList{
+		Items:      items,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+	})
+}
+
+type resReaction struct {
+	ID          uuid.UUID  `json:"id"`
+	Reaction    string     `json:"reaction"`
+	Comment     *string    `json:"comment"`
+	UserID      *uuid.UUID `json:"user_id"`
+	PushTokenID *uuid.UUID `json:"push_token_id"`
+	IP          *string    `json:"ip"`
+	UserAgent   *string    `json:"user_agent"`
+	AuthMethod  *string    `json:"auth_method"`
+	AuthAt      *time.Time `json:"auth_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type resUnreadCount struct {
+	Total     int64               `json:"total"`
+	Endpoints map[uuid.UUID]int64 `json:"endpoints"` // 읽지 않은 알림이 있는 endpoint만
+}
+
+// UnreadCount 목록을 넘기지 않고 배지에 쓸 읽지 않은 수만 조회
+func (h *NotiHandler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		wrapper.RespondError(w, common.ErrUnauthorized)
+		return
+	}
+
+	counts, err := h.service.UnreadCounts(ctx, userClaim.UserID)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, resUnreadCount{
+		Total:     counts.Total,
+		Endpoints: counts.ByEndpoint,
+	})
+}
+
+// GetReactions 알림의 리액션 이력 (덮어쓰인 리액션 포함)
+func (h *NotiHandler) GetReactions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		wrapper.RespondError(w, common.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParamFromCtx(ctx, "id"))
+	if err != nil {
+		wrapper.RespondError(w, common.ErrInvalidParam)
+		return
+	}
+
+	reactions, err := h.service.FindReactions(ctx, userClaim.UserID, id)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	items := make([]resReaction, len(reactions))
+	for i, reaction := range reactions {
+		items[i] = resReaction{
+			ID:          reaction.ID,
+			Reaction:    reaction.Reaction,
+			Comment:     reaction.Comment,
+			UserID:      reaction.UserID,
+			PushTokenID: reaction.PushTokenID,
+			IP:          reaction.IP,
+			UserAgent:   reaction.UserAgent,
+			AuthMethod:  reaction.AuthMethod,
+			AuthAt:      reaction.AuthAt,
+			CreatedAt:   reaction.CreatedAt,
+		}
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, items)
+}
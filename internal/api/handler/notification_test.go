@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"torchi/internal/domain/common"
+)
+
+func TestParseTimeParam(t *testing.T) {
+	cases := []struct {
+		name     string
+		v        string
+		endOfDay bool
+		want     time.Time
+	}{
+		{"utc", "2026-03-01T10:00:00Z", false, time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{"offset is normalized", "2026-03-01T10:00:00+09:00", false, time.Date(2026, 3, 1, 1, 0, 0, 0, time.UTC)},
+		{"timestamp ignores end of day", "2026-03-01T20:00:00-05:00", true, time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)},
+		{"date", "2026-03-01", false, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"date end of day", "2026-03-01", true, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := parseTimeParam(c.v, c.endOfDay)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			// pgx는 시각만 보내므로 Equal이 아니라 위치까지 UTC여야 한다
+			if *got != c.want {
+				t.Errorf("expected: %v, got: %v", c.want, *got)
+			}
+		})
+	}
+
+	if got, err := parseTimeParam("", false); got != nil || err != nil {
+		t.Errorf("empty expected nil, got: %v %v", got, err)
+	}
+	if _, err := parseTimeParam("yesterday", false); !errors.Is(err, common.ErrInvalidParam) {
+		t.Errorf("expected: %v, got: %v", common.ErrInvalidParam, err)
+	}
+}
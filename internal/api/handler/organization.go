@@ -0,0 +1,360 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+	"torchi/internal/api/wrapper"
+	"torchi/internal/domain/common"
+	"torchi/internal/domain/endpoint"
+	"torchi/internal/domain/organization"
+	"torchi/internal/pkg/log"
+	"torchi/internal/pkg/token"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type OrganizationHandler struct {
+	log             *log.Logger
+	service         *organization.OrganizationService
+	endpointService *endpoint.EndpointService
+}
+
+func NewOrganizationHandler(
+	log *log.Logger,
+	service *organization.OrganizationService,
+	endpointService *endpoint.EndpointService,
+) *OrganizationHandler {
+	return &OrganizationHandler{
+		log:             log,
+		service:         service,
+		endpointService: endpointService,
+	}
+}
+
+func (h *OrganizationHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Post("/", wrapper.WrapJson(h.Create, h.log.Error))
+	r.Get("/", h.GetList)
+	r.Get("/invites", h.GetMyInvites)
+	r.Post("/invites/{inviteID}/accept", wrapper.WrapJson(h.AcceptInvite, h.log.Error))
+	r.Delete("/invites/{inviteID}", wrapper.WrapJson(h.DeclineInvite, h.log.Error))
+	r.Delete("/{id}", wrapper.WrapJson(h.Delete, h.log.Error))
+	r.Get("/{id}/members", h.GetMembers)
+	r.Post("/{id}/invites", wrapper.WrapJson(h.Invite, h.log.Error))
+	r.Get("/{id}/invites", h.GetInvites)
+	r.Delete("/{id}/invites/{inviteID}", wrapper.WrapJson(h.RevokeInvite, h.log.Error))
+	r.Patch("/{id}/members/{userID}", wrapper.WrapJson(h.UpdateMemberRole, h.log.Error))
+	r.Delete("/{id}/members/me", wrapper.WrapJson(h.Leave, h.log.Error))
+	r.Delete("/{id}/members/{userID}", wrapper.WrapJson(h.RemoveMember, h.log.Error))
+	r.Get("/{id}/endpoints", h.GetEndpoints)
+	return r
+}
+
+type reqCreateOrganization struct {
+	Name string `json:"name"`
+}
+
+type resCreateOrganization struct {
+	ID uuid.UUID `json:"id"`
+}
+
+func (h *OrganizationHandler) Create(ctx context.Context, req reqCreateOrganization) (interface{}, error) {
+	id, err := h.service.Create(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return resCreateOrganization{ID: id}, nil
+}
+
+type resOrganization struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"` // 내 역할. owner, admin, member
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (h *OrganizationHandler) GetList(w http.ResponseWriter, r *http.Request) {
+	orgs, err := h.service.List(r.Context())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	result := make([]resOrganization, 0, len(orgs))
+	for _, o := range orgs {
+		result = append(result, resOrganization{
+			ID:        o.ID,
+			Name:      o.Name,
+			Role:      o.Role,
+			CreatedAt: o.CreatedAt,
+		})
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, result)
+}
+
+// Delete 조직 endpoint도 함께 삭제된다 (owner만)
+func (h *OrganizationHandler) Delete(ctx context.Context, _ interface{}) (interface{}, error) {
+	id, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (h *OrganizationHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	id, err := orgID(r.Context())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	members, err := h.service.ListMembers(r.Context(), id)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	result := make([]resMember, 0, len(members))
+	for _, m := range members {
+		result = append(result, resMember{
+			UserID:   m.UserID,
+			Role:     m.Role,
+			Email:    m.Email,
+			Guest:    m.Guest,
+			JoinedAt: m.JoinedAt,
+		})
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, result)
+}
+
+type reqInviteOrganizationMember struct {
+	Email string `json:"email"`
+	Role  string `json:"role"` // 생략하면 member
+}
+
+// Invite 가입 여부와 관계없이 같은 응답. 그 이메일로 가입한 유저가 수락해야 멤버가 된다
+func (h *OrganizationHandler) Invite(ctx context.Context, req reqInviteOrganizationMember) (interface{}, error) {
+	id, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	role := req.Role
+	if role == "" {
+		role = organization.RoleMember
+	}
+
+	if err := h.service.Invite(ctx, id, req.Email, role); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+type resOrganizationInvite struct {
+	ID        uuid.UUID  `json:"id"`
+	Email     string     `json:"email"`
+	Role      string     `json:"role"`
+	InvitedBy *uuid.UUID `json:"invited_by"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// GetInvites 수락하지 않은 초대 (owner/admin)
+func (h *OrganizationHandler) GetInvites(w http.ResponseWriter, r *http.Request) {
+	id, err := orgID(r.Context())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	invites, err := h.service.ListInvites(r.Context(), id)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	result := make([]resOrganizationInvite, 0, len(invites))
+	for _, i := range invites {
+		result = append(result, resOrganizationInvite{
+			ID:        i.ID,
+			Email:     i.Email,
+			Role:      i.Role,
+			InvitedBy: i.InvitedBy,
+			ExpiresAt: i.ExpiresAt,
+			CreatedAt: i.CreatedAt,
+		})
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, result)
+}
+
+func (h *OrganizationHandler) RevokeInvite(ctx context.Context, _ interface{}) (interface{}, error) {
+	id, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inviteID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "inviteID"))
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+
+	if err := h.service.RevokeInvite(ctx, id, inviteID); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+type resMyOrganizationInvite struct {
+	ID        uuid.UUID `json:"id"`
+	OrgID     uuid.UUID `json:"org_id"`
+	OrgName   string    `json:"org_name"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetMyInvites 내 이메일로 온 초대
+func (h *OrganizationHandler) GetMyInvites(w http.ResponseWriter, r *http.Request) {
+	invites, err := h.service.ListMyInvites(r.Context())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	result := make([]resMyOrganizationInvite, 0, len(invites))
+	for _, i := range invites {
+		result = append(result, resMyOrganizationInvite{
+			ID:        i.ID,
+			OrgID:     i.OrgID,
+			OrgName:   i.OrgName,
+			Role:      i.Role,
+			ExpiresAt: i.ExpiresAt,
+			CreatedAt: i.CreatedAt,
+		})
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, result)
+}
+
+func (h *OrganizationHandler) AcceptInvite(ctx context.Context, _ interface{}) (interface{}, error) {
+	inviteID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "inviteID"))
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+
+	if err := h.service.AcceptInvite(ctx, inviteID); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (h *OrganizationHandler) DeclineInvite(ctx context.Context, _ interface{}) (interface{}, error) {
+	inviteID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "inviteID"))
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+
+	if err := h.service.DeclineInvite(ctx, inviteID); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+type reqUpdateOrganizationMember struct {
+	Role string `json:"role"`
+}
+
+func (h *OrganizationHandler) UpdateMemberRole(ctx context.Context, req reqUpdateOrganizationMember) (interface{}, error) {
+	id, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	memberID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "userID"))
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+
+	if err := h.service.UpdateMemberRole(ctx, id, memberID, req.Role); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (h *OrganizationHandler) RemoveMember(ctx context.Context, _ interface{}) (interface{}, error) {
+	id, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	memberID, err := uuid.Parse(chi.URLParamFromCtx(ctx, "userID"))
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+
+	if err := h.service.RemoveMember(ctx, id, memberID); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (h *OrganizationHandler) Leave(ctx context.Context, _ interface{}) (interface{}, error) {
+	id, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.service.RemoveMember(ctx, id, userClaim.UserID); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// GetEndpoints 조직의 모든 endpoint. role이 빈 문자열이면 아직 알림을 받지 않는 endpoint
+func (h *OrganizationHandler) GetEndpoints(w http.ResponseWriter, r *http.Request) {
+	id, err := orgID(r.Context())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	endpoints, err := h.endpointService.ListByOrg(r.Context(), id)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	result := make([]resListEndpoint, 0, len(endpoints))
+	for i := range endpoints {
+		result = append(result, toResListEndpoint(&endpoints[i]))
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, result)
+}
+
+func orgID(ctx context.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(chi.URLParamFromCtx(ctx, "id"))
+	if err != nil {
+		return uuid.Nil, common.ErrInvalidParam
+	}
+	return id, nil
+}
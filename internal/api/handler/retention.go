@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+	"torchi/internal/api/wrapper"
+	"torchi/internal/domain/retention"
+	"torchi/internal/pkg/log"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type RetentionHandler struct {
+	log     *log.Logger
+	service *retention.RetentionService
+}
+
+func NewRetentionHandler(log *log.Logger, service *retention.RetentionService) *RetentionHandler {
+	return &RetentionHandler{
+		log:     log,
+		service: service,
+	}
+}
+
+func (h *RetentionHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetUserPolicy)
+	r.Put("/", wrapper.WrapJson(h.UpdateUserPolicy, h.log.Error))
+	r.Get("/endpoints/{id}", h.GetEndpointPolicy)
+	r.Put("/endpoints/{id}", wrapper.WrapJson(h.UpdateEndpointPolicy, h.log.Error))
+	return r
+}
+
+// reqRetention null이면 제한 없음. 둘 다 null이면 설정을 지운다
+type reqRetention struct {
+	KeepDays  *int `json:"keep_days"`
+	KeepCount *int `json:"keep_count"`
+}
+
+type resRetention struct {
+	KeepDays  *int       `json:"keep_days"`
+	KeepCount *int       `json:"keep_count"`
+	MaxDays   int        `json:"max_days"` // 서버 최대 보관 일수. 정책과 관계없이 적용, 0이면 없음
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+func (h *RetentionHandler) toResRetention(p retention.Policy) resRetention {
+	return resRetention{
+		KeepDays:  p.KeepDays,
+		KeepCount: p.KeepCount,
+		MaxDays:   h.service.MaxDays(),
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+// GetUserPolicy 내 알림 전체에 적용되는 보관 정책
+func (h *RetentionHandler) GetUserPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := h.service.UserPolicy(r.Context())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, h.toResRetention(policy))
+}
+
+func (h *RetentionHandler) UpdateUserPolicy(ctx context.Context, req reqRetention) (interface{}, error) {
+	return nil, h.service.UpdateUserPolicy(ctx, retention.Policy{
+		KeepDays:  req.KeepDays,
+		KeepCount: req.KeepCount,
+	})
+}
+
+// GetEndpointPolicy endpoint 멤버 모두에게 적용되는 보관 정책. keep_count는 멤버별 개수
+func (h *RetentionHandler) GetEndpointPolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := endpointID(r.Context())
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	policy, err := h.service.EndpointPolicy(r.Context(), id)
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
+	wrapper.RespondJSON(w, http.StatusOK, h.toResRetention(policy))
+}
+
+func (h *RetentionHandler) UpdateEndpointPolicy(ctx context.Context, req reqRetention) (interface{}, error) {
+	id, err := endpointID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, h.service.UpdateEndpointPolicy(ctx, id, retention.Policy{
+		KeepDays:  req.KeepDays,
+		KeepCount: req.KeepCount,
+	})
+}
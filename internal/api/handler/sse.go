@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+	"torchi/internal/api/wrapper"
 	"torchi/internal/domain/sse"
 	"torchi/internal/pkg/log"
 	"torchi/internal/pkg/token"
@@ -33,6 +34,14 @@ func (h *SSEHandler) Stream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// ?events=read,deleted&endpoint_id=... 로 받을 이벤트를 좁힌다
+	query := r.URL.Query()
+	filter, err := sse.ParseFilter(query["events"], query["endpoint_id"])
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		http.Error(w, "streaming not supported", http.StatusInternalServerError)
@@ -41,7 +50,8 @@ func (h *SSEHandler) Stream(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	ch := h.broker.Subscribe(userClaim.UserID)
+	// 브라우저가 재연결할 때 마지막으로 받은 이벤트 ID를 보낸다
+	ch, missed := h.broker.Subscribe(userClaim.UserID, r.Header.Get("Last-Event-ID"))
 	defer func() {
 		h.broker.Unsubscribe(userClaim.UserID, ch)
 	}()
@@ -57,9 +67,26 @@ func (h *SSEHandler) Stream(w http.ResponseWriter, r *http.Request) {
 		return true
 	}
 
+	sendEvent := func(event sse.SSEEvent) bool {
+		if !filter.Match(event) {
+			return true
+		}
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			h.log.Error("sse marshal error", "err", err)
+			return true
+		}
+		return send("id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Event, string(data))
+	}
+
 	if !send("event: connected\ndata: {}\n\n") {
 		return
 	}
+	for _, event := range missed {
+		if !sendEvent(event) {
+			return
+		}
+	}
 
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
@@ -76,15 +103,11 @@ func (h *SSEHandler) Stream(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 		case event, ok := <-ch:
+			// 버퍼가 넘쳐 끊긴 경우에도 닫힌다. 클라이언트는 Last-Event-ID로 다시 연결한다
 			if !ok {
 				return
 			}
-			data, err := json.Marshal(event.Data)
-			if err != nil {
-				h.log.Error("sse marshal error", "err", err)
-				continue
-			}
-			if !send("event: %s\ndata: %s\n\n", event.Event, string(data)) {
+			if !sendEvent(event) {
 				return
 			}
 		}
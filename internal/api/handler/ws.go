@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"torchi/internal/api/wrapper"
+	"torchi/internal/domain/common"
+	"torchi/internal/domain/notifications"
+	"torchi/internal/domain/push"
+	"torchi/internal/domain/sse"
+	"torchi/internal/pkg/config"
+	"torchi/internal/pkg/log"
+	"torchi/internal/pkg/token"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/google/uuid"
+)
+
+const (
+	wsPingInterval = 30 * time.Second
+	wsPingTimeout  = 10 * time.Second
+	wsWriteTimeout = 10 * time.Second
+	wsReadLimit    = 4096
+	// wsReplyBuffer 응답을 못 가져가면 명령 읽기도 멈춘다
+	wsReplyBuffer = 8
+)
+
+// WSHandler SSE와 같은 브로커 구독을 WebSocket으로. 같은 연결로 읽음, 응답, 구독 변경 명령을 받는다
+type WSHandler struct {
+	log         *log.Logger
+	broker      *sse.Broker
+	notiService *notifications.NotiService
+	pushService *push.PushService
+
+	originPatterns []string
+	trustProxy     bool
+}
+
+func NewWSHandler(
+	log *log.Logger,
+	env config.Env,
+	broker *sse.Broker,
+	notiService *notifications.NotiService,
+	pushService *push.PushService,
+) *WSHandler {
+	h := &WSHandler{
+		log:         log,
+		broker:      broker,
+		notiService: notiService,
+		pushService: pushService,
+		trustProxy:  env.Service.TrustProxy,
+	}
+	// 같은 호스트는 기본으로 허용. 프론트를 따로 띄운 경우(FRONT_URL)만 추가
+	if u, err := url.Parse(env.FrontUrl); err == nil && u.Host != "" {
+		h.originPatterns = []string{u.Host}
+	}
+	return h
+}
+
+// errWSTokenExpired 연결에 쓴 access token이 만료됨. 클라이언트는 토큰을 갱신해 다시 연결한다
+var errWSTokenExpired = errors.New("websocket token expired")
+
+// tokenExpired 만료 시각이 없는 토큰은 만료되지 않는다
+func tokenExpired(claims *token.Claims, now time.Time) bool {
+	return claims.ExpiresAt != nil && !now.Before(claims.ExpiresAt.Time)
+}
+
+// wsCommand 클라이언트 → 서버. id는 응답의 ref로 돌려준다
+type wsCommand struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // read, react, subscribe
+
+	NotificationID string `json:"notification_id"`
+	Read           *bool  `json:"read"`
+	Reaction       string `json:"reaction"`
+	Comment        string `json:"comment"`
+
+	Events    []string `json:"events"`
+	Endpoints []string `json:"endpoints"`
+}
+
+// wsMessage 서버 → 클라이언트.
+// event: SSE 이벤트 (id, event, data 의미는 같음), result: 명령 성공, error: 명령 실패 (code는 REST와 같음)
+type wsMessage struct {
+	Type  string `json:"type"`
+	ID    string `json:"id,omitempty"`
+	Event string `json:"event,omitempty"`
+	Data  any    `json:"data,omitempty"`
+	Ref   string `json:"ref,omitempty"`
+	Code  string `json:"code,omitempty"`
+}
+
+// wsReply filter가 있으면 응답을 쓰기 전에 구독 조건을 바꾼다
+type wsReply struct {
+	msg    wsMessage
+	filter *sse.Filter
+}
+
+// Stream /api/ws?events=&endpoint_id=&last_event_id=
+// 브라우저 WebSocket은 헤더를 못 보내므로 Last-Event-ID는 쿼리로도 받는다
+func (h *WSHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userClaim, err := token.UserFromContext(r.Context())
+	if err != nil {
+		wrapper.RespondError(w, common.ErrUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	filter, err := sse.ParseFilter(query["events"], query["endpoint_id"])
+	if err != nil {
+		wrapper.RespondError(w, err)
+		return
+	}
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query.Get("last_event_id")
+	}
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: h.originPatterns})
+	if err != nil {
+		// Accept가 이미 응답을 썼다
+		h.log.Info("websocket accept failed", "err", err)
+		return
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(wsReadLimit)
+
+	ch, missed := h.broker.Subscribe(userClaim.UserID, lastEventID)
+	defer h.broker.Unsubscribe(userClaim.UserID, ch)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// 쓰기는 이 고루틴에서만. 명령 처리 결과와 구독 변경은 채널로 받는다
+	replies := make(chan wsReply, wsReplyBuffer)
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- h.readCommands(ctx, conn, r, replies)
+	}()
+
+	write := func(msg wsMessage) bool {
+		writeCtx, cancel := context.WithTimeout(ctx, wsWriteTimeout)
+		defer cancel()
+		if err := wsjson.Write(writeCtx, conn, msg); err != nil {
+			h.log.Info("client disconnected while writing", "userID", userClaim.UserID)
+			return false
+		}
+		return true
+	}
+	writeEvent := func(event sse.SSEEvent) bool {
+		if !filter.Match(event) {
+			return true
+		}
+		return write(wsMessage{Type: "event", ID: event.ID, Event: event.Event, Data: event.Data})
+	}
+
+	if !write(wsMessage{Type: "connected"}) {
+		return
+	}
+	for _, event := range missed {
+		if !writeEvent(event) {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	// 명령이 없어도 토큰 만료 시각에 연결을 닫는다
+	var expired <-chan time.Time
+	if userClaim.ExpiresAt != nil {
+		timer := time.NewTimer(time.Until(userClaim.ExpiresAt.Time))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.broker.Done():
+			conn.Close(websocket.StatusGoingAway, "server shutting down")
+			return
+		case <-expired:
+			conn.Close(websocket.StatusPolicyViolation, "token expired")
+			return
+		case err := <-readErr:
+			if errors.Is(err, errWSTokenExpired) {
+				conn.Close(websocket.StatusPolicyViolation, "token expired")
+				return
+			}
+			if status := websocket.CloseStatus(err); status != websocket.StatusNormalClosure && status != websocket.StatusGoingAway {
+				h.log.Info("websocket read", "userID", userClaim.UserID, "err", err)
+			}
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, wsPingTimeout)
+			err := conn.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				h.log.Info("websocket ping failed", "userID", userClaim.UserID, "err", err)
+				return
+			}
+		case reply := <-replies:
+			if reply.filter != nil {
+				filter = *reply.filter
+			}
+			if !write(reply.msg) {
+				return
+			}
+		case event, ok := <-ch:
+			// 버퍼가 넘쳐 끊겼거나 종료 중. 클라이언트는 last_event_id로 다시 연결한다
+			if !ok {
+				conn.Close(h.closeReason(userClaim.UserID, ch))
+				return
+			}
+			if !writeEvent(event) {
+				return
+			}
+		}
+	}
+}
+
+// closeReason 구독 채널이 닫힌 이유. Shutdown은 잠금을 쥔 채 채널을 닫고 Done을 닫으므로
+// Unsubscribe로 잠금을 기다린 뒤 확인한다
+func (h *WSHandler) closeReason(userID uuid.UUID, ch chan sse.SSEEvent) (websocket.StatusCode, string) {
+	h.broker.Unsubscribe(userID, ch)
+	select {
+	case <-h.broker.Done():
+		return websocket.StatusGoingAway, "server shutting down"
+	default:
+		return websocket.StatusTryAgainLater, "too slow"
+	}
+}
+
+// readCommands 연결이 끝날 때까지 명령을 읽는다. Ping의 pong도 여기서 읽혀야 처리된다.
+// 토큰이 만료된 뒤에 온 명령은 처리하지 않고 errWSTokenExpired
+func (h *WSHandler) readCommands(ctx context.Context, conn *websocket.Conn, r *http.Request, replies chan<- wsReply) error {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// wsjson.Read는 JSON이 잘못되면 연결을 닫으므로 직접 푼다. 잘못된 명령은 error로 답하고 연결은 유지
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return err
+		}
+		if tokenExpired(userClaim, time.Now()) {
+			return errWSTokenExpired
+		}
+		var cmd wsCommand
+		if err := json.Unmarshal(data, &cmd); err != nil {
+			cmd = wsCommand{ID: cmd.ID, Type: "invalid"}
+		}
+
+		reply := wsReply{msg: wsMessage{Type: "result", Ref: cmd.ID}}
+		filter, err := h.handleCommand(ctx, r, cmd)
+		if err != nil {
+			var domErr *common.DomainError
+			if !errors.As(err, &domErr) {
+				h.log.Error("websocket command", "type", cmd.Type, "err", err)
+				domErr = common.ErrInternalServer
+			}
+			reply.msg = wsMessage{Type: "error", Ref: cmd.ID, Code: domErr.Code}
+		}
+		reply.filter = filter
+
+		select {
+		case replies <- reply:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// handleCommand subscribe면 바꿀 구독 조건을 반환
+func (h *WSHandler) handleCommand(ctx context.Context, r *http.Request, cmd wsCommand) (*sse.Filter, error) {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cmd.Type {
+	case "read":
+		id, err := uuid.Parse(cmd.NotificationID)
+		if err != nil {
+			return nil, common.ErrInvalidParam
+		}
+		read := cmd.Read == nil || *cmd.Read
+		return nil, h.notiService.SetRead(ctx, userClaim.UserID, id, read)
+
+	case "react":
+		id, err := uuid.Parse(cmd.NotificationID)
+		if err != nil || len([]rune(cmd.Comment)) > maxReactionCommentLength {
+			return nil, common.ErrInvalidParam
+		}
+		return nil, h.pushService.React(ctx, push.ReactParams{
+			NotiID:    id,
+			Reaction:  cmd.Reaction,
+			Comment:   strings.TrimSpace(cmd.Comment),
+			IP:        wrapper.ClientIP(r, h.trustProxy),
+			UserAgent: r.UserAgent(),
+		})
+
+	case "subscribe":
+		// 새 조건으로 바꾼다. 둘 다 비우면 전부 받는다
+		filter, err := sse.ParseFilter(cmd.Events, cmd.Endpoints)
+		if err != nil {
+			return nil, err
+		}
+		return &filter, nil
+	}
+	return nil, common.ErrBadRequest
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"torchi/internal/domain/common"
+	"torchi/internal/domain/sse"
+	"torchi/internal/pkg/log"
+	"torchi/internal/pkg/token"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func newTestWSHandler() *WSHandler {
+	return &WSHandler{
+		log:    &log.Logger{Logger: slog.New(slog.DiscardHandler)},
+		broker: sse.NewBroker(),
+	}
+}
+
+// dialWS 인증 미들웨어 대신 claims를 context에 넣고 연결한 뒤 connected를 받는다
+func dialWS(t *testing.T, h *WSHandler, claims *token.Claims) *websocket.Conn {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.Stream(w, r.WithContext(token.ContextWith(r.Context(), claims)))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.CloseNow() })
+
+	if msg := readWS(t, conn); msg.Type != "connected" {
+		t.Fatalf("expected connected, got: %+v", msg)
+	}
+	return conn
+}
+
+func readWS(t *testing.T, conn *websocket.Conn) wsMessage {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var msg wsMessage
+	if err := wsjson.Read(ctx, conn, &msg); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return msg
+}
+
+func writeWS(t *testing.T, conn *websocket.Conn, data string) {
+	t.Helper()
+	if err := conn.Write(context.Background(), websocket.MessageText, []byte(data)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func testClaims(expiresIn time.Duration) *token.Claims {
+	return &token.Claims{
+		UserID:           uuid.New(),
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn))},
+	}
+}
+
+func TestWS_Commands(t *testing.T) {
+	h := newTestWSHandler()
+	claims := testClaims(time.Hour)
+	conn := dialWS(t, h, claims)
+
+	cases := []struct {
+		name string
+		cmd  string
+		want wsMessage
+	}{
+		{"subscribe", `{"id":"1","type":"subscribe","events":["read"]}`, wsMessage{Type: "result", Ref: "1"}},
+		{"bad endpoint filter", `{"id":"2","type":"subscribe","endpoints":["x"]}`, wsMessage{Type: "error", Ref: "2", Code: common.ErrInvalidParam.Code}},
+		{"bad notification id", `{"id":"3","type":"read","notification_id":"x"}`, wsMessage{Type: "error", Ref: "3", Code: common.ErrInvalidParam.Code}},
+		{"long comment", `{"id":"4","type":"react","notification_id":"` + uuid.NewString() + `","comment":"` + strings.Repeat("a", maxReactionCommentLength+1) + `"}`, wsMessage{Type: "error", Ref: "4", Code: common.ErrInvalidParam.Code}},
+		{"unknown type", `{"id":"5","type":"nope"}`, wsMessage{Type: "error", Ref: "5", Code: common.ErrBadRequest.Code}},
+		{"invalid json keeps connection", `{"id":`, wsMessage{Type: "error", Code: common.ErrBadRequest.Code}},
+	}
+
+	for _, c := range cases {
+		writeWS(t, conn, c.cmd)
+		if got := readWS(t, conn); got != c.want {
+			t.Errorf("%s expected: %+v, got: %+v", c.name, c.want, got)
+		}
+	}
+
+	// subscribe로 바꾼 조건이 이후 이벤트에 적용된다
+	h.broker.Publish(claims.UserID, sse.SSEEvent{Event: "notification", Data: map[string]any{}})
+	h.broker.Publish(claims.UserID, sse.SSEEvent{Event: "read", Data: map[string]any{}})
+	if got := readWS(t, conn); got.Type != "event" || got.Event != "read" {
+		t.Errorf("expected filtered read event, got: %+v", got)
+	}
+}
+
+func TestWS_TokenExpired(t *testing.T) {
+	conn := dialWS(t, newTestWSHandler(), testClaims(200*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, _, err := conn.Read(ctx)
+	if status := websocket.CloseStatus(err); status != websocket.StatusPolicyViolation {
+		t.Errorf("expected: %v, got: %v (%v)", websocket.StatusPolicyViolation, status, err)
+	}
+}
+
+func TestTokenExpired(t *testing.T) {
+	now := time.Now()
+	if tokenExpired(&token.Claims{}, now) {
+		t.Error("token without exp should not expire")
+	}
+	if tokenExpired(testClaims(time.Minute), now) {
+		t.Error("valid token should not expire")
+	}
+	if !tokenExpired(testClaims(-time.Second), now) {
+		t.Error("past exp should expire")
+	}
+}
+
+func TestWSHandler_CloseReason(t *testing.T) {
+	h := newTestWSHandler()
+	userID := uuid.New()
+
+	// 버퍼가 넘치도록 보내면 브로커가 채널을 닫는다
+	ch, _ := h.broker.Subscribe(userID, "")
+	for range 100 {
+		h.broker.Publish(userID, sse.SSEEvent{Event: "notification"})
+	}
+	for range ch {
+	}
+	if status, _ := h.closeReason(userID, ch); status != websocket.StatusTryAgainLater {
+		t.Errorf("slow client expected: %v, got: %v", websocket.StatusTryAgainLater, status)
+	}
+
+	ch, _ = h.broker.Subscribe(userID, "")
+	h.broker.Shutdown()
+	if status, _ := h.closeReason(userID, ch); status != websocket.StatusGoingAway {
+		t.Errorf("shutdown expected: %v, got: %v", websocket.StatusGoingAway, status)
+	}
+}
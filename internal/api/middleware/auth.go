@@ -32,3 +32,25 @@ func AuthMiddleware(tp *token.TokenProvider) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// OptionalAuthMiddleware 토큰이 있으면 검증해서 Context에 넣고, 없거나 유효하지 않으면 익명으로 통과시킴.
+// 외부 호출(/v1)과 앱 호출이 섞인 라우트에서 사용.
+func OptionalAuthMiddleware(tp *token.TokenProvider) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie(handler.AccessCookieKey)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, err := tp.Validate(cookie.Value)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(token.ContextWith(r.Context(), claims)))
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"sync"
 	"time"
+	"torchi/internal/api/handler"
 	"torchi/internal/pkg/log"
 
 	"go.uber.org/fx"
@@ -87,8 +88,11 @@ func RateLimitMiddleware(mgr *RateLimiterManager) func(http.Handler) http.Handle
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
+			// 헤더로 토큰을 보낸 경우 경로가 같으므로 토큰 기준으로 제한
 			token := r.URL.Path
-			mgr.log.Debug("token", "..", token)
+			if t := handler.EndpointTokenFromHeader(r); t != "" {
+				token = "token:" + t
+			}
 			if token == "" {
 				next.ServeHTTP(w, r)
 				return
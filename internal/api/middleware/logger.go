@@ -0,0 +1,126 @@
+package middle
+
+import (
+	"fmt"
+	stdlog "log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"torchi/internal/pkg/token"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestLogger chi middleware.Logger와 같은 형식으로 남기되 경로의 endpoint 토큰을 가린다.
+// {token} 파라미터는 라우팅이 끝나야 알 수 있으므로 요청 시작이 아닌 응답 시점에 기록한다.
+func RequestLogger() func(http.Handler) http.Handler {
+	return middleware.RequestLogger(&redactLogFormatter{
+		logger: stdlog.New(os.Stdout, "", stdlog.LstdFlags),
+	})
+}
+
+type redactLogFormatter struct {
+	logger middleware.LoggerInterface
+}
+
+func (f *redactLogFormatter) NewLogEntry(r *http.Request) middleware.LogEntry {
+	return &redactLogEntry{logger: f.logger, request: r}
+}
+
+type redactLogEntry struct {
+	logger  middleware.LoggerInterface
+	request *http.Request
+}
+
+func (e *redactLogEntry) Write(status, bytes int, _ http.Header, elapsed time.Duration, _ interface{}) {
+	r := e.request
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	line := fmt.Sprintf("\"%s %s://%s%s %s\" from %s - %03d %dB in %s",
+		r.Method, scheme, r.Host, redactTokenParams(r, r.RequestURI), r.Proto,
+		r.RemoteAddr, status, bytes, elapsed,
+	)
+	if reqID := middleware.GetReqID(r.Context()); reqID != "" {
+		line = "[" + reqID + "] " + line
+	}
+	e.logger.Print(line)
+}
+
+func (e *redactLogEntry) Panic(v interface{}, stack []byte) {
+	middleware.PrintPrettyStack(v)
+}
+
+// redactedQueryKeys 값이 로그에 남으면 안 되는 쿼리 파라미터. OAuth 콜백의 code, state 등
+var redactedQueryKeys = map[string]struct{}{
+	"token":         {},
+	"access_token":  {},
+	"refresh_token": {},
+	"code":          {},
+	"state":         {},
+}
+
+// pushTokenPath 이 뒤의 경로 조각은 endpoint 토큰.
+// 라우트가 맞지 않은 요청(404, 405)은 chi가 {token}을 채우지 않으므로 경로 모양으로도 가린다
+const pushTokenPath = "/v1/push/"
+
+// pushStaticSegments 토큰을 헤더로 받는 /v1/push/ 아래 고정 경로
+var pushStaticSegments = map[string]struct{}{
+	"ask":    {},
+	"stream": {},
+	"poll":   {},
+}
+
+// redactTokenParams s에 포함된 {token} URL 파라미터 값과 redactedQueryKeys 쿼리 값을 가린다
+func redactTokenParams(r *http.Request, s string) string {
+	path, query, hasQuery := strings.Cut(s, "?")
+	path = redactPushToken(path)
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		for i, key := range rctx.URLParams.Keys {
+			if key == "token" && i < len(rctx.URLParams.Values) {
+				path = token.RedactIn(path, rctx.URLParams.Values[i])
+			}
+		}
+	}
+	if !hasQuery {
+		return path
+	}
+	return path + "?" + redactQuery(query)
+}
+
+func redactPushToken(path string) string {
+	i := strings.Index(path, pushTokenPath)
+	if i < 0 {
+		return path
+	}
+	start := i + len(pushTokenPath)
+	segment, _, _ := strings.Cut(path[start:], "/")
+	if _, static := pushStaticSegments[segment]; segment == "" || static {
+		return path
+	}
+	return path[:start] + token.Redact(segment) + path[start+len(segment):]
+}
+
+// redactQuery 원래 순서와 인코딩은 그대로 두고 값만 가린다
+func redactQuery(query string) string {
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if _, secret := redactedQueryKeys[strings.ToLower(key)]; secret {
+			pairs[i] = pair[:len(pair)-len(value)] + token.Redact(value)
+		}
+	}
+	return strings.Join(pairs, "&")
+}
@@ -0,0 +1,86 @@
+package middle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestRedactTokenParams(t *testing.T) {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("token", "abcdefghijklmnop")
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+	cases := []struct {
+		in, want string
+	}{
+		{"/api/v1/push/abcdefghijklmnop/ask", "/api/v1/push/abc***/ask"},
+		{"/api/auth/github/callback?code=0123456789abcdef&state=xyz", "/api/auth/github/callback?code=012***&state=***"},
+		{"/api/notifications?query=code&limit=10", "/api/notifications?query=code&limit=10"},
+		{"/api/ws?ACCESS_TOKEN=0123456789abcdef&flag", "/api/ws?ACCESS_TOKEN=012***&flag"},
+	}
+
+	for _, c := range cases {
+		if got := redactTokenParams(r, c.in); got != c.want {
+			t.Errorf("redactTokenParams(%q) expected: %q, got: %q", c.in, c.want, got)
+		}
+	}
+}
+
+type captureLogger struct {
+	lines []string
+}
+
+func (l *captureLogger) Print(v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprint(v...))
+}
+
+// 라우트가 맞지 않으면 chi가 {token}을 채우지 않아도 로그에 토큰이 남으면 안 된다
+func TestRequestLogger_UnmatchedPushRoutes(t *testing.T) {
+	const secret = "SECRETTOKEN1234567890"
+
+	logger := &captureLogger{}
+	r := chi.NewRouter()
+	r.Use(middleware.RequestLogger(&redactLogFormatter{logger: logger}))
+	r.Route("/api", func(r chi.Router) {
+		v1 := chi.NewRouter()
+		v1.Post("/push/{token}", func(w http.ResponseWriter, r *http.Request) {})
+		v1.Post("/push/ask", func(w http.ResponseWriter, r *http.Request) {})
+		r.Mount("/v1", v1)
+	})
+
+	cases := []struct {
+		name       string
+		method     string
+		url        string
+		wantStatus int
+	}{
+		{"method not allowed", http.MethodGet, "/api/v1/push/" + secret, http.StatusMethodNotAllowed},
+		{"not found", http.MethodPost, "/api/v1/push/" + secret + "/typo", http.StatusNotFound},
+		{"matched", http.MethodPost, "/api/v1/push/" + secret, http.StatusOK},
+	}
+
+	for _, c := range cases {
+		logger.lines = nil
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(c.method, c.url, nil))
+
+		if w.Code != c.wantStatus {
+			t.Errorf("%s expected status: %d, got: %d", c.name, c.wantStatus, w.Code)
+		}
+		if len(logger.lines) != 1 || strings.Contains(logger.lines[0], secret) {
+			t.Errorf("%s token should be redacted, got: %v", c.name, logger.lines)
+		}
+	}
+
+	if got := redactPushToken("/api/v1/push/ask"); got != "/api/v1/push/ask" {
+		t.Errorf("static path should stay, got: %s", got)
+	}
+}
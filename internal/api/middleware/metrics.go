@@ -11,6 +11,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// unmatchedRoute 라우트가 없는 요청의 path 라벨. 요청 경로를 그대로 쓰면 스캐너가 라벨을 끝없이 늘린다
+const unmatchedRoute = "unmatched"
+
 type Metrics struct {
 	requestsTotal    *prometheus.CounterVec
 	requestDuration  *prometheus.HistogramVec
@@ -49,7 +52,7 @@ func (m *Metrics) Middleware() func(http.Handler) http.Handler {
 
 			path := chi.RouteContext(r.Context()).RoutePattern()
 			if path == "" {
-				path = r.URL.Path
+				path = unmatchedRoute
 			}
 
 			if path == "/metrics" {
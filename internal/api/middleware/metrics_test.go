@@ -101,3 +101,15 @@ func TestMetricsEndpointExcluded(t *testing.T) {
 		t.Error("/metrics 스크레이핑은 집계되면 안 됨")
 	}
 }
+
+func TestUnmatchedRouteLabel(t *testing.T) {
+	m, reg := setup()
+	request(m, "/api/health", "/api/v1/push/abc123/unknown")
+
+	if !hasRequestCount(reg, unmatchedRoute) {
+		t.Error("라우트가 없는 요청은 고정 라벨로 집계되어야 함")
+	}
+	if hasRequestCount(reg, "/api/v1/push/abc123/unknown") {
+		t.Error("요청 경로가 라벨로 남으면 안 됨")
+	}
+}
@@ -17,9 +17,12 @@ func NewRouter(
 	authHandler *handler.AuthHandler,
 	userHandler *handler.UserHandler,
 	endpointHandler *handler.EndpointHandler,
+	orgHandler *handler.OrganizationHandler,
 	apiHandler *handler.ApiHandler,
 	notiHandler *handler.NotiHandler,
+	retentionHandler *handler.RetentionHandler,
 	sseHandler *handler.SSEHandler,
+	wsHandler *handler.WSHandler,
 	healthHandler *handler.HealthHandler,
 
 	tokenProvider *token.TokenProvider,
@@ -30,7 +33,7 @@ func NewRouter(
 ) *chi.Mux {
 	r := chi.NewRouter()
 
-	r.Use(middleware.Logger)
+	r.Use(middle.RequestLogger())
 	r.Use(middleware.Recoverer)
 	r.Use(middle.CorsMiddleware(env.FrontUrl))
 	r.Use(m.Middleware())
@@ -45,7 +48,10 @@ func NewRouter(
 
 	r.Route("/api", func(r chi.Router) {
 		r.Get("/health", healthHandler.Check)
-		r.With(middle.RateLimitMiddleware(limitMiddleware)).Mount("/v1", apiHandler.Routes())
+		r.With(
+			middle.RateLimitMiddleware(limitMiddleware),
+			middle.OptionalAuthMiddleware(tokenProvider), // react 시 step-up 확인용
+		).Mount("/v1", apiHandler.Routes())
 
 		r.Mount("/auth", authHandler.Routes())
 
@@ -54,8 +60,11 @@ func NewRouter(
 			r.Mount("/subscriptions", subscriptionHandler.Routes())
 			r.Mount("/users", userHandler.Routes())
 			r.Mount("/endpoints", endpointHandler.Routes())
+			r.Mount("/orgs", orgHandler.Routes())
 			r.Mount("/notifications", notiHandler.Routes())
+			r.Mount("/retention", retentionHandler.Routes())
 			r.Mount("/sse", sseHandler.Routes())
+			r.Get("/ws", wsHandler.Stream)
 		})
 	})
 
@@ -68,8 +77,11 @@ var routeModule = fx.Module("router",
 		handler.NewAuthHandler,
 		handler.NewUserHandler,
 		handler.NewEndpointHandler,
+		handler.NewOrganizationHandler,
 		handler.NewNotiHandler,
+		handler.NewRetentionHandler,
 		handler.NewSSEHandler,
+		handler.NewWSHandler,
 		handler.NewHealthHandler,
 
 		// API
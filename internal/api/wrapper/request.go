@@ -0,0 +1,30 @@
+package wrapper
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientIP 요청자 IP.
+// trustProxy가 true일 때만 리버스 프록시가 넣어주는 X-Forwarded-For / X-Real-IP를 사용한다.
+// (프록시 없이 노출된 경우 헤더는 클라이언트가 마음대로 넣을 수 있음)
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			first, _, _ := strings.Cut(xff, ",")
+			if ip := strings.TrimSpace(first); ip != "" {
+				return ip
+			}
+		}
+		if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -40,11 +40,12 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string)
 	}
 
 	// 추출한 정보로 새 토큰 세트 생성 (Sliding Window)
-	newAccess, err := s.tokenProvider.CreateAccessToken(claims.UserID, claims.Email)
+	// 로그인 시각(auth_time)은 갱신하지 않고 이어받음
+	newAccess, err := s.tokenProvider.CreateAccessToken(claims.UserID, claims.Email, claims.AuthInfo())
 	if err != nil {
 		return "", "", err
 	}
-	newRefresh, err := s.tokenProvider.CreateRefreshToken(claims.UserID, claims.Email)
+	newRefresh, err := s.tokenProvider.CreateRefreshToken(claims.UserID, claims.Email, claims.AuthInfo())
 	if err != nil {
 		return "", "", err
 	}
@@ -56,15 +57,16 @@ func (s *AuthService) TestLogin(ctx context.Context) (string, string, error) {
 
 	userID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
 	email := "tester@gmail.com"
+	auth := token.NewAuthInfo(token.AuthMethodGithub)
 
 	// Access Token (짧은 수명)
-	accessToken, err := s.tokenProvider.CreateAccessToken(userID, email)
+	accessToken, err := s.tokenProvider.CreateAccessToken(userID, email, auth)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// Refresh Token (긴 수명)
-	refreshToken, err := s.tokenProvider.CreateRefreshToken(userID, email)
+	refreshToken, err := s.tokenProvider.CreateRefreshToken(userID, email, auth)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
 	}
@@ -84,7 +86,7 @@ func (s *AuthService) GuestLogin(ctx context.Context, guestID *uuid.UUID) (Login
 		return LoginResult{}, fmt.Errorf("failed to upsert guest user: %w", err)
 	}
 
-	at, rt, err := s.tokenProvider.CreatePairToken(dbUser.ID, "guest")
+	at, rt, err := s.tokenProvider.CreatePairToken(dbUser.ID, "guest", token.NewAuthInfo(token.AuthMethodGuest))
 	if err != nil {
 		return LoginResult{}, err
 	}
@@ -133,11 +135,12 @@ func (s *AuthService) OauthGithubFlow(ctx context.Context, code string) (at stri
 	}
 
 	// 페어 토큰 생성
-	at, err = s.tokenProvider.CreateAccessToken(dbUser.ID, email)
+	auth := token.NewAuthInfo(token.AuthMethodGithub)
+	at, err = s.tokenProvider.CreateAccessToken(dbUser.ID, email, auth)
 	if err != nil {
 		return at, rt, err
 	}
-	rt, err = s.tokenProvider.CreateRefreshToken(dbUser.ID, email)
+	rt, err = s.tokenProvider.CreateRefreshToken(dbUser.ID, email, auth)
 	if err != nil {
 		return at, rt, err
 	}
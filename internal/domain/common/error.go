@@ -25,7 +25,31 @@ var (
 	ErrInvalidParam   = NewError(400, "INVALID_PARAMETER")
 	ErrInternalServer = NewError(500, "INTERNAL_SERVER_ERROR")
 
-	ErrUserNotFound          = NewError(404, "USER_NOT_FOUND")
-	ErrEndpointNotFound      = NewError(404, "ENDPOINT_NOT_FOUND")
-	ErrNotificationDeleted   = NewError(410, "NOTIFICATION_DELETED")
+	ErrUserNotFound         = NewError(404, "USER_NOT_FOUND")
+	ErrEndpointNotFound     = NewError(404, "ENDPOINT_NOT_FOUND")
+	ErrEndpointNameConflict = NewError(409, "ENDPOINT_NAME_DUPLICATED")
+	ErrEndpointExpired      = NewError(410, "ENDPOINT_EXPIRED")
+	ErrEndpointPermission   = NewError(403, "ENDPOINT_PERMISSION_DENIED") // 볼 수는 있지만 관리 권한이 없음
+	ErrSourceIPNotAllowed   = NewError(403, "SOURCE_IP_NOT_ALLOWED")
+	ErrAskNotAllowed        = NewError(403, "ASK_NOT_ALLOWED")
+	ErrAskTimeoutExceeded   = NewError(400, "ASK_TIMEOUT_EXCEEDED")
+	ErrPayloadTooLarge      = NewError(413, "PAYLOAD_TOO_LARGE")
+	ErrSignatureRequired    = NewError(401, "SIGNATURE_REQUIRED")
+	ErrSignatureExpired     = NewError(401, "SIGNATURE_EXPIRED")
+	ErrSignatureInvalid     = NewError(401, "SIGNATURE_INVALID")
+	ErrSignatureReplayed    = NewError(409, "SIGNATURE_REPLAYED")
+	ErrSenderKeyNotFound    = NewError(404, "SENDER_KEY_NOT_FOUND")
+	ErrSenderKeyScope       = NewError(403, "SENDER_KEY_SCOPE_DENIED")
+	ErrSenderKeyLimit       = NewError(409, "SENDER_KEY_LIMIT_EXCEEDED")
+	ErrMemberNotFound       = NewError(404, "MEMBER_NOT_FOUND")
+	ErrOwnerCannotLeave     = NewError(409, "OWNER_CANNOT_LEAVE") // owner는 endpoint를 삭제해야 함
+	ErrInviteNotFound       = NewError(404, "INVITE_NOT_FOUND")
+	ErrInviteExpired        = NewError(410, "INVITE_EXPIRED") // 만료, 폐기, 사용 횟수 초과
+	ErrOrgNotFound          = NewError(404, "ORGANIZATION_NOT_FOUND")
+	ErrOrgPermission        = NewError(403, "ORGANIZATION_PERMISSION_DENIED")
+	ErrLastOrgOwner         = NewError(409, "LAST_ORGANIZATION_OWNER") // 조직에는 owner가 한 명 이상 있어야 함
+	ErrNotificationDeleted  = NewError(410, "NOTIFICATION_DELETED")
+	ErrNotificationNotFound = NewError(404, "NOTIFICATION_NOT_FOUND")
+	ErrSyncTokenExpired     = NewError(410, "SYNC_TOKEN_EXPIRED") // 처음부터 다시 동기화해야 함
+	ErrStepUpRequired       = NewError(403, "STEP_UP_REQUIRED")   // sensitive 요청은 최근 재로그인 필요
 )
@@ -0,0 +1,34 @@
+package endpoint
+
+import (
+	"torchi/internal/domain/organization"
+
+	"github.com/google/uuid"
+)
+
+// Access 유저가 endpoint에 대해 가진 권한. 판단은 EndpointService에서 한다
+type Access struct {
+	OwnerID  *uuid.UUID // 개인 endpoint 소유자. 조직 endpoint면 nil
+	OrgID    *uuid.UUID // 조직 endpoint면 소유 조직
+	OrgRole  string     // 소유 조직에서 유저의 역할. 조직 멤버가 아니면 빈 문자열
+	IsMember bool       // endpoint 멤버로 알림을 받고 있는지
+}
+
+// CanManage 설정, 토큰, sender key, 초대, 멤버 관리.
+// 개인 endpoint는 소유자, 조직 endpoint는 조직 owner/admin
+func (a *Access) CanManage(userID uuid.UUID) bool {
+	if a.OrgID != nil {
+		return organization.CanManageEndpoints(a.OrgRole)
+	}
+	return a.OwnerID != nil && *a.OwnerID == userID
+}
+
+// CanView endpoint 멤버이거나 소유 조직의 멤버
+func (a *Access) CanView(userID uuid.UUID) bool {
+	return a.IsMember || a.OrgRole != "" || a.CanManage(userID)
+}
+
+// CanSubscribe 초대 없이 알림을 받을 수 있는지. 조직 endpoint는 조직 멤버면 누구나
+func (a *Access) CanSubscribe() bool {
+	return a.OrgID != nil && a.OrgRole != ""
+}
@@ -0,0 +1,44 @@
+package endpoint
+
+import (
+	"testing"
+	"torchi/internal/domain/organization"
+
+	"github.com/google/uuid"
+)
+
+func TestAccess(t *testing.T) {
+	me := uuid.New()
+	other := uuid.New()
+	org := uuid.New()
+
+	cases := []struct {
+		name      string
+		access    Access
+		manage    bool
+		view      bool
+		subscribe bool
+	}{
+		{"personal owner", Access{OwnerID: &me, IsMember: true}, true, true, false},
+		{"personal member", Access{OwnerID: &other, IsMember: true}, false, true, false},
+		{"personal stranger", Access{OwnerID: &other}, false, false, false},
+		{"org admin", Access{OrgID: &org, OrgRole: organization.RoleAdmin}, true, true, true},
+		{"org member", Access{OrgID: &org, OrgRole: organization.RoleMember}, false, true, true},
+		{"invited outsider", Access{OrgID: &org, IsMember: true}, false, true, false},
+		{"org stranger", Access{OrgID: &org}, false, false, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.access.CanManage(me); got != c.manage {
+				t.Errorf("CanManage expected: %v, got: %v", c.manage, got)
+			}
+			if got := c.access.CanView(me); got != c.view {
+				t.Errorf("CanView expected: %v, got: %v", c.view, got)
+			}
+			if got := c.access.CanSubscribe(); got != c.subscribe {
+				t.Errorf("CanSubscribe expected: %v, got: %v", c.subscribe, got)
+			}
+		})
+	}
+}
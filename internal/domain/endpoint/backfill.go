@@ -0,0 +1,101 @@
+package endpoint
+
+import (
+	"context"
+	"torchi/internal/pkg/log"
+	"torchi/internal/pkg/token"
+
+	"go.uber.org/fx"
+)
+
+const backfillBatchSize = 500
+
+// RegisterTokenBackfill 서버 시작 시 평문으로 남아있는 토큰을 해시로 옮기고 평문은 지운다.
+// FindByToken은 해시로만 조회하므로 요청을 받기 전에 끝내고, 실패하면 서버를 시작하지 않는다.
+func RegisterTokenBackfill(lc fx.Lifecycle, repo EndpointRepository, hasher *token.Hasher, log *log.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			count, err := backfillTokenHash(ctx, repo, hasher)
+			if err != nil {
+				log.Error("endpoint token backfill failed", "err", err, "migrated", count)
+				return err
+			}
+			if count > 0 {
+				log.Info("endpoint token backfill done", "migrated", count)
+			}
+			return nil
+		},
+	})
+}
+
+func backfillTokenHash(ctx context.Context, repo EndpointRepository, hasher *token.Hasher) (int, error) {
+	count := 0
+	for {
+		rows, err := repo.ListPlaintextTokens(ctx, backfillBatchSize)
+		if err != nil {
+			return count, err
+		}
+		if len(rows) == 0 {
+			return count, nil
+		}
+
+		for _, row := range rows {
+			params := setTokenHashParams{id: row.id}
+			if row.token != nil {
+				hash, prefix := hasher.Hash(*row.token), token.Prefix(*row.token)
+				params.tokenHash, params.tokenPrefix = &hash, &prefix
+			}
+			if row.previousToken != nil {
+				hash := hasher.Hash(*row.previousToken)
+				params.previousTokenHash = &hash
+			}
+
+			if err := repo.SetTokenHash(ctx, params); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+}
+
+// RegisterSigningSecretBackfill 서버 시작 시 평문으로 남아있는 서명 secret을 암호화한다.
+// FindByToken은 암호화된 secret만 풀 수 있으므로 요청을 받기 전에 끝내고, 실패하면 서버를 시작하지 않는다.
+func RegisterSigningSecretBackfill(lc fx.Lifecycle, repo EndpointRepository, sealer *token.Sealer, log *log.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			count, err := backfillSigningSecrets(ctx, repo, sealer)
+			if err != nil {
+				log.Error("signing secret backfill failed", "err", err, "sealed", count)
+				return err
+			}
+			if count > 0 {
+				log.Info("signing secret backfill done", "sealed", count)
+			}
+			return nil
+		},
+	})
+}
+
+func backfillSigningSecrets(ctx context.Context, repo EndpointRepository, sealer *token.Sealer) (int, error) {
+	count := 0
+	for {
+		rows, err := repo.ListPlaintextSigningSecrets(ctx, backfillBatchSize)
+		if err != nil {
+			return count, err
+		}
+		if len(rows) == 0 {
+			return count, nil
+		}
+
+		for _, row := range rows {
+			sealed, err := sealer.Seal(row.secret)
+			if err != nil {
+				return count, err
+			}
+			if err := repo.SealSigningSecret(ctx, row.id, row.secret, sealed); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"torchi/internal/pkg/token"
+
+	"github.com/google/uuid"
+)
+
+// backfillRepo ListPlaintextTokens, SetTokenHash만 쓰는 backfill용 가짜 저장소
+type backfillRepo struct {
+	EndpointRepository
+	rows   []plaintextToken
+	set    []setTokenHashParams
+	setErr error
+}
+
+func (r *backfillRepo) ListPlaintextTokens(_ context.Context, limit int) ([]plaintextToken, error) {
+	return append([]plaintextToken(nil), r.rows[:min(limit, len(r.rows))]...), nil
+}
+
+func (r *backfillRepo) SetTokenHash(_ context.Context, params setTokenHashParams) error {
+	if r.setErr != nil {
+		return r.setErr
+	}
+	r.set = append(r.set, params)
+	// 평문을 지운 row는 다음 조회에서 빠진다
+	for i, row := range r.rows {
+		if row.id == params.id {
+			r.rows = append(r.rows[:i], r.rows[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func TestBackfillTokenHash(t *testing.T) {
+	hasher := token.NewHasher("key")
+	current, previous := "current-token", "previous-token"
+
+	rows := make([]plaintextToken, backfillBatchSize+1)
+	for i := range rows {
+		rows[i] = plaintextToken{id: uuid.New(), token: &current}
+	}
+	rows[0].previousToken = &previous
+	repo := &backfillRepo{rows: rows}
+
+	count, err := backfillTokenHash(context.Background(), repo, hasher)
+	if err != nil || count != backfillBatchSize+1 {
+		t.Fatalf("expected %d migrated, got: %d %v", backfillBatchSize+1, count, err)
+	}
+
+	first := repo.set[0]
+	if *first.tokenHash != hasher.Hash(current) || *first.tokenPrefix != token.Prefix(current) {
+		t.Errorf("unexpected token hash: %+v", first)
+	}
+	if first.previousTokenHash == nil || *first.previousTokenHash != hasher.Hash(previous) {
+		t.Errorf("previous token should be hashed: %+v", first)
+	}
+	if repo.set[1].previousTokenHash != nil {
+		t.Errorf("previous token hash should stay nil: %+v", repo.set[1])
+	}
+}
+
+func TestBackfillTokenHash_Error(t *testing.T) {
+	current := "current-token"
+	want := errors.New("db down")
+	repo := &backfillRepo{
+		rows:   []plaintextToken{{id: uuid.New(), token: &current}},
+		setErr: want,
+	}
+
+	if _, err := backfillTokenHash(context.Background(), repo, token.NewHasher("key")); !errors.Is(err, want) {
+		t.Errorf("expected: %v, got: %v", want, err)
+	}
+}
+
+// secretRepo ListPlaintextSigningSecrets, SealSigningSecret만 쓰는 backfill용 가짜 저장소
+type secretRepo struct {
+	EndpointRepository
+	rows   []plaintextSecret
+	sealed map[uuid.UUID]string
+}
+
+func (r *secretRepo) ListPlaintextSigningSecrets(_ context.Context, limit int) ([]plaintextSecret, error) {
+	return append([]plaintextSecret(nil), r.rows[:min(limit, len(r.rows))]...), nil
+}
+
+func (r *secretRepo) SealSigningSecret(_ context.Context, id uuid.UUID, plaintext, sealed string) error {
+	for i, row := range r.rows {
+		if row.id == id && row.secret == plaintext {
+			r.rows = append(r.rows[:i], r.rows[i+1:]...)
+			r.sealed[id] = sealed
+			break
+		}
+	}
+	return nil
+}
+
+func TestBackfillSigningSecrets(t *testing.T) {
+	sealer := token.NewSealer("key", "endpoint-signing-secret")
+
+	rows := make([]plaintextSecret, backfillBatchSize+1)
+	for i := range rows {
+		rows[i] = plaintextSecret{id: uuid.New(), secret: "secret"}
+	}
+	repo := &secretRepo{rows: rows, sealed: map[uuid.UUID]string{}}
+
+	count, err := backfillSigningSecrets(context.Background(), repo, sealer)
+	if err != nil || count != backfillBatchSize+1 {
+		t.Fatalf("expected %d sealed, got: %d %v", backfillBatchSize+1, count, err)
+	}
+
+	got, err := sealer.Open(repo.sealed[rows[0].id])
+	if err != nil || got != "secret" {
+		t.Errorf("expected sealed secret, got: %q %v", got, err)
+	}
+}
@@ -1,37 +1,266 @@
 -- name: CreateEndpoint :one
-INSERT INTO endpoints (
-    user_id,
-    name,
-    token
-) VALUES (
-    $1, 
-    $2, 
-    $3
+-- 만든 유저를 멤버로 함께 등록. 개인 endpoint면 owner, 조직 endpoint면 member
+WITH created AS (
+    INSERT INTO endpoints (
+        user_id,
+        org_id,
+        name,
+        token_hash,
+        token_prefix
+    ) VALUES (
+        sqlc.narg('user_id'),
+        sqlc.narg('org_id'),
+        sqlc.arg('name'),
+        sqlc.arg('token_hash'),
+        sqlc.arg('token_prefix')
+    )
+    RETURNING id, user_id
+), owner AS (
+    INSERT INTO endpoint_members (endpoint_id, user_id, role)
+    SELECT id, sqlc.arg('created_by'), CASE WHEN user_id IS NULL THEN 'member' ELSE 'owner' END
+    FROM created
 )
-RETURNING *;
+SELECT id FROM created;
 
 -- name: FindEndpointByUserID :many
-SELECT * FROM endpoints
-WHERE user_id = $1;
+-- 소유하거나 참여한 endpoint. 음소거 상태는 멤버별
+SELECT
+    sqlc.embed(e),
+    m.role,
+    m.notification_enabled AS member_notification_enabled,
+    m.notification_disabled_at AS member_notification_disabled_at,
+    m.muted_until AS member_muted_until,
+    o.name AS org_name,
+    om.role AS org_role
+FROM endpoints e
+JOIN endpoint_members m ON m.endpoint_id = e.id
+LEFT JOIN organizations o ON o.id = e.org_id
+LEFT JOIN organization_members om ON om.org_id = e.org_id AND om.user_id = m.user_id
+WHERE m.user_id = $1;
 
--- name: FindEndpointByToken :one
-SELECT * FROM endpoints
-WHERE token = $1;
+-- name: FindEndpointByOrgID :many
+-- 조직의 모든 endpoint. 알림을 받지 않는 endpoint는 member_role이 NULL
+SELECT
+    sqlc.embed(e),
+    m.role AS member_role,
+    m.notification_enabled AS member_notification_enabled,
+    m.muted_until AS member_muted_until
+FROM endpoints e
+LEFT JOIN endpoint_members m ON m.endpoint_id = e.id AND m.user_id = sqlc.arg('user_id')
+WHERE e.org_id = sqlc.arg('org_id')
+ORDER BY e.created_at;
+
+-- name: FindEndpointAccess :one
+-- 권한 확인용. 조직 멤버가 아니면 org_role은 NULL
+SELECT
+    e.user_id,
+    e.org_id,
+    om.role AS org_role,
+    EXISTS (
+        SELECT 1 FROM endpoint_members m
+        WHERE m.endpoint_id = e.id
+          AND m.user_id = sqlc.arg('user_id')
+    ) AS is_member
+FROM endpoints e
+LEFT JOIN organization_members om ON om.org_id = e.org_id AND om.user_id = sqlc.arg('user_id')
+WHERE e.id = sqlc.arg('id');
 
+-- name: TransferEndpointToOrg :one
+-- 개인 endpoint를 조직으로 옮긴다. 기존 owner는 member로 남아 계속 알림을 받음.
+-- 소유자이면서 대상 조직의 owner/admin이 아니면 row가 없음
+WITH moved AS (
+    UPDATE endpoints
+    SET user_id = NULL,
+        org_id = sqlc.arg('org_id')
+    WHERE id = sqlc.arg('id')
+      AND user_id = sqlc.arg('user_id')::uuid
+      AND EXISTS (
+          SELECT 1 FROM organization_members om
+          WHERE om.org_id = sqlc.arg('org_id')
+            AND om.user_id = sqlc.arg('user_id')
+            AND om.role IN ('owner', 'admin')
+      )
+    RETURNING id
+), demoted AS (
+    UPDATE endpoint_members m
+    SET role = 'member'
+    FROM moved
+    WHERE m.endpoint_id = moved.id
+)
+SELECT id FROM moved;
+
+-- name: FindEndpointByTokenHash :one
+-- 재발급 유예기간 중이면 이전 토큰으로도 조회됨.
+-- 평문 토큰은 서버 시작 시 backfill로 모두 해시로 옮긴 뒤 요청을 받는다
+SELECT * FROM endpoints
+WHERE token_hash = sqlc.arg('token_hash')
+   OR (previous_token_hash = sqlc.arg('token_hash') AND previous_token_expires_at > now());
 
--- name: DeleteEndpointByToken :exec
+-- name: DeleteEndpoint :execrows
+-- 관리 권한이 없으면 row가 없음
 DELETE FROM endpoints
-WHERE token = $1
+WHERE id = sqlc.arg('id')
+  AND (
+      endpoints.user_id = sqlc.arg('user_id')::uuid
+      OR endpoints.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = sqlc.arg('user_id')
+            AND om.role IN ('owner', 'admin')
+      )
+  );
+
+-- name: UpdateEndpointMute :execrows
+-- muted_until이 NULL이면 직접 해제할 때까지 음소거
+UPDATE endpoint_members
+SET notification_enabled = false, 
+  notification_disabled_at = $2,
+  muted_until = $4
+WHERE endpoint_id = $1
+  AND user_id = $3;
+
+-- name: UpdateEndpointUnmute :execrows
+UPDATE endpoint_members
+SET notification_enabled = true, 
+  notification_disabled_at = null,
+  muted_until = null
+WHERE endpoint_id = $1
   AND user_id = $2;
 
--- name: UpdateEndpointMute :exec
+-- name: UnmuteExpiredEndpoints :many
+-- 기한이 지난 음소거를 해제하고 해제된 멤버를 반환
+UPDATE endpoint_members m
+SET notification_enabled = true,
+    notification_disabled_at = null,
+    muted_until = null
+FROM endpoints e
+WHERE e.id = m.endpoint_id
+  AND m.notification_enabled = false
+  AND m.muted_until <= now()
+RETURNING m.endpoint_id, m.user_id, e.name;
+
+-- name: RotateEndpointToken :one
+-- grace_until이 NULL이면 이전 토큰은 즉시 무효. 관리 권한이 없으면 row가 없음
+WITH rotated AS (
+    UPDATE endpoints
+    SET token_hash = sqlc.arg('new_token_hash'),
+        token_prefix = sqlc.arg('new_token_prefix'),
+        previous_token_hash = CASE WHEN sqlc.narg('grace_until')::timestamp IS NULL THEN NULL ELSE endpoints.token_hash END,
+        previous_token_expires_at = sqlc.narg('grace_until'),
+        token = NULL,
+        previous_token = NULL
+    WHERE endpoints.id = sqlc.arg('id')
+      AND (
+          endpoints.user_id = sqlc.arg('user_id')::uuid
+          OR endpoints.org_id IN (
+              SELECT om.org_id FROM organization_members om
+              WHERE om.user_id = sqlc.arg('user_id')
+                AND om.role IN ('owner', 'admin')
+          )
+      )
+    RETURNING endpoints.id, endpoints.previous_token_expires_at
+), logged AS (
+    INSERT INTO endpoint_token_rotations (endpoint_id, rotated_by, grace_until)
+    SELECT rotated.id, sqlc.arg('user_id'), rotated.previous_token_expires_at
+    FROM rotated
+)
+SELECT id, previous_token_expires_at FROM rotated;
+
+-- name: ListPlaintextEndpointTokens :many
+-- 해시로 옮기지 않은 평문 토큰 (backfill용)
+SELECT id, token, previous_token FROM endpoints
+WHERE token IS NOT NULL
+   OR previous_token IS NOT NULL
+LIMIT $1;
+
+-- name: SetEndpointTokenHash :exec
 UPDATE endpoints
-SET notification_enabled = false, 
-  notification_disabled_at = $2
-WHERE token = $1;
+SET token_hash = COALESCE(sqlc.narg('token_hash'), token_hash),
+    token_prefix = COALESCE(sqlc.narg('token_prefix'), token_prefix),
+    previous_token_hash = COALESCE(sqlc.narg('previous_token_hash'), previous_token_hash),
+    token = NULL,
+    previous_token = NULL
+WHERE id = sqlc.arg('id');
 
--- name: UpdateEndpointUnmute :exec
+-- name: ListPlaintextSigningSecrets :many
+-- 암호화하지 않은 서명 secret (backfill용). 'v1.'은 token.Sealer의 형식
+SELECT id, signing_secret::text AS signing_secret FROM endpoints
+WHERE signing_secret IS NOT NULL
+  AND signing_secret NOT LIKE 'v1.%'
+LIMIT $1;
+
+-- name: SealEndpointSigningSecret :exec
+-- 그 사이 secret이 다시 발급되었으면 바꾸지 않는다
 UPDATE endpoints
-SET notification_enabled = true, 
-  notification_disabled_at = null
-WHERE token = $1;
\ No newline at end of file
+SET signing_secret = sqlc.arg('sealed')::text
+WHERE id = sqlc.arg('id')
+  AND signing_secret = sqlc.arg('plaintext')::text;
+
+-- name: UpdateEndpoint :one
+-- NULL인 항목은 변경하지 않음. 빈 문자열이나 0은 설정 해제. 관리 권한이 없으면 row가 없음
+UPDATE endpoints
+SET name = COALESCE(sqlc.narg('name')::text, name),
+    description = CASE WHEN sqlc.narg('description')::text IS NULL THEN description ELSE NULLIF(sqlc.narg('description')::text, '') END,
+    icon = CASE WHEN sqlc.narg('icon')::text IS NULL THEN icon ELSE NULLIF(sqlc.narg('icon')::text, '') END,
+    color = CASE WHEN sqlc.narg('color')::text IS NULL THEN color ELSE NULLIF(sqlc.narg('color')::text, '') END,
+    default_ttl = CASE WHEN sqlc.narg('default_ttl')::int IS NULL THEN default_ttl ELSE NULLIF(sqlc.narg('default_ttl')::int, 0) END,
+    default_urgency = CASE WHEN sqlc.narg('default_urgency')::text IS NULL THEN default_urgency ELSE NULLIF(sqlc.narg('default_urgency')::text, '') END,
+    default_click_url = CASE WHEN sqlc.narg('default_click_url')::text IS NULL THEN default_click_url ELSE NULLIF(sqlc.narg('default_click_url')::text, '') END
+WHERE id = sqlc.arg('id')
+  AND (
+      endpoints.user_id = sqlc.arg('user_id')::uuid
+      OR endpoints.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = sqlc.arg('user_id')
+            AND om.role IN ('owner', 'admin')
+      )
+  )
+RETURNING *;
+
+-- name: UpdateEndpointPolicy :one
+-- 관리 권한이 없으면 row가 없음
+UPDATE endpoints
+SET allowed_cidrs = sqlc.arg('allowed_cidrs')::text[],
+    ask_enabled = sqlc.arg('ask_enabled'),
+    max_ask_timeout = sqlc.narg('max_ask_timeout'),
+    max_body_size = sqlc.narg('max_body_size'),
+    expires_at = sqlc.narg('expires_at')
+WHERE id = sqlc.arg('id')
+  AND (
+      endpoints.user_id = sqlc.arg('user_id')::uuid
+      OR endpoints.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = sqlc.arg('user_id')
+            AND om.role IN ('owner', 'admin')
+      )
+  )
+RETURNING *;
+
+-- name: EnableEndpointSigning :execrows
+-- 이미 활성화된 경우 secret을 새로 발급. 관리 권한이 없으면 row가 없음
+UPDATE endpoints
+SET signing_secret = sqlc.arg('signing_secret'),
+    signing_required = true
+WHERE id = sqlc.arg('id')
+  AND (
+      endpoints.user_id = sqlc.arg('user_id')::uuid
+      OR endpoints.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = sqlc.arg('user_id')
+            AND om.role IN ('owner', 'admin')
+      )
+  );
+
+-- name: DisableEndpointSigning :execrows
+-- 관리 권한이 없으면 row가 없음
+UPDATE endpoints
+SET signing_secret = NULL,
+    signing_required = false
+WHERE id = sqlc.arg('id')
+  AND (
+      endpoints.user_id = sqlc.arg('user_id')::uuid
+      OR endpoints.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = sqlc.arg('user_id')
+            AND om.role IN ('owner', 'admin')
+      )
+  );
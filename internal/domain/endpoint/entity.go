@@ -2,6 +2,7 @@ package endpoint
 
 import (
 	"time"
+	"torchi/internal/domain/common"
 
 	"github.com/google/uuid"
 )
@@ -9,8 +10,75 @@ import (
 type Endpoint struct {
 	ID                 uuid.UUID
 	Name               string
-	Token              string
+	TokenPrefix        string // 토큰 앞자리. 토큰 원문은 발급/재발급 응답에서만 확인 가능
 	CreatedAt          time.Time
-	UserID             uuid.UUID
-	NotificationEnable bool // false의 경우 push하지않고 notification 테이블에만 데이터를 넣음
+	UserID             *uuid.UUID // 개인 endpoint 소유자. 조직 endpoint면 nil
+	OrgID              *uuid.UUID // 조직 endpoint면 소유 조직
+	OrgName            *string    // 목록 조회에서만 채워짐
+	NotificationEnable bool       // 조회한 멤버의 음소거 상태. false의 경우 push하지않고 notification 테이블에만 데이터를 넣음
+	MutedUntil         *time.Time // 기한이 있는 음소거면 자동 해제 시각. 목록 조회에서만 채워짐
+	Role               string     // 조회한 유저의 역할 (RoleOwner, RoleMember). 알림을 받지 않으면 빈 문자열. 목록 조회에서만 채워짐
+	CanManage          bool       // 조회한 유저가 관리할 수 있는지. 목록 조회에서만 채워짐
+
+	Description *string
+	Icon        *string // 알림 아이콘 이미지 URL
+	Color       *string // #RRGGBB
+
+	// push 기본 옵션. nil이면 서버 기본값 사용
+	DefaultTTL      *int
+	DefaultUrgency  *string
+	DefaultClickURL *string
+
+	Policy Policy
+
+	// true면 push/ask에 SigningSecret으로 만든 서명이 필요 (pkg/signature).
+	// DB에는 암호화해서 저장하고 서비스의 FindByToken만 풀어서 채운다
+	SigningRequired bool
+	SigningSecret   *string
+
+	// sender key로 조회한 경우 그 키. endpoint 토큰으로 조회했으면 nil
+	SenderKey *SenderKey
+}
+
+// Allows sender key는 scope에 포함된 요청만 허용.
+// endpoint 토큰(재발급 유예 중인 이전 토큰 포함)은 보내는 요청만 허용하고 read는 받지 않는다.
+// 스크립트, CI에 붙여넣는 토큰으로 민감한 본문과 응답 코멘트까지 읽을 수 없도록 read는 그 scope의 키가 있어야 한다
+func (e *Endpoint) Allows(scope string) bool {
+	if e.SenderKey == nil {
+		return scope != ScopeRead
+	}
+	return e.SenderKey.HasScope(scope)
+}
+
+// CheckScope Allows가 아니면 ErrSenderKeyScope
+func (e *Endpoint) CheckScope(scope string) error {
+	if !e.Allows(scope) {
+		return common.ErrSenderKeyScope
+	}
+	return nil
+}
+
+// AddResult 생성 결과. 토큰은 이 응답에서만 확인할 수 있다.
+type AddResult struct {
+	EndpointID uuid.UUID
+	Token      string
+}
+
+// RotateResult 토큰 재발급 결과. 새 토큰은 이 응답에서만 확인할 수 있다.
+type RotateResult struct {
+	EndpointID             uuid.UUID
+	Token                  string
+	PreviousTokenExpiresAt *time.Time // nil이면 이전 토큰 즉시 무효
+}
+
+// UpdateParams endpoint 설정 변경. nil인 항목은 변경하지 않고,
+// 빈 문자열이나 0은 설정 해제로 취급한다.
+type UpdateParams struct {
+	Name            *string
+	Description     *string
+	Icon            *string
+	Color           *string
+	DefaultTTL      *int
+	DefaultUrgency  *string
+	DefaultClickURL *string
 }
@@ -0,0 +1,76 @@
+package endpoint
+
+import (
+	"time"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+// endpoint_members.role
+const (
+	RoleOwner  = "owner"  // endpoint를 만든 유저. 설정 변경, 초대, 멤버 관리 가능
+	RoleMember = "member" // 초대 링크로 참여. 알림 수신과 본인 음소거만 가능
+)
+
+const (
+	inviteCodeLength = 22
+
+	defaultInviteTTL = 7 * 24 * time.Hour
+	maxInviteTTL     = 30 * 24 * time.Hour
+	maxInviteUses    = 1000
+)
+
+// Member endpoint를 공유받는 유저. 읽음, 삭제, 음소거 상태는 멤버마다 따로 가진다.
+type Member struct {
+	UserID   uuid.UUID
+	Role     string
+	Email    *string
+	Guest    bool
+	JoinedAt time.Time
+}
+
+// hideEmails 관리 권한이 없는 멤버에게는 본인 이메일만 보여준다
+func hideEmails(members []Member, userID uuid.UUID) {
+	for i := range members {
+		if members[i].UserID != userID {
+			members[i].Email = nil
+		}
+	}
+}
+
+// Invite 초대 링크. 코드 원문은 생성 응답에서만 확인할 수 있다.
+type Invite struct {
+	ID           uuid.UUID
+	EndpointID   uuid.UUID
+	EndpointName string
+	CodePrefix   string
+	ExpiresAt    time.Time
+	MaxUses      *int // nil이면 횟수 제한 없음
+	UseCount     int
+	RevokedAt    *time.Time
+	CreatedAt    time.Time
+}
+
+// CheckUsable 폐기, 만료, 사용 횟수 초과면 ErrInviteExpired
+func (i *Invite) CheckUsable(now time.Time) error {
+	if i.RevokedAt != nil || !now.Before(i.ExpiresAt) {
+		return common.ErrInviteExpired
+	}
+	if i.MaxUses != nil && i.UseCount >= *i.MaxUses {
+		return common.ErrInviteExpired
+	}
+	return nil
+}
+
+// CreateInviteResult 생성 결과. 코드는 이 응답에서만 확인할 수 있다.
+type CreateInviteResult struct {
+	Invite
+	Code string
+}
+
+// JoinResult 참여한 endpoint
+type JoinResult struct {
+	EndpointID   uuid.UUID
+	EndpointName string
+}
@@ -0,0 +1,56 @@
+package endpoint
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+func TestInvite_CheckUsable(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	one := 1
+
+	cases := []struct {
+		name   string
+		invite Invite
+		want   error
+	}{
+		{"usable", Invite{ExpiresAt: now.Add(time.Hour)}, nil},
+		{"unlimited uses", Invite{ExpiresAt: now.Add(time.Hour), UseCount: 500}, nil},
+		{"expired", Invite{ExpiresAt: past}, common.ErrInviteExpired},
+		{"expires now", Invite{ExpiresAt: now}, common.ErrInviteExpired},
+		{"revoked", Invite{ExpiresAt: now.Add(time.Hour), RevokedAt: &past}, common.ErrInviteExpired},
+		{"used up", Invite{ExpiresAt: now.Add(time.Hour), MaxUses: &one, UseCount: 1}, common.ErrInviteExpired},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.invite.CheckUsable(now); !errors.Is(err, c.want) {
+				t.Errorf("expected: %v, got: %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestHideEmails(t *testing.T) {
+	me, other := uuid.New(), uuid.New()
+	myEmail, otherEmail := "me@example.com", "other@example.com"
+
+	members := []Member{
+		{UserID: me, Email: &myEmail},
+		{UserID: other, Email: &otherEmail},
+		{UserID: uuid.New(), Guest: true},
+	}
+	hideEmails(members, me)
+
+	if members[0].Email == nil || *members[0].Email != myEmail {
+		t.Errorf("own email should stay: %v", members[0].Email)
+	}
+	if members[1].Email != nil || members[2].Email != nil {
+		t.Errorf("other emails should be hidden: %v, %v", members[1].Email, members[2].Email)
+	}
+}
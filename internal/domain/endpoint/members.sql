@@ -0,0 +1,121 @@
+-- name: ListEndpointMembers :many
+SELECT
+    m.user_id,
+    m.role,
+    m.created_at,
+    u.email,
+    u.guest
+FROM endpoint_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.endpoint_id = $1
+ORDER BY m.created_at;
+
+-- name: IsEndpointMember :one
+SELECT EXISTS (
+    SELECT 1 FROM endpoint_members
+    WHERE endpoint_id = $1
+      AND user_id = $2
+);
+
+-- name: AddEndpointMember :exec
+-- 이미 멤버면 그대로 둔다
+INSERT INTO endpoint_members (endpoint_id, user_id, role)
+VALUES ($1, $2, 'member')
+ON CONFLICT (endpoint_id, user_id) DO NOTHING;
+
+-- name: RemoveEndpointMember :execrows
+-- owner row는 지울 수 없음. 본인이 나가거나 removed_by가 관리 권한이 있어야 한다
+DELETE FROM endpoint_members m
+USING endpoints e
+WHERE m.endpoint_id = sqlc.arg('endpoint_id')
+  AND m.user_id = sqlc.arg('user_id')
+  AND m.role <> 'owner'
+  AND e.id = m.endpoint_id
+  AND (
+      m.user_id = sqlc.arg('removed_by')
+      OR e.user_id = sqlc.arg('removed_by')
+      OR e.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = sqlc.arg('removed_by')
+            AND om.role IN ('owner', 'admin')
+      )
+  );
+
+-- name: CreateEndpointInvite :one
+-- endpoint가 없거나 created_by가 관리 권한이 없으면 row가 없음
+INSERT INTO endpoint_invites (
+    endpoint_id,
+    code_hash,
+    code_prefix,
+    created_by,
+    expires_at,
+    max_uses
+)
+SELECT
+    e.id,
+    sqlc.arg('code_hash'),
+    sqlc.arg('code_prefix'),
+    sqlc.arg('created_by')::uuid,
+    sqlc.arg('expires_at'),
+    sqlc.narg('max_uses')
+FROM endpoints e
+WHERE e.id = sqlc.arg('endpoint_id')
+  AND (
+      e.user_id = sqlc.arg('created_by')
+      OR e.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = sqlc.arg('created_by')
+            AND om.role IN ('owner', 'admin')
+      )
+  )
+RETURNING *;
+
+-- name: ListEndpointInvites :many
+SELECT * FROM endpoint_invites
+WHERE endpoint_id = $1
+ORDER BY id;
+
+-- name: RevokeEndpointInvite :execrows
+-- 관리 권한이 없으면 row가 없음
+UPDATE endpoint_invites i
+SET revoked_at = COALESCE(i.revoked_at, now())
+FROM endpoints e
+WHERE i.id = sqlc.arg('id')
+  AND i.endpoint_id = sqlc.arg('endpoint_id')
+  AND e.id = i.endpoint_id
+  AND (
+      e.user_id = sqlc.arg('user_id')::uuid
+      OR e.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = sqlc.arg('user_id')
+            AND om.role IN ('owner', 'admin')
+      )
+  );
+
+-- name: FindEndpointInviteByCodeHash :one
+SELECT i.*, e.name AS endpoint_name FROM endpoint_invites i
+JOIN endpoints e ON e.id = i.endpoint_id
+WHERE i.code_hash = $1;
+
+-- name: JoinEndpointByInvite :one
+-- 만료, 폐기, 사용 횟수를 확인하고 member로 등록. 새로 등록됐을 때만 사용 횟수를 올린다.
+-- 초대 row를 잠가 마지막 사용 횟수를 두 요청이 함께 쓰지 않는다
+WITH invite AS (
+    SELECT id, endpoint_id FROM endpoint_invites
+    WHERE id = sqlc.arg('invite_id')
+      AND revoked_at IS NULL
+      AND expires_at > now()
+      AND (max_uses IS NULL OR use_count < max_uses)
+    FOR UPDATE
+), joined AS (
+    INSERT INTO endpoint_members (endpoint_id, user_id, role)
+    SELECT endpoint_id, sqlc.arg('user_id'), 'member' FROM invite
+    ON CONFLICT (endpoint_id, user_id) DO NOTHING
+    RETURNING endpoint_id
+), used AS (
+    UPDATE endpoint_invites
+    SET use_count = use_count + 1
+    WHERE id IN (SELECT id FROM invite)
+      AND EXISTS (SELECT 1 FROM joined)
+)
+SELECT endpoint_id FROM joined;
@@ -5,4 +5,7 @@ import "go.uber.org/fx"
 var Module = fx.Options(
 	fx.Provide(NewEndpointService),
 	fx.Provide(NewEndpointRepository),
+	fx.Invoke(RegisterTokenBackfill),
+	fx.Invoke(RegisterSigningSecretBackfill),
+	fx.Invoke(RegisterUnmuteJob),
 )
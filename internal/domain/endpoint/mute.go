@@ -0,0 +1,102 @@
+package endpoint
+
+import (
+	"context"
+	"time"
+	"torchi/internal/domain/sse"
+	"torchi/internal/pkg/log"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+const (
+	maxMuteDuration = 365 * 24 * time.Hour
+	unmuteInterval  = 30 * time.Second
+
+	// 음소거 설정, 해제 SSE 이벤트. 기한이 지나 풀린 경우 endpoint_unmuted의 expired가 true
+	EventEndpointMuted   = "endpoint_muted"
+	EventEndpointUnmuted = "endpoint_unmuted"
+)
+
+// ExpiredMute 기한이 지나 해제된 음소거
+type ExpiredMute struct {
+	EndpointID   uuid.UUID
+	EndpointName string
+	UserID       uuid.UUID
+}
+
+// applyMute 조회한 멤버의 음소거 상태를 채운다. 기한이 지났으면 해제 작업 전이라도 음소거가 아니다
+func (e *Endpoint) applyMute(enabled bool, mutedUntil *time.Time, now time.Time) {
+	if !enabled && mutedUntil != nil && !mutedUntil.After(now) {
+		enabled, mutedUntil = true, nil
+	}
+	e.NotificationEnable = enabled
+	e.MutedUntil = mutedUntil
+	if enabled {
+		e.MutedUntil = nil
+	}
+}
+
+// MuteRemaining 기한이 있는 음소거의 남은 시간. 음소거가 아니거나 기한이 없으면 0
+func (e *Endpoint) MuteRemaining(now time.Time) time.Duration {
+	if e.NotificationEnable || e.MutedUntil == nil {
+		return 0
+	}
+	return max(e.MutedUntil.Sub(now), 0)
+}
+
+// RegisterUnmuteJob 기한이 지난 음소거를 주기적으로 해제하고 멤버에게 SSE로 알린다
+func RegisterUnmuteJob(lc fx.Lifecycle, repo EndpointRepository, broker *sse.Broker, log *log.Logger) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				ticker := time.NewTicker(unmuteInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if err := unmuteExpired(ctx, repo, broker); err != nil {
+							log.Error("endpoint unmute failed", "err", err)
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+func unmuteExpired(ctx context.Context, repo EndpointRepository, broker *sse.Broker) error {
+	expired, err := repo.UnmuteExpired(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range expired {
+		broker.Publish(m.UserID, unmutedEvent(m.EndpointID, m.EndpointName, true))
+	}
+	return nil
+}
+
+// unmutedEvent 직접 해제한 경우 endpoint_name은 비어 있다
+func unmutedEvent(endpointID uuid.UUID, endpointName string, expired bool) sse.SSEEvent {
+	return sse.SSEEvent{
+		Event: EventEndpointUnmuted,
+		Data: map[string]any{
+			"endpoint_id":   endpointID,
+			"endpoint_name": endpointName,
+			"expired":       expired,
+		},
+		EndpointID: &endpointID,
+	}
+}
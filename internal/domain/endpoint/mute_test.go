@@ -0,0 +1,71 @@
+package endpoint
+
+import (
+	"context"
+	"testing"
+	"time"
+	"torchi/internal/domain/sse"
+	"torchi/internal/pkg/token"
+
+	"github.com/google/uuid"
+)
+
+func TestEndpoint_ApplyMute(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Second), now.Add(time.Hour)
+
+	cases := []struct {
+		name       string
+		enabled    bool
+		mutedUntil *time.Time
+		wantEnable bool
+		wantRemain time.Duration
+	}{
+		{"not muted", true, nil, true, 0},
+		{"muted indefinitely", false, nil, false, 0},
+		{"muted until future", false, &future, false, time.Hour},
+		{"mute expired", false, &past, true, 0},
+		{"expires now", false, &now, true, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var e Endpoint
+			e.applyMute(c.enabled, c.mutedUntil, now)
+
+			if e.NotificationEnable != c.wantEnable {
+				t.Errorf("enable expected: %v, got: %v", c.wantEnable, e.NotificationEnable)
+			}
+			if got := e.MuteRemaining(now); got != c.wantRemain {
+				t.Errorf("remaining expected: %v, got: %v", c.wantRemain, got)
+			}
+		})
+	}
+}
+
+// muteRepo UpdateMute만 쓰는 가짜 저장소
+type muteRepo struct {
+	EndpointRepository
+	until *time.Time
+}
+
+func (r *muteRepo) UpdateMute(_ context.Context, _, _ uuid.UUID, _, mutedUntil *time.Time) (int64, error) {
+	r.until = mutedUntil
+	return 1, nil
+}
+
+func TestUpdateMute_UntilUTC(t *testing.T) {
+	repo := &muteRepo{}
+	s := &EndpointService{repo: repo, broker: sse.NewBroker()}
+	ctx := token.ContextWith(context.Background(), &token.Claims{UserID: uuid.New()})
+
+	kst := time.FixedZone("KST", 9*60*60)
+	until := time.Now().Add(time.Hour).In(kst).Truncate(time.Second)
+	if err := s.UpdateMute(ctx, uuid.New(), false, &until); err != nil {
+		t.Fatal(err)
+	}
+
+	if repo.until.Location() != time.UTC || !repo.until.Equal(until) {
+		t.Errorf("expected %v in UTC, got: %v", until, repo.until)
+	}
+}
@@ -0,0 +1,100 @@
+package endpoint
+
+import (
+	"net/netip"
+	"time"
+	"torchi/internal/domain/common"
+)
+
+const (
+	maxAllowedCIDRs = 50
+
+	// 정책으로 지정할 수 있는 상한
+	maxPolicyAskTimeout = 24 * 60 * 60
+	maxPolicyBodySize   = 1 << 20
+)
+
+// Policy endpoint 토큰으로 할 수 있는 일을 제한한다.
+// 기본값(zero 값 + AskEnabled true)은 제한 없음.
+type Policy struct {
+	AllowedCIDRs  []string   // 비어있으면 모든 IP 허용
+	AskEnabled    bool       // false면 /ask 불가
+	MaxAskTimeout *int       // 초. nil이면 서버 기본값
+	MaxBodySize   *int       // byte. nil이면 제한 없음
+	ExpiresAt     *time.Time // 이후로는 push/ask 불가
+}
+
+// CheckAccess 만료 여부와 요청 IP를 확인
+func (p Policy) CheckAccess(clientIP string, now time.Time) error {
+	if p.ExpiresAt != nil && !now.Before(*p.ExpiresAt) {
+		return common.ErrEndpointExpired
+	}
+	if len(p.AllowedCIDRs) == 0 {
+		return nil
+	}
+
+	addr, err := netip.ParseAddr(clientIP)
+	if err != nil {
+		return common.ErrSourceIPNotAllowed
+	}
+	addr = addr.Unmap()
+	for _, cidr := range p.AllowedCIDRs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			continue
+		}
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	return common.ErrSourceIPNotAllowed
+}
+
+// CheckAsk ask 허용 여부와 요청한 대기 시간(초)을 확인. 0 이하면 바로 끝나버리므로 ErrInvalidParam
+func (p Policy) CheckAsk(timeout int) error {
+	if !p.AskEnabled {
+		return common.ErrAskNotAllowed
+	}
+	if timeout <= 0 {
+		return common.ErrInvalidParam
+	}
+	if p.MaxAskTimeout != nil && timeout > *p.MaxAskTimeout {
+		return common.ErrAskTimeoutExceeded
+	}
+	return nil
+}
+
+// normalize 입력값 검증. 단일 IP는 /32(/128)로 변환한다.
+func (p *Policy) normalize() error {
+	if len(p.AllowedCIDRs) > maxAllowedCIDRs {
+		return common.ErrInvalidParam
+	}
+
+	cidrs := make([]string, 0, len(p.AllowedCIDRs))
+	for _, raw := range p.AllowedCIDRs {
+		prefix, err := netip.ParsePrefix(raw)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(raw)
+			if addrErr != nil {
+				return common.ErrInvalidParam
+			}
+			addr = addr.Unmap()
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		cidrs = append(cidrs, prefix.Masked().String())
+	}
+	p.AllowedCIDRs = cidrs
+
+	if p.MaxAskTimeout != nil && (*p.MaxAskTimeout <= 0 || *p.MaxAskTimeout > maxPolicyAskTimeout) {
+		return common.ErrInvalidParam
+	}
+	if p.MaxBodySize != nil && (*p.MaxBodySize <= 0 || *p.MaxBodySize > maxPolicyBodySize) {
+		return common.ErrInvalidParam
+	}
+	// TIMESTAMP 컬럼은 오프셋을 버리고 시각만 저장하므로 UTC로 맞춘다
+	if p.ExpiresAt != nil {
+		expiresAt := p.ExpiresAt.UTC()
+		p.ExpiresAt = &expiresAt
+	}
+	return nil
+}
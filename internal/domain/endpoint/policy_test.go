@@ -0,0 +1,87 @@
+package endpoint
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"torchi/internal/domain/common"
+)
+
+func TestPolicy_CheckAccess(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+
+	cases := []struct {
+		name   string
+		policy Policy
+		ip     string
+		want   error
+	}{
+		{"no restriction", Policy{AskEnabled: true}, "203.0.113.7", nil},
+		{"allowed cidr", Policy{AllowedCIDRs: []string{"10.0.0.0/8"}}, "10.1.2.3", nil},
+		{"ipv4 mapped ipv6", Policy{AllowedCIDRs: []string{"10.0.0.0/8"}}, "::ffff:10.1.2.3", nil},
+		{"outside cidr", Policy{AllowedCIDRs: []string{"10.0.0.0/8"}}, "192.168.0.1", common.ErrSourceIPNotAllowed},
+		{"invalid ip", Policy{AllowedCIDRs: []string{"10.0.0.0/8"}}, "unknown", common.ErrSourceIPNotAllowed},
+		{"expired", Policy{ExpiresAt: &past}, "10.1.2.3", common.ErrEndpointExpired},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.policy.CheckAccess(c.ip, now); !errors.Is(err, c.want) {
+				t.Errorf("expected: %v, got: %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_CheckAsk(t *testing.T) {
+	maxTimeout := 60
+
+	if err := (Policy{AskEnabled: false}).CheckAsk(10); !errors.Is(err, common.ErrAskNotAllowed) {
+		t.Errorf("expected ask not allowed, got: %v", err)
+	}
+	if err := (Policy{AskEnabled: true, MaxAskTimeout: &maxTimeout}).CheckAsk(61); !errors.Is(err, common.ErrAskTimeoutExceeded) {
+		t.Errorf("expected timeout exceeded, got: %v", err)
+	}
+	if err := (Policy{AskEnabled: true, MaxAskTimeout: &maxTimeout}).CheckAsk(60); err != nil {
+		t.Errorf("expected nil, got: %v", err)
+	}
+	for _, timeout := range []int{0, -1} {
+		if err := (Policy{AskEnabled: true}).CheckAsk(timeout); !errors.Is(err, common.ErrInvalidParam) {
+			t.Errorf("timeout %d expected invalid param, got: %v", timeout, err)
+		}
+	}
+}
+
+func TestPolicy_Normalize(t *testing.T) {
+	p := Policy{AllowedCIDRs: []string{"10.1.2.3/8", "192.168.0.1", "2001:db8::1"}}
+	if err := p.normalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"10.0.0.0/8", "192.168.0.1/32", "2001:db8::1/128"}
+	for i := range want {
+		if p.AllowedCIDRs[i] != want[i] {
+			t.Errorf("expected: %s, got: %s", want[i], p.AllowedCIDRs[i])
+		}
+	}
+
+	bad := Policy{AllowedCIDRs: []string{"not-an-ip"}}
+	if err := bad.normalize(); !errors.Is(err, common.ErrInvalidParam) {
+		t.Errorf("expected invalid param, got: %v", err)
+	}
+}
+
+func TestPolicy_NormalizeExpiresAtUTC(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	expiresAt := time.Date(2026, 12, 31, 0, 0, 0, 0, kst)
+	p := Policy{ExpiresAt: &expiresAt}
+	if err := p.normalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := time.Date(2026, 12, 30, 15, 0, 0, 0, time.UTC)
+	if p.ExpiresAt.Location() != time.UTC || !p.ExpiresAt.Equal(want) {
+		t.Errorf("expected: %v, got: %v", want, p.ExpiresAt)
+	}
+}
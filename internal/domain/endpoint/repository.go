@@ -4,40 +4,121 @@ import (
 	"context"
 	"errors"
 	"time"
+	"torchi/internal/domain/common"
 	db "torchi/internal/infrastructure/db/postgresql"
+	"torchi/internal/pkg"
+	"torchi/internal/pkg/token"
 
 	"github.com/google/uuid"
 )
 
 var ErrDuplicateToken = errors.New("duplicate endpoint token")
 
+const (
+	nameUniqConstraint    = "endpoints_user_name_uniq"
+	orgNameUniqConstraint = "endpoints_org_name_uniq"
+)
+
 type EndpointRepository interface {
-	Add(ctx context.Context, params insertEndpointParams) error
+	Add(ctx context.Context, params insertEndpointParams) (uuid.UUID, error)
+	// 소유하거나 참여한 endpoint. Role, CanManage, 음소거 상태는 userID 기준
 	FindByUserID(ctx context.Context, userID uuid.UUID) ([]Endpoint, error)
-	RemoveByToken(ctx context.Context, token string, userID uuid.UUID) error
-	FindByToken(ctx context.Context, token string) (*Endpoint, error)
-	UpdateMute(ctx context.Context, token string, disabledAt *time.Time) error
-	UpdateUnmute(ctx context.Context, token string) error
+	// 조직의 모든 endpoint. 알림을 받지 않는 endpoint는 Role이 빈 문자열
+	FindByOrgID(ctx context.Context, orgID, userID uuid.UUID) ([]Endpoint, error)
+	// endpoint가 없으면 nil
+	FindAccess(ctx context.Context, id, userID uuid.UUID) (*Access, error)
+	// 개인 endpoint가 아니거나, userID가 소유자이면서 대상 조직의 owner/admin이 아니면 false
+	TransferToOrg(ctx context.Context, id, orgID, userID uuid.UUID) (bool, error)
+	// since 이후 합계와 발송이 있는 날의 일별 집계
+	Stats(ctx context.Context, id uuid.UUID, since time.Time) (*Stats, error)
+
+	// 권한 확인은 서비스에서 한다. 변경 메서드는 영향받은 row 수를 반환.
+	// 관리용 변경은 확인 이후 권한이 바뀐 경우를 막기 위해 userID가 관리할 수 있는 endpoint만 바꾼다
+	Remove(ctx context.Context, id, userID uuid.UUID) (int64, error)
+	// tokenHash로 조회. 재발급 유예 중인 이전 토큰도 포함
+	FindByToken(ctx context.Context, tokenHash string) (*Endpoint, error)
+	// mutedUntil이 nil이면 직접 해제할 때까지 음소거
+	UpdateMute(ctx context.Context, id, userID uuid.UUID, disabledAt, mutedUntil *time.Time) (int64, error)
+	UpdateUnmute(ctx context.Context, id, userID uuid.UUID) (int64, error)
+	UnmuteExpired(ctx context.Context) ([]ExpiredMute, error)
+	RotateToken(ctx context.Context, params rotateTokenParams) (*RotateResult, error)
+	Update(ctx context.Context, params updateEndpointParams) (*Endpoint, error)
+	UpdatePolicy(ctx context.Context, id, userID uuid.UUID, policy Policy) (*Endpoint, error)
+	EnableSigning(ctx context.Context, id, userID uuid.UUID, secret string) (int64, error)
+	DisableSigning(ctx context.Context, id, userID uuid.UUID) (int64, error)
+
+	ListPlaintextTokens(ctx context.Context, limit int) ([]plaintextToken, error)
+	SetTokenHash(ctx context.Context, params setTokenHashParams) error
+	ListPlaintextSigningSecrets(ctx context.Context, limit int) ([]plaintextSecret, error)
+	// plaintext가 그대로 남아 있을 때만 sealed로 바꾼다
+	SealSigningSecret(ctx context.Context, id uuid.UUID, plaintext, sealed string) error
+
+	// endpoint가 없거나 관리 권한이 없거나 살아있는 키가 이미 maxKeys개면 nil
+	AddSenderKey(ctx context.Context, params insertSenderKeyParams) (*SenderKey, error)
+	ListSenderKeys(ctx context.Context, endpointID uuid.UUID) ([]SenderKey, error)
+	RevokeSenderKey(ctx context.Context, id, endpointID, userID uuid.UUID) (int64, error)
+	// 폐기되지 않은 sender key로 조회. Endpoint.SenderKey가 채워진다
+	FindBySenderKey(ctx context.Context, keyHash string) (*Endpoint, error)
+	TouchSenderKey(ctx context.Context, id uuid.UUID) error
+
+	ListMembers(ctx context.Context, endpointID uuid.UUID) ([]Member, error)
+	IsMember(ctx context.Context, endpointID, userID uuid.UUID) (bool, error)
+	// 이미 멤버면 그대로 둔다
+	AddMember(ctx context.Context, endpointID, userID uuid.UUID) error
+	// owner row는 지울 수 없다. 본인이 나가거나 removedBy가 관리 권한이 있어야 한다
+	RemoveMember(ctx context.Context, endpointID, memberID, removedBy uuid.UUID) (int64, error)
+
+	// endpoint가 없거나 관리 권한이 없으면 nil
+	AddInvite(ctx context.Context, params insertInviteParams) (*Invite, error)
+	ListInvites(ctx context.Context, endpointID uuid.UUID) ([]Invite, error)
+	RevokeInvite(ctx context.Context, id, endpointID, userID uuid.UUID) (int64, error)
+	FindInviteByCode(ctx context.Context, codeHash string) (*Invite, error)
+	// 사용할 수 없는 초대이거나 이미 멤버면 false. 새로 참여했을 때만 사용 횟수가 오른다
+	Join(ctx context.Context, inviteID, userID uuid.UUID) (bool, error)
 }
 
 type endpointRepository struct {
 	queries *db.Queries
+	// 여러 쿼리를 한 트랜잭션으로 묶을 때 (AddSenderKey)
+	database *db.Database
 }
 
-func NewEndpointRepository(queries *db.Queries) EndpointRepository {
+func NewEndpointRepository(queries *db.Queries, database *db.Database) EndpointRepository {
 	return &endpointRepository{
-		queries: queries,
+		queries:  queries,
+		database: database,
 	}
 }
 
-func (r *endpointRepository) UpdateMute(ctx context.Context, token string, disabledAt *time.Time) error {
+func (r *endpointRepository) UpdateMute(ctx context.Context, id, userID uuid.UUID, disabledAt, mutedUntil *time.Time) (int64, error) {
 	return r.queries.UpdateEndpointMute(ctx, db.UpdateEndpointMuteParams{
-		Token:                  token,
+		EndpointID:             id,
+		UserID:                 userID,
 		NotificationDisabledAt: disabledAt,
+		MutedUntil:             mutedUntil,
 	})
 }
-func (r *endpointRepository) UpdateUnmute(ctx context.Context, token string) error {
-	return r.queries.UpdateEndpointUnmute(ctx, token)
+func (r *endpointRepository) UpdateUnmute(ctx context.Context, id, userID uuid.UUID) (int64, error) {
+	return r.queries.UpdateEndpointUnmute(ctx, db.UpdateEndpointUnmuteParams{
+		EndpointID: id,
+		UserID:     userID,
+	})
+}
+func (r *endpointRepository) UnmuteExpired(ctx context.Context) ([]ExpiredMute, error) {
+	rows, err := r.queries.UnmuteExpiredEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ExpiredMute, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, ExpiredMute{
+			EndpointID:   row.EndpointID,
+			EndpointName: row.Name,
+			UserID:       row.UserID,
+		})
+	}
+	return result, nil
 }
 func (r *endpointRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]Endpoint, error) {
 	endpoints, err := r.queries.FindEndpointByUserID(ctx, userID)
@@ -45,22 +126,50 @@ func (r *endpointRepository) FindByUserID(ctx context.Context, userID uuid.UUID)
 		return nil, err
 	}
 
+	now := time.Now()
 	var result []Endpoint
-	for _, endpoint := range endpoints {
-		result = append(result, Endpoint{
-			ID:                 endpoint.ID,
-			Name:               endpoint.Name,
-			Token:              endpoint.Token,
-			CreatedAt:          endpoint.CreatedAt,
-			UserID:             endpoint.UserID,
-			NotificationEnable: endpoint.NotificationEnabled,
-		})
+	for _, row := range endpoints {
+		e := toEntity(row.Endpoint)
+		e.Role = row.Role
+		e.applyMute(row.MemberNotificationEnabled, row.MemberMutedUntil, now)
+		e.OrgName = row.OrgName
+		access := Access{
+			OwnerID:  row.Endpoint.UserID,
+			OrgID:    row.Endpoint.OrgID,
+			OrgRole:  pkg.SafeDereference(row.OrgRole),
+			IsMember: true,
+		}
+		e.CanManage = access.CanManage(userID)
+		result = append(result, *e)
 	}
 	return result, nil
 }
-func (r *endpointRepository) FindByToken(ctx context.Context, token string) (*Endpoint, error) {
-	rowData, err := r.queries.FindEndpointByToken(ctx, token)
 
+func (r *endpointRepository) FindByOrgID(ctx context.Context, orgID, userID uuid.UUID) ([]Endpoint, error) {
+	rows, err := r.queries.FindEndpointByOrgID(ctx, db.FindEndpointByOrgIDParams{
+		UserID: userID,
+		OrgID:  &orgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]Endpoint, 0, len(rows))
+	for _, row := range rows {
+		e := toEntity(row.Endpoint)
+		e.Role = pkg.SafeDereference(row.MemberRole)
+		e.applyMute(pkg.SafeDereference(row.MemberNotificationEnabled), row.MemberMutedUntil, now)
+		result = append(result, *e)
+	}
+	return result, nil
+}
+
+func (r *endpointRepository) FindAccess(ctx context.Context, id, userID uuid.UUID) (*Access, error) {
+	row, err := r.queries.FindEndpointAccess(ctx, db.FindEndpointAccessParams{
+		UserID: userID,
+		ID:     id,
+	})
 	if err != nil {
 		if db.IsNoRows(err) {
 			return nil, nil
@@ -68,46 +177,618 @@ func (r *endpointRepository) FindByToken(ctx context.Context, token string) (*En
 		return nil, err
 	}
 
-	return &Endpoint{
-		ID:                 rowData.ID,
-		Name:               rowData.Name,
-		Token:              rowData.Token,
-		CreatedAt:          rowData.CreatedAt,
-		UserID:             rowData.UserID,
-		NotificationEnable: rowData.NotificationEnabled,
+	return &Access{
+		OwnerID:  row.UserID,
+		OrgID:    row.OrgID,
+		OrgRole:  pkg.SafeDereference(row.OrgRole),
+		IsMember: row.IsMember,
 	}, nil
 }
 
+func (r *endpointRepository) TransferToOrg(ctx context.Context, id, orgID, userID uuid.UUID) (bool, error) {
+	_, err := r.queries.TransferEndpointToOrg(ctx, db.TransferEndpointToOrgParams{
+		OrgID:  &orgID,
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if db.IsNoRows(err) {
+			return false, nil
+		}
+		if db.IsUniqueViolationOn(err, orgNameUniqConstraint) {
+			return false, common.ErrEndpointNameConflict
+		}
+		return false, err
+	}
+	return true, nil
+}
+func (r *endpointRepository) FindByToken(ctx context.Context, tokenHash string) (*Endpoint, error) {
+	rowData, err := r.queries.FindEndpointByTokenHash(ctx, &tokenHash)
+
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toEntity(rowData), nil
+}
+
 type insertEndpointParams struct {
-	userID      uuid.UUID
+	createdBy   uuid.UUID
+	orgID       *uuid.UUID // nil이면 createdBy의 개인 endpoint
 	serviceName string
-	endpoint    string
+	tokenHash   string
+	tokenPrefix string
+}
+
+func (r *endpointRepository) Add(ctx context.Context, params insertEndpointParams) (uuid.UUID, error) {
+	var owner *uuid.UUID
+	if params.orgID == nil {
+		owner = &params.createdBy
+	}
+
+	id, err := r.queries.CreateEndpoint(ctx, db.CreateEndpointParams{
+		UserID:      owner,
+		OrgID:       params.orgID,
+		Name:        params.serviceName,
+		TokenHash:   &params.tokenHash,
+		TokenPrefix: &params.tokenPrefix,
+		CreatedBy:   params.createdBy,
+	})
+
+	if err != nil {
+		if isNameConflict(err) {
+			return uuid.Nil, common.ErrEndpointNameConflict
+		}
+		if db.IsUniqueViolation(err) {
+			return uuid.Nil, ErrDuplicateToken
+		}
+		return uuid.Nil, err
+
+	}
+	return id, nil
 }
 
-func (r *endpointRepository) Add(ctx context.Context, params insertEndpointParams) error {
-	_, err := r.queries.CreateEndpoint(ctx, db.CreateEndpointParams{
-		UserID: params.userID,
-		Name:   params.serviceName,
-		Token:  params.endpoint,
+func (r *endpointRepository) Remove(ctx context.Context, id, userID uuid.UUID) (int64, error) {
+	return r.queries.DeleteEndpoint(ctx, db.DeleteEndpointParams{
+		ID:     id,
+		UserID: userID,
 	})
+}
+
+type rotateTokenParams struct {
+	id             uuid.UUID
+	userID         uuid.UUID // 재발급한 유저 (권한 확인, 이력 기록)
+	newTokenHash   string
+	newTokenPrefix string
+	graceUntil     *time.Time
+}
 
+// RotateToken endpoint가 없으면 nil 반환
+func (r *endpointRepository) RotateToken(ctx context.Context, params rotateTokenParams) (*RotateResult, error) {
+	row, err := r.queries.RotateEndpointToken(ctx, db.RotateEndpointTokenParams{
+		NewTokenHash:   &params.newTokenHash,
+		NewTokenPrefix: &params.newTokenPrefix,
+		GraceUntil:     params.graceUntil,
+		ID:             params.id,
+		UserID:         params.userID,
+	})
 	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, nil
+		}
 		if db.IsUniqueViolation(err) {
-			return ErrDuplicateToken
+			return nil, ErrDuplicateToken
 		}
-		return err
+		return nil, err
+	}
+
+	return &RotateResult{
+		EndpointID:             row.ID,
+		PreviousTokenExpiresAt: row.PreviousTokenExpiresAt,
+	}, nil
+}
+
+type updateEndpointParams struct {
+	id     uuid.UUID
+	userID uuid.UUID
+	UpdateParams
+}
+
+// Update endpoint가 없거나 관리 권한이 없으면 nil 반환
+func (r *endpointRepository) Update(ctx context.Context, params updateEndpointParams) (*Endpoint, error) {
+	row, err := r.queries.UpdateEndpoint(ctx, db.UpdateEndpointParams{
+		ID:              params.id,
+		Name:            params.Name,
+		Description:     params.Description,
+		Icon:            params.Icon,
+		Color:           params.Color,
+		DefaultTtl:      toInt32Ptr(params.DefaultTTL),
+		DefaultUrgency:  params.DefaultUrgency,
+		DefaultClickUrl: params.DefaultClickURL,
+		UserID:          params.userID,
+	})
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, nil
+		}
+		if isNameConflict(err) {
+			return nil, common.ErrEndpointNameConflict
+		}
+		return nil, err
+	}
 
+	return toEntity(row), nil
+}
+
+// UpdatePolicy endpoint가 없거나 관리 권한이 없으면 nil 반환
+func (r *endpointRepository) UpdatePolicy(ctx context.Context, id, userID uuid.UUID, policy Policy) (*Endpoint, error) {
+	cidrs := policy.AllowedCIDRs
+	if cidrs == nil {
+		cidrs = []string{}
 	}
-	return nil
+
+	row, err := r.queries.UpdateEndpointPolicy(ctx, db.UpdateEndpointPolicyParams{
+		ID:            id,
+		AllowedCidrs:  cidrs,
+		AskEnabled:    policy.AskEnabled,
+		MaxAskTimeout: toInt32Ptr(policy.MaxAskTimeout),
+		MaxBodySize:   toInt32Ptr(policy.MaxBodySize),
+		ExpiresAt:     policy.ExpiresAt,
+		UserID:        userID,
+	})
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toEntity(row), nil
+}
+
+func (r *endpointRepository) EnableSigning(ctx context.Context, id, userID uuid.UUID, secret string) (int64, error) {
+	return r.queries.EnableEndpointSigning(ctx, db.EnableEndpointSigningParams{
+		ID:            id,
+		UserID:        userID,
+		SigningSecret: &secret,
+	})
 }
 
-func (r *endpointRepository) RemoveByToken(ctx context.Context, token string, userID uuid.UUID) error {
-	err := r.queries.DeleteEndpointByToken(ctx, db.DeleteEndpointByTokenParams{
-		Token:  token,
+func (r *endpointRepository) DisableSigning(ctx context.Context, id, userID uuid.UUID) (int64, error) {
+	return r.queries.DisableEndpointSigning(ctx, db.DisableEndpointSigningParams{
+		ID:     id,
 		UserID: userID,
 	})
+}
+
+// plaintextToken 해시로 옮기지 않은 기존 토큰
+type plaintextToken struct {
+	id            uuid.UUID
+	token         *string
+	previousToken *string
+}
+
+func (r *endpointRepository) ListPlaintextTokens(ctx context.Context, limit int) ([]plaintextToken, error) {
+	rows, err := r.queries.ListPlaintextEndpointTokens(ctx, int32(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]plaintextToken, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, plaintextToken{
+			id:            row.ID,
+			token:         row.Token,
+			previousToken: row.PreviousToken,
+		})
+	}
+	return result, nil
+}
+
+type setTokenHashParams struct {
+	id                uuid.UUID
+	tokenHash         *string
+	tokenPrefix       *string
+	previousTokenHash *string
+}
+
+// SetTokenHash 해시를 저장하고 평문 토큰은 지운다
+func (r *endpointRepository) SetTokenHash(ctx context.Context, params setTokenHashParams) error {
+	return r.queries.SetEndpointTokenHash(ctx, db.SetEndpointTokenHashParams{
+		ID:                params.id,
+		TokenHash:         params.tokenHash,
+		TokenPrefix:       params.tokenPrefix,
+		PreviousTokenHash: params.previousTokenHash,
+	})
+}
+
+// plaintextSecret 암호화하지 않은 기존 서명 secret
+type plaintextSecret struct {
+	id     uuid.UUID
+	secret string
+}
+
+func (r *endpointRepository) ListPlaintextSigningSecrets(ctx context.Context, limit int) ([]plaintextSecret, error) {
+	rows, err := r.queries.ListPlaintextSigningSecrets(ctx, int32(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]plaintextSecret, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, plaintextSecret{id: row.ID, secret: row.SigningSecret})
+	}
+	return result, nil
+}
+
+func (r *endpointRepository) SealSigningSecret(ctx context.Context, id uuid.UUID, plaintext, sealed string) error {
+	return r.queries.SealEndpointSigningSecret(ctx, db.SealEndpointSigningSecretParams{
+		Sealed:    sealed,
+		ID:        id,
+		Plaintext: plaintext,
+	})
+}
+
+type insertSenderKeyParams struct {
+	endpointID uuid.UUID
+	userID     uuid.UUID // 발급한 유저 (권한 확인)
+	label      string
+	keyHash    string
+	keyPrefix  string
+	scopes     []string
+	maxKeys    int // 살아있는 키가 이만큼 있으면 발급하지 않는다
+}
+
+// AddSenderKey endpoint를 잠근 뒤 개수를 세고 넣어 동시에 발급해도 maxKeys를 넘지 않는다
+func (r *endpointRepository) AddSenderKey(ctx context.Context, params insertSenderKeyParams) (*SenderKey, error) {
+	tx, err := r.database.Begin(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.queries.WithTx(tx)
+	if err := q.LockEndpointForSenderKeys(ctx, params.endpointID); err != nil {
+		return nil, err
+	}
+
+	row, err := q.CreateSenderKey(ctx, db.CreateSenderKeyParams{
+		Label:      params.label,
+		KeyHash:    params.keyHash,
+		KeyPrefix:  params.keyPrefix,
+		Scopes:     params.scopes,
+		EndpointID: params.endpointID,
+		UserID:     params.userID,
+		MaxKeys:    int32(params.maxKeys),
+	})
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, nil
+		}
+		if db.IsUniqueViolation(err) {
+			return nil, ErrDuplicateToken
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return toSenderKey(row), nil
+}
+
+func (r *endpointRepository) ListSenderKeys(ctx context.Context, endpointID uuid.UUID) ([]SenderKey, error) {
+	rows, err := r.queries.ListSenderKeys(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SenderKey, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, *toSenderKey(row))
+	}
+	return result, nil
+}
+
+func (r *endpointRepository) RevokeSenderKey(ctx context.Context, id, endpointID, userID uuid.UUID) (int64, error) {
+	return r.queries.RevokeSenderKey(ctx, db.RevokeSenderKeyParams{
+		ID:         id,
+		EndpointID: endpointID,
+		UserID:     userID,
+	})
+}
+
+func (r *endpointRepository) FindBySenderKey(ctx context.Context, keyHash string) (*Endpoint, error) {
+	row, err := r.queries.FindEndpointBySenderKeyHash(ctx, keyHash)
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	e := toEntity(row.Endpoint)
+	e.SenderKey = &SenderKey{
+		ID:         row.SenderKeyID,
+		EndpointID: row.Endpoint.ID,
+		Label:      row.SenderKeyLabel,
+		Scopes:     row.SenderKeyScopes,
+	}
+	return e, nil
+}
+
+func (r *endpointRepository) TouchSenderKey(ctx context.Context, id uuid.UUID) error {
+	return r.queries.TouchSenderKey(ctx, id)
+}
+
+func (r *endpointRepository) ListMembers(ctx context.Context, endpointID uuid.UUID) ([]Member, error) {
+	rows, err := r.queries.ListEndpointMembers(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Member, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, Member{
+			UserID:   row.UserID,
+			Role:     row.Role,
+			Email:    row.Email,
+			Guest:    row.Guest,
+			JoinedAt: row.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (r *endpointRepository) IsMember(ctx context.Context, endpointID, userID uuid.UUID) (bool, error) {
+	return r.queries.IsEndpointMember(ctx, db.IsEndpointMemberParams{
+		EndpointID: endpointID,
+		UserID:     userID,
+	})
+}
+
+func (r *endpointRepository) AddMember(ctx context.Context, endpointID, userID uuid.UUID) error {
+	return r.queries.AddEndpointMember(ctx, db.AddEndpointMemberParams{
+		EndpointID: endpointID,
+		UserID:     userID,
+	})
+}
+
+func (r *endpointRepository) RemoveMember(ctx context.Context, endpointID, memberID, removedBy uuid.UUID) (int64, error) {
+	return r.queries.RemoveEndpointMember(ctx, db.RemoveEndpointMemberParams{
+		EndpointID: endpointID,
+		UserID:     memberID,
+		RemovedBy:  removedBy,
+	})
+}
+
+type insertInviteParams struct {
+	endpointID uuid.UUID
+	createdBy  uuid.UUID // 권한 확인도 이 유저로
+	codeHash   string
+	codePrefix string
+	expiresAt  time.Time
+	maxUses    *int
+}
+
+func (r *endpointRepository) AddInvite(ctx context.Context, params insertInviteParams) (*Invite, error) {
+	row, err := r.queries.CreateEndpointInvite(ctx, db.CreateEndpointInviteParams{
+		CodeHash:   params.codeHash,
+		CodePrefix: params.codePrefix,
+		CreatedBy:  params.createdBy,
+		ExpiresAt:  params.expiresAt,
+		MaxUses:    toInt32Ptr(params.maxUses),
+		EndpointID: params.endpointID,
+	})
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, nil
+		}
+		if db.IsUniqueViolation(err) {
+			return nil, ErrDuplicateToken
+		}
+		return nil, err
+	}
+
+	return toInvite(row), nil
+}
+
+func (r *endpointRepository) ListInvites(ctx context.Context, endpointID uuid.UUID) ([]Invite, error) {
+	rows, err := r.queries.ListEndpointInvites(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Invite, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, *toInvite(row))
+	}
+	return result, nil
+}
+
+func (r *endpointRepository) RevokeInvite(ctx context.Context, id, endpointID, userID uuid.UUID) (int64, error) {
+	return r.queries.RevokeEndpointInvite(ctx, db.RevokeEndpointInviteParams{
+		ID:         id,
+		EndpointID: endpointID,
+		UserID:     userID,
+	})
+}
+
+func (r *endpointRepository) FindInviteByCode(ctx context.Context, codeHash string) (*Invite, error) {
+	row, err := r.queries.FindEndpointInviteByCodeHash(ctx, codeHash)
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	invite := toInvite(db.EndpointInvite{
+		ID:         row.ID,
+		EndpointID: row.EndpointID,
+		CodeHash:   row.CodeHash,
+		CodePrefix: row.CodePrefix,
+		CreatedBy:  row.CreatedBy,
+		ExpiresAt:  row.ExpiresAt,
+		MaxUses:    row.MaxUses,
+		UseCount:   row.UseCount,
+		RevokedAt:  row.RevokedAt,
+		CreatedAt:  row.CreatedAt,
+	})
+	invite.EndpointName = row.EndpointName
+	return invite, nil
+}
+
+func (r *endpointRepository) Join(ctx context.Context, inviteID, userID uuid.UUID) (bool, error) {
+	_, err := r.queries.JoinEndpointByInvite(ctx, db.JoinEndpointByInviteParams{
+		InviteID: inviteID,
+		UserID:   userID,
+	})
+	if err != nil {
+		if db.IsNoRows(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func toInvite(row db.EndpointInvite) *Invite {
+	return &Invite{
+		ID:         row.ID,
+		EndpointID: row.EndpointID,
+		CodePrefix: row.CodePrefix,
+		ExpiresAt:  row.ExpiresAt,
+		MaxUses:    int32Ptr(row.MaxUses),
+		UseCount:   int(row.UseCount),
+		RevokedAt:  row.RevokedAt,
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+func toSenderKey(row db.EndpointSenderKey) *SenderKey {
+	return &SenderKey{
+		ID:         row.ID,
+		EndpointID: row.EndpointID,
+		Label:      row.Label,
+		KeyPrefix:  row.KeyPrefix,
+		Scopes:     row.Scopes,
+		CreatedAt:  row.CreatedAt,
+		LastUsedAt: row.LastUsedAt,
+		RevokedAt:  row.RevokedAt,
+	}
+}
+
+func toEntity(row db.Endpoint) *Endpoint {
+	// backfill 전 row는 평문 토큰에서 앞자리를 보여줌
+	prefix := pkg.SafeDereference(row.TokenPrefix)
+	if prefix == "" && row.Token != nil {
+		prefix = token.Prefix(*row.Token)
+	}
+
+	return &Endpoint{
+		ID:                 row.ID,
+		Name:               row.Name,
+		TokenPrefix:        prefix,
+		CreatedAt:          row.CreatedAt,
+		UserID:             row.UserID,
+		OrgID:              row.OrgID,
+		NotificationEnable: row.NotificationEnabled,
+		Description:        row.Description,
+		Icon:               row.Icon,
+		Color:              row.Color,
+		DefaultTTL:         int32Ptr(row.DefaultTtl),
+		DefaultUrgency:     row.DefaultUrgency,
+		DefaultClickURL:    row.DefaultClickUrl,
+		Policy: Policy{
+			AllowedCIDRs:  row.AllowedCidrs,
+			AskEnabled:    row.AskEnabled,
+			MaxAskTimeout: int32Ptr(row.MaxAskTimeout),
+			MaxBodySize:   int32Ptr(row.MaxBodySize),
+			ExpiresAt:     row.ExpiresAt,
+		},
+		SigningRequired: row.SigningRequired,
+		SigningSecret:   row.SigningSecret,
+	}
+}
+
+// isNameConflict 개인 endpoint는 유저 안에서, 조직 endpoint는 조직 안에서 이름이 겹치면 안 됨
+func isNameConflict(err error) bool {
+	return db.IsUniqueViolationOn(err, nameUniqConstraint) || db.IsUniqueViolationOn(err, orgNameUniqConstraint)
+}
+
+func int32Ptr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+func toInt32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v)
+	return &i
+}
+
+func (r *endpointRepository) Stats(ctx context.Context, id uuid.UUID, since time.Time) (*Stats, error) {
+	total, err := r.queries.GetEndpointStats(ctx, db.GetEndpointStatsParams{
+		EndpointID: &id,
+		Since:      since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.queries.ListEndpointDailyStats(ctx, db.ListEndpointDailyStatsParams{
+		EndpointID: &id,
+		Since:      since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	daily := make([]DailyStats, 0, len(rows))
+	for _, row := range rows {
+		daily = append(daily, DailyStats{
+			Day: row.Day,
+			StatsCount: StatsCount{
+				Sent:           row.Sent,
+				Muted:          row.Muted,
+				Failed:         row.Failed,
+				Asks:           row.Asks,
+				Answered:       row.Answered,
+				TimedOut:       row.TimedOut,
+				MedianReaction: toDuration(row.MedianReactionSeconds),
+			},
+		})
+	}
+
+	return &Stats{
+		Since: since,
+		Total: StatsCount{
+			Sent:           total.Sent,
+			Muted:          total.Muted,
+			Failed:         total.Failed,
+			Asks:           total.Asks,
+			Answered:       total.Answered,
+			TimedOut:       total.TimedOut,
+			MedianReaction: toDuration(total.MedianReactionSeconds),
+		},
+		Daily:      daily,
+		LastUsedAt: total.LastUsedAt,
+	}, nil
+}
+
+func toDuration(seconds *float64) *time.Duration {
+	if seconds == nil {
+		return nil
 	}
-	return nil
+	d := time.Duration(*seconds * float64(time.Second))
+	return &d
 }
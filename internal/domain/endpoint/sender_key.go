@@ -0,0 +1,81 @@
+package endpoint
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+// sender key가 할 수 있는 요청 종류
+const (
+	ScopePush = "push"
+	ScopeAsk  = "ask"
+	// ScopeRead endpoint로 온 메시지와 응답을 구독 (/push/{token}/stream, /poll)
+	ScopeRead = "read"
+)
+
+var knownScopes = []string{ScopePush, ScopeAsk, ScopeRead}
+
+// defaultScopes scope 없이 발급한 키. 보내는 쪽 키가 읽기까지 갖지 않도록 read는 따로 지정해야 한다
+var defaultScopes = []string{ScopeAsk, ScopePush}
+
+const (
+	// endpoint 하나에 동시에 살아있을 수 있는 sender key 수
+	maxSenderKeys = 20
+)
+
+// SenderKey endpoint의 보조 키. CI, cron 등 보내는 쪽마다 따로 발급하고 따로 폐기한다.
+// 키 원문은 발급 응답에서만 확인할 수 있다.
+type SenderKey struct {
+	ID         uuid.UUID
+	EndpointID uuid.UUID
+	Label      string
+	KeyPrefix  string
+	Scopes     []string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+func (k *SenderKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// AddSenderKeyResult 발급 결과. 키는 이 응답에서만 확인할 수 있다.
+type AddSenderKeyResult struct {
+	SenderKey
+	Key string
+}
+
+// normalizeScopes 중복 제거 후 정렬. 비어 있으면 defaultScopes
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return slices.Clone(defaultScopes), nil
+	}
+
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !slices.Contains(knownScopes, scope) {
+			return nil, common.ErrInvalidParam
+		}
+		if !slices.Contains(result, scope) {
+			result = append(result, scope)
+		}
+	}
+	slices.Sort(result)
+	return result, nil
+}
+
+func countActiveSenderKeys(keys []SenderKey) int {
+	active := 0
+	for _, k := range keys {
+		if k.RevokedAt == nil {
+			active++
+		}
+	}
+	return active
+}
@@ -0,0 +1,102 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizeScopes(t *testing.T) {
+	cases := []struct {
+		name   string
+		scopes []string
+		want   []string
+		err    error
+	}{
+		{"empty means send scopes", nil, []string{ScopeAsk, ScopePush}, nil},
+		{"read", []string{"read", "push"}, []string{ScopePush, ScopeRead}, nil},
+		{"dedupe and sort", []string{"push", " PUSH ", "ask"}, []string{ScopeAsk, ScopePush}, nil},
+		{"single", []string{"push"}, []string{ScopePush}, nil},
+		{"unknown", []string{"push", "admin"}, nil, common.ErrInvalidParam},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := normalizeScopes(c.scopes)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("expected err: %v, got: %v", c.err, err)
+			}
+			if !slices.Equal(got, c.want) {
+				t.Errorf("expected: %v, got: %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestEndpoint_Allows(t *testing.T) {
+	owner := &Endpoint{}
+	if !owner.Allows(ScopeAsk) {
+		t.Error("endpoint token should allow every scope")
+	}
+
+	pushOnly := &Endpoint{SenderKey: &SenderKey{Scopes: []string{ScopePush}}}
+	if !pushOnly.Allows(ScopePush) {
+		t.Error("expected push to be allowed")
+	}
+	if pushOnly.Allows(ScopeAsk) {
+		t.Error("expected ask to be denied")
+	}
+}
+
+func TestEndpoint_CheckScopeRead(t *testing.T) {
+	// endpoint 토큰과 재발급 유예 중인 이전 토큰은 둘 다 SenderKey 없이 조회된다
+	primary := &Endpoint{}
+	if err := primary.CheckScope(ScopeRead); !errors.Is(err, common.ErrSenderKeyScope) {
+		t.Errorf("primary token read expected: %v, got: %v", common.ErrSenderKeyScope, err)
+	}
+	if err := primary.CheckScope(ScopePush); err != nil {
+		t.Errorf("primary token push expected nil, got: %v", err)
+	}
+
+	sendKey := &Endpoint{SenderKey: &SenderKey{Scopes: []string{ScopeAsk, ScopePush}}}
+	if err := sendKey.CheckScope(ScopeRead); !errors.Is(err, common.ErrSenderKeyScope) {
+		t.Errorf("send key read expected: %v, got: %v", common.ErrSenderKeyScope, err)
+	}
+
+	readKey := &Endpoint{SenderKey: &SenderKey{Scopes: []string{ScopeRead}}}
+	if err := readKey.CheckScope(ScopeRead); err != nil {
+		t.Errorf("read key expected nil, got: %v", err)
+	}
+}
+
+// senderKeyRepo ListSenderKeys만 쓰는 가짜 저장소
+type senderKeyRepo struct {
+	EndpointRepository
+	keys []SenderKey
+}
+
+func (r *senderKeyRepo) ListSenderKeys(context.Context, uuid.UUID) ([]SenderKey, error) {
+	return r.keys, nil
+}
+
+func TestSenderKeyRejected(t *testing.T) {
+	revokedAt := time.Now()
+	keys := make([]SenderKey, maxSenderKeys)
+	repo := &senderKeyRepo{keys: keys}
+	s := &EndpointService{repo: repo}
+
+	if err := s.senderKeyRejected(context.Background(), uuid.New()); !errors.Is(err, common.ErrSenderKeyLimit) {
+		t.Errorf("full expected: %v, got: %v", common.ErrSenderKeyLimit, err)
+	}
+
+	// 폐기된 키는 세지 않는다
+	keys[0].RevokedAt = &revokedAt
+	if err := s.senderKeyRejected(context.Background(), uuid.New()); !errors.Is(err, common.ErrEndpointNotFound) {
+		t.Errorf("not full expected: %v, got: %v", common.ErrEndpointNotFound, err)
+	}
+}
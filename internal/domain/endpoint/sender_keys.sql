@@ -0,0 +1,79 @@
+-- name: CreateSenderKey :one
+-- endpoint가 없거나 관리 권한이 없거나 살아있는 키가 이미 max_keys개면 row가 없음.
+-- 동시에 발급해도 개수를 넘지 않도록 같은 트랜잭션에서 LockEndpointForSenderKeys를 먼저 실행한다
+INSERT INTO endpoint_sender_keys (
+    endpoint_id,
+    label,
+    key_hash,
+    key_prefix,
+    scopes
+)
+SELECT
+    e.id,
+    sqlc.arg('label'),
+    sqlc.arg('key_hash'),
+    sqlc.arg('key_prefix'),
+    sqlc.arg('scopes')::text[]
+FROM endpoints e
+WHERE e.id = sqlc.arg('endpoint_id')
+  AND (
+      e.user_id = sqlc.arg('user_id')::uuid
+      OR e.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = sqlc.arg('user_id')
+            AND om.role IN ('owner', 'admin')
+      )
+  )
+  AND (
+      SELECT count(*) FROM endpoint_sender_keys k
+      WHERE k.endpoint_id = e.id
+        AND k.revoked_at IS NULL
+  ) < sqlc.arg('max_keys')::int
+RETURNING *;
+
+-- name: LockEndpointForSenderKeys :exec
+-- 같은 문장 안의 잠금은 이미 잡은 스냅샷을 바꾸지 않으므로 따로 잠근다.
+-- 잠금을 얻은 뒤 실행한 CreateSenderKey는 먼저 커밋된 키까지 센다
+SELECT id FROM endpoints
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListSenderKeys :many
+SELECT * FROM endpoint_sender_keys
+WHERE endpoint_id = $1
+ORDER BY id;
+
+-- name: RevokeSenderKey :execrows
+-- 이미 폐기된 키는 폐기 시각을 유지. 관리 권한이 없으면 row가 없음
+UPDATE endpoint_sender_keys k
+SET revoked_at = COALESCE(k.revoked_at, now())
+FROM endpoints e
+WHERE k.id = sqlc.arg('id')
+  AND k.endpoint_id = sqlc.arg('endpoint_id')
+  AND e.id = k.endpoint_id
+  AND (
+      e.user_id = sqlc.arg('user_id')::uuid
+      OR e.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = sqlc.arg('user_id')
+            AND om.role IN ('owner', 'admin')
+      )
+  );
+
+-- name: FindEndpointBySenderKeyHash :one
+SELECT
+    sqlc.embed(e),
+    k.id AS sender_key_id,
+    k.label AS sender_key_label,
+    k.scopes AS sender_key_scopes
+FROM endpoint_sender_keys k
+JOIN endpoints e ON e.id = k.endpoint_id
+WHERE k.key_hash = $1
+  AND k.revoked_at IS NULL;
+
+-- name: TouchSenderKey :exec
+-- 요청마다 쓰지 않도록 1분 단위로만 갱신
+UPDATE endpoint_sender_keys
+SET last_used_at = now()
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute');
@@ -2,28 +2,93 @@ package endpoint
 
 import (
 	"context"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 	"torchi/internal/domain/common"
+	"torchi/internal/domain/organization"
+	"torchi/internal/domain/sse"
 	"torchi/internal/pkg/token"
+
+	"github.com/google/uuid"
+)
+
+const (
+	endpointLength = 11
+	secretLength   = 43 // 256bit
+
+	// 토큰 재발급 후 이전 토큰을 허용할 수 있는 최대 기간
+	maxRotateGracePeriod = 7 * 24 * time.Hour
+
+	maxNameLength        = 30
+	maxDescriptionLength = 200
+	maxURLLength         = 2048
+
+	// web push TTL 상한(4주). 대부분의 push 서비스가 이 이상은 잘라낸다.
+	maxDefaultTTL = 4 * 7 * 24 * 60 * 60
 )
 
-const endpointLength = 11
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// web push Urgency 헤더 값
+var urgencies = map[string]bool{
+	"very-low": true,
+	"low":      true,
+	"normal":   true,
+	"high":     true,
+}
 
 type EndpointService struct {
-	repo EndpointRepository
+	repo    EndpointRepository
+	hasher  *token.Hasher
+	secrets *token.Sealer
+	orgs    *organization.OrganizationService
+	broker  *sse.Broker
 }
 
 func NewEndpointService(
 	repo EndpointRepository,
+	hasher *token.Hasher,
+	secrets *token.Sealer,
+	orgs *organization.OrganizationService,
+	broker *sse.Broker,
 ) *EndpointService {
 	return &EndpointService{
-		repo: repo,
+		repo:    repo,
+		hasher:  hasher,
+		secrets: secrets,
+		orgs:    orgs,
+		broker:  broker,
 	}
 }
 
-func (s *EndpointService) FindByToken(ctx context.Context, token string) (*Endpoint, error) {
+// FindByToken endpoint 토큰 또는 sender key로 조회. sender key면 Endpoint.SenderKey가 채워진다
+func (s *EndpointService) FindByToken(ctx context.Context, endpointToken string) (*Endpoint, error) {
+	hash := s.hasher.Hash(endpointToken)
+
+	endpoint, err := s.repo.FindByToken(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil {
+		endpoint, err = s.repo.FindBySenderKey(ctx, hash)
+		if err != nil || endpoint == nil {
+			return endpoint, err
+		}
+		if err := s.repo.TouchSenderKey(ctx, endpoint.SenderKey.ID); err != nil {
+			return nil, err
+		}
+	}
 
-	return s.repo.FindByToken(ctx, token)
+	if endpoint.SigningSecret != nil {
+		secret, err := s.secrets.Open(*endpoint.SigningSecret)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.SigningSecret = &secret
+	}
+	return endpoint, nil
 }
 func (s *EndpointService) List(ctx context.Context) ([]Endpoint, error) {
 	userClaim, err := token.UserFromContext(ctx)
@@ -33,25 +98,89 @@ func (s *EndpointService) List(ctx context.Context) ([]Endpoint, error) {
 	return s.repo.FindByUserID(ctx, userClaim.UserID)
 }
 
-func (s *EndpointService) UpdateMute(ctx context.Context, token string, notiEnable bool) error {
-	//TODO: 업데이트할 엔드포인트가 없으면 예외처리.
+// ListByOrg 조직의 모든 endpoint. 조직 멤버라면 누구나 조회 가능
+func (s *EndpointService) ListByOrg(ctx context.Context, orgID uuid.UUID) ([]Endpoint, error) {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.orgs.MemberRole(ctx, orgID, userClaim.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, common.ErrOrgNotFound
+	}
+
+	endpoints, err := s.repo.FindByOrgID(ctx, orgID, userClaim.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range endpoints {
+		endpoints[i].CanManage = organization.CanManageEndpoints(role)
+	}
+	return endpoints, nil
+}
+
+// UpdateMute notiEnable이 false면 음소거. until이 있으면 그 시각에 자동으로 해제된다
+func (s *EndpointService) UpdateMute(ctx context.Context, id uuid.UUID, notiEnable bool, until *time.Time) error {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var affected int64
+	if notiEnable {
+		affected, err = s.repo.UpdateUnmute(ctx, id, userClaim.UserID)
+	} else {
+		disabledTime := time.Now().UTC()
+		if until != nil {
+			if !until.After(disabledTime) || until.Sub(disabledTime) > maxMuteDuration {
+				return common.ErrInvalidParam
+			}
+			// TIMESTAMP 컬럼은 오프셋을 버리고 시각만 저장하므로 UTC로 맞춘다
+			utc := until.UTC()
+			until = &utc
+		}
+		affected, err = s.repo.UpdateMute(ctx, id, userClaim.UserID, &disabledTime, until)
+	}
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return common.ErrEndpointNotFound
+	}
+
+	// 음소거는 멤버별 설정이라 본인의 다른 탭, 기기에만 보낸다
 	if notiEnable {
-		return s.repo.UpdateUnmute(ctx, token)
+		s.broker.Publish(userClaim.UserID, unmutedEvent(id, "", false))
 	} else {
-		disabledTime := time.Now()
-		return s.repo.UpdateMute(ctx, token, &disabledTime)
+		s.broker.Publish(userClaim.UserID, sse.SSEEvent{
+			Event:      EventEndpointMuted,
+			Data:       map[string]any{"endpoint_id": id, "muted_until": until},
+			EndpointID: &id,
+		})
 	}
+	return nil
 }
 
-func (s *EndpointService) Add(ctx context.Context, serviceName string) error {
+// Add 토큰은 해시만 저장하므로 반환값에서만 확인할 수 있다.
+// orgID가 있으면 조직 endpoint로 만들며 조직 owner/admin만 가능
+func (s *EndpointService) Add(ctx context.Context, serviceName string, orgID *uuid.UUID) (*AddResult, error) {
 
 	userClaim, err := token.UserFromContext(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if len([]rune(serviceName)) > 30 {
-		return common.ErrInvalidParam
+	if len([]rune(serviceName)) > maxNameLength {
+		return nil, common.ErrInvalidParam
+	}
+	if orgID != nil {
+		if err := s.requireOrgManager(ctx, *orgID, userClaim.UserID); err != nil {
+			return nil, err
+		}
 	}
 
 	const maxRetry = 5
@@ -59,40 +188,629 @@ func (s *EndpointService) Add(ctx context.Context, serviceName string) error {
 	for i := 0; i < maxRetry; i++ {
 		endpoint, err := s.genEndpoint()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		err = s.repo.Add(ctx, insertEndpointParams{
-			userID:      userClaim.UserID,
+		id, err := s.repo.Add(ctx, insertEndpointParams{
+			createdBy:   userClaim.UserID,
+			orgID:       orgID,
 			serviceName: serviceName,
-			endpoint:    endpoint,
+			tokenHash:   s.hasher.Hash(endpoint),
+			tokenPrefix: token.Prefix(endpoint),
+		})
+
+		if err != nil {
+			if err == ErrDuplicateToken {
+				continue
+			}
+			return nil, err
+		}
+		return &AddResult{EndpointID: id, Token: endpoint}, nil
+
+	}
+
+	return nil, common.ErrInternalServer
+}
+
+func (s *EndpointService) Remove(ctx context.Context, id uuid.UUID) error {
+	userID, err := s.authorizeManage(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := s.repo.Remove(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return common.ErrEndpointNotFound
+	}
+	return nil
+}
+
+// RotateToken 토큰 재발급. endpoint ID는 유지되므로 알림 이력은 그대로 연결된다.
+// gracePeriod 동안은 이전 토큰으로도 push 가능.
+func (s *EndpointService) RotateToken(ctx context.Context, id uuid.UUID, gracePeriod time.Duration) (*RotateResult, error) {
+	if gracePeriod < 0 || gracePeriod > maxRotateGracePeriod {
+		return nil, common.ErrInvalidParam
+	}
+
+	userID, err := s.authorizeManage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var graceUntil *time.Time
+	if gracePeriod > 0 {
+		t := time.Now().Add(gracePeriod)
+		graceUntil = &t
+	}
+
+	const maxRetry = 5
+
+	for i := 0; i < maxRetry; i++ {
+		newToken, err := s.genEndpoint()
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := s.repo.RotateToken(ctx, rotateTokenParams{
+			id:             id,
+			userID:         userID,
+			newTokenHash:   s.hasher.Hash(newToken),
+			newTokenPrefix: token.Prefix(newToken),
+			graceUntil:     graceUntil,
 		})
+		if err != nil {
+			if err == ErrDuplicateToken {
+				continue
+			}
+			return nil, err
+		}
+		if result == nil {
+			return nil, common.ErrEndpointNotFound
+		}
+		result.Token = newToken
+		return result, nil
+	}
+
+	return nil, common.ErrInternalServer
+}
+
+// Update 이름, 설명, 아이콘 등 endpoint 설정 변경
+func (s *EndpointService) Update(ctx context.Context, id uuid.UUID, params UpdateParams) (*Endpoint, error) {
+	if err := validateUpdate(&params); err != nil {
+		return nil, err
+	}
+	userID, err := s.authorizeManage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := s.repo.Update(ctx, updateEndpointParams{
+		id:           id,
+		userID:       userID,
+		UpdateParams: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil {
+		return nil, common.ErrEndpointNotFound
+	}
+	return endpoint, nil
+}
+
+// UpdatePolicy 접근 정책 전체를 교체
+func (s *EndpointService) UpdatePolicy(ctx context.Context, id uuid.UUID, policy Policy) (*Endpoint, error) {
+	if err := policy.normalize(); err != nil {
+		return nil, err
+	}
+	userID, err := s.authorizeManage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := s.repo.UpdatePolicy(ctx, id, userID, policy)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil {
+		return nil, common.ErrEndpointNotFound
+	}
+	return endpoint, nil
+}
+
+// EnableSigning 서명 요청을 강제하고 새 secret 발급. secret은 이 응답에서만 확인할 수 있다.
+func (s *EndpointService) EnableSigning(ctx context.Context, id uuid.UUID) (string, error) {
+	userID, err := s.authorizeManage(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := token.GenerateEndpointToken(secretLength)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := s.secrets.Seal(secret)
+	if err != nil {
+		return "", err
+	}
+
+	affected, err := s.repo.EnableSigning(ctx, id, userID, sealed)
+	if err != nil {
+		return "", err
+	}
+	if affected == 0 {
+		return "", common.ErrEndpointNotFound
+	}
+	return secret, nil
+}
+
+func (s *EndpointService) DisableSigning(ctx context.Context, id uuid.UUID) error {
+	userID, err := s.authorizeManage(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := s.repo.DisableSigning(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return common.ErrEndpointNotFound
+	}
+	return nil
+}
+
+// AddSenderKey endpoint에 보조 키 발급. scopes가 비어 있으면 모든 권한
+func (s *EndpointService) AddSenderKey(ctx context.Context, endpointID uuid.UUID, label string, scopes []string) (*AddSenderKeyResult, error) {
+	label = strings.TrimSpace(label)
+	if label == "" || len([]rune(label)) > maxNameLength {
+		return nil, common.ErrInvalidParam
+	}
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := s.authorizeManage(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	const maxRetry = 5
 
+	for i := 0; i < maxRetry; i++ {
+		key, err := s.genEndpoint()
+		if err != nil {
+			return nil, err
+		}
+
+		senderKey, err := s.repo.AddSenderKey(ctx, insertSenderKeyParams{
+			endpointID: endpointID,
+			userID:     userID,
+			label:      label,
+			keyHash:    s.hasher.Hash(key),
+			keyPrefix:  token.Prefix(key),
+			scopes:     scopes,
+			maxKeys:    maxSenderKeys,
+		})
 		if err != nil {
 			if err == ErrDuplicateToken {
 				continue
 			}
-			return err
+			return nil, err
+		}
+		if senderKey == nil {
+			return nil, s.senderKeyRejected(ctx, endpointID)
 		}
+		return &AddSenderKeyResult{SenderKey: *senderKey, Key: key}, nil
+	}
+
+	return nil, common.ErrInternalServer
+}
+
+// senderKeyRejected 발급되지 않은 이유. 권한은 앞에서 확인했으므로 개수가 찼으면 한도, 아니면 그 사이 사라진 것으로 본다
+func (s *EndpointService) senderKeyRejected(ctx context.Context, endpointID uuid.UUID) error {
+	keys, err := s.repo.ListSenderKeys(ctx, endpointID)
+	if err != nil {
+		return err
+	}
+	if countActiveSenderKeys(keys) >= maxSenderKeys {
+		return common.ErrSenderKeyLimit
+	}
+	return common.ErrEndpointNotFound
+}
+
+// ListSenderKeys 폐기된 키도 포함
+func (s *EndpointService) ListSenderKeys(ctx context.Context, endpointID uuid.UUID) ([]SenderKey, error) {
+	if _, err := s.authorizeManage(ctx, endpointID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListSenderKeys(ctx, endpointID)
+}
+
+// RevokeSenderKey 다른 키와 endpoint 토큰에는 영향 없음
+func (s *EndpointService) RevokeSenderKey(ctx context.Context, endpointID, keyID uuid.UUID) error {
+	userID, err := s.authorizeManage(ctx, endpointID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := s.repo.RevokeSenderKey(ctx, keyID, endpointID, userID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return common.ErrSenderKeyNotFound
+	}
+	return nil
+}
+
+// Stats 최근 days일(오늘 포함)의 발송 통계. 멤버라면 누구나 조회 가능
+func (s *EndpointService) Stats(ctx context.Context, id uuid.UUID, days int) (*Stats, error) {
+	userID, access, err := s.access(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanView(userID) {
+		return nil, common.ErrEndpointNotFound
+	}
+	if days < 1 || days > MaxStatsDays {
+		return nil, common.ErrInvalidParam
+	}
+
+	now := time.Now()
+	stats, err := s.repo.Stats(ctx, id, statsSince(now, days))
+	if err != nil {
+		return nil, err
+	}
+	stats.Daily = fillDaily(stats.Since, now, stats.Daily)
+	return stats, nil
+}
+
+// ListMembers endpoint 멤버나 소유 조직의 멤버라면 누구나 조회 가능. 다른 멤버의 이메일은 관리 권한이 있어야 보인다
+func (s *EndpointService) ListMembers(ctx context.Context, endpointID uuid.UUID) ([]Member, error) {
+	userID, access, err := s.access(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanView(userID) {
+		return nil, common.ErrEndpointNotFound
+	}
+
+	members, err := s.repo.ListMembers(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanManage(userID) {
+		hideEmails(members, userID)
+	}
+	return members, nil
+}
+
+// RemoveMember 관리 권한이 있는 유저가 멤버를 내보낸다. owner는 내보낼 수 없다.
+func (s *EndpointService) RemoveMember(ctx context.Context, endpointID, memberID uuid.UUID) error {
+	userID, err := s.authorizeManage(ctx, endpointID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := s.repo.RemoveMember(ctx, endpointID, memberID, userID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return common.ErrMemberNotFound
+	}
+	return nil
+}
+
+// Leave 참여한 endpoint에서 나간다. 이미 받은 알림은 그대로 남는다.
+func (s *EndpointService) Leave(ctx context.Context, endpointID uuid.UUID) error {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	affected, err := s.repo.RemoveMember(ctx, endpointID, userClaim.UserID, userClaim.UserID)
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
 		return nil
+	}
+
+	// 멤버인데 지워지지 않았다면 owner
+	isMember, err := s.repo.IsMember(ctx, endpointID, userClaim.UserID)
+	if err != nil {
+		return err
+	}
+	if isMember {
+		return common.ErrOwnerCannotLeave
+	}
+	return common.ErrEndpointNotFound
+}
+
+// Subscribe 조직 endpoint를 초대 없이 구독. 조직 멤버라면 누구나 가능하고, 이미 멤버면 그대로 성공
+func (s *EndpointService) Subscribe(ctx context.Context, endpointID uuid.UUID) error {
+	userID, access, err := s.access(ctx, endpointID)
+	if err != nil {
+		return err
+	}
+	if access.IsMember {
+		return nil
+	}
+	if !access.CanSubscribe() {
+		return common.ErrEndpointNotFound
+	}
+	return s.repo.AddMember(ctx, endpointID, userID)
+}
+
+// TransferToOrg 개인 endpoint를 조직으로 옮긴다. 토큰, sender key, 멤버는 그대로 유지되고
+// 기존 소유자는 member로 남는다. 소유자이면서 대상 조직의 owner/admin이어야 한다.
+func (s *EndpointService) TransferToOrg(ctx context.Context, id, orgID uuid.UUID) error {
+	userID, access, err := s.access(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 이미 조직 endpoint면 옮길 수 없음
+	if access.OrgID != nil || !access.CanManage(userID) {
+		if access.CanView(userID) {
+			return common.ErrEndpointPermission
+		}
+		return common.ErrEndpointNotFound
+	}
+
+	if err := s.requireOrgManager(ctx, orgID, userID); err != nil {
+		return err
+	}
+
+	moved, err := s.repo.TransferToOrg(ctx, id, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !moved {
+		return common.ErrEndpointNotFound
+	}
+	return nil
+}
+
+// CreateInvite 초대 링크 생성. ttl이 0이면 기본 7일, maxUses가 nil이면 횟수 제한 없음
+func (s *EndpointService) CreateInvite(ctx context.Context, endpointID uuid.UUID, ttl time.Duration, maxUses *int) (*CreateInviteResult, error) {
+	if ttl == 0 {
+		ttl = defaultInviteTTL
+	}
+	if ttl < 0 || ttl > maxInviteTTL {
+		return nil, common.ErrInvalidParam
+	}
+	if maxUses != nil && (*maxUses < 1 || *maxUses > maxInviteUses) {
+		return nil, common.ErrInvalidParam
+	}
+	userID, err := s.authorizeManage(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	const maxRetry = 5
+
+	for i := 0; i < maxRetry; i++ {
+		code, err := token.GenerateEndpointToken(inviteCodeLength)
+		if err != nil {
+			return nil, err
+		}
+
+		invite, err := s.repo.AddInvite(ctx, insertInviteParams{
+			endpointID: endpointID,
+			createdBy:  userID,
+			codeHash:   s.hasher.Hash(code),
+			codePrefix: token.Prefix(code),
+			expiresAt:  time.Now().Add(ttl),
+			maxUses:    maxUses,
+		})
+		if err != nil {
+			if err == ErrDuplicateToken {
+				continue
+			}
+			return nil, err
+		}
+		if invite == nil {
+			return nil, common.ErrEndpointNotFound
+		}
+		return &CreateInviteResult{Invite: *invite, Code: code}, nil
+	}
+
+	return nil, common.ErrInternalServer
+}
+
+// ListInvites 관리 권한이 있어야 조회 가능. 만료, 폐기된 초대도 포함
+func (s *EndpointService) ListInvites(ctx context.Context, endpointID uuid.UUID) ([]Invite, error) {
+	if _, err := s.authorizeManage(ctx, endpointID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListInvites(ctx, endpointID)
+}
 
+// RevokeInvite 이미 참여한 멤버에게는 영향 없음
+func (s *EndpointService) RevokeInvite(ctx context.Context, endpointID, inviteID uuid.UUID) error {
+	userID, err := s.authorizeManage(ctx, endpointID)
+	if err != nil {
+		return err
 	}
 
-	return common.ErrInternalServer
+	affected, err := s.repo.RevokeInvite(ctx, inviteID, endpointID, userID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return common.ErrInviteNotFound
+	}
+	return nil
 }
 
-func (s *EndpointService) Remove(ctx context.Context, endpointToken string) error {
+// Join 초대 코드로 endpoint에 참여. 이미 멤버면 사용 횟수를 쓰지 않고 그대로 성공
+func (s *EndpointService) Join(ctx context.Context, code string) (*JoinResult, error) {
 	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if code == "" {
+		return nil, common.ErrInviteNotFound
+	}
+	invite, err := s.repo.FindInviteByCode(ctx, s.hasher.Hash(code))
+	if err != nil {
+		return nil, err
+	}
+	if invite == nil {
+		return nil, common.ErrInviteNotFound
+	}
+
+	result := &JoinResult{
+		EndpointID:   invite.EndpointID,
+		EndpointName: invite.EndpointName,
+	}
+
+	isMember, err := s.repo.IsMember(ctx, invite.EndpointID, userClaim.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if isMember {
+		return result, nil
+	}
+
+	if err := invite.CheckUsable(time.Now()); err != nil {
+		return nil, err
+	}
+
+	joined, err := s.repo.Join(ctx, invite.ID, userClaim.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if joined {
+		return result, nil
+	}
+
+	// 그 사이 다른 요청으로 이미 참여했거나, 확인 이후 다른 요청이 마지막 사용 횟수를 가져간 경우
+	isMember, err = s.repo.IsMember(ctx, invite.EndpointID, userClaim.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, common.ErrInviteExpired
+	}
+	return result, nil
+}
+
+// Authorize 다른 도메인에서 endpoint 단위 설정을 다룰 때 권한 확인. manage가 false면 조회 권한만 본다
+func (s *EndpointService) Authorize(ctx context.Context, endpointID uuid.UUID, manage bool) error {
+	if manage {
+		_, err := s.authorizeManage(ctx, endpointID)
+		return err
+	}
+
+	userID, access, err := s.access(ctx, endpointID)
 	if err != nil {
 		return err
 	}
+	if !access.CanView(userID) {
+		return common.ErrEndpointNotFound
+	}
+	return nil
+}
+
+// access 요청한 유저의 권한 조회. endpoint가 없으면 ErrEndpointNotFound
+func (s *EndpointService) access(ctx context.Context, endpointID uuid.UUID) (uuid.UUID, *Access, error) {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	access, err := s.repo.FindAccess(ctx, endpointID, userClaim.UserID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	if access == nil {
+		return uuid.Nil, nil, common.ErrEndpointNotFound
+	}
+	return userClaim.UserID, access, nil
+}
+
+// authorizeManage 관리 권한 확인. 볼 수 없는 endpoint는 존재 여부를 드러내지 않도록 ErrEndpointNotFound
+func (s *EndpointService) authorizeManage(ctx context.Context, endpointID uuid.UUID) (uuid.UUID, error) {
+	userID, access, err := s.access(ctx, endpointID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if access.CanManage(userID) {
+		return userID, nil
+	}
+	if access.CanView(userID) {
+		return uuid.Nil, common.ErrEndpointPermission
+	}
+	return uuid.Nil, common.ErrEndpointNotFound
+}
 
-	err = s.repo.RemoveByToken(ctx, endpointToken, userClaim.UserID)
+// requireOrgManager 조직 endpoint를 만들거나 옮겨올 수 있는지 확인
+func (s *EndpointService) requireOrgManager(ctx context.Context, orgID, userID uuid.UUID) error {
+	role, err := s.orgs.MemberRole(ctx, orgID, userID)
 	if err != nil {
 		return err
 	}
+	if role == "" {
+		return common.ErrOrgNotFound
+	}
+	if !organization.CanManageEndpoints(role) {
+		return common.ErrOrgPermission
+	}
 	return nil
 }
+
+func validateUpdate(p *UpdateParams) error {
+	if p.Name != nil {
+		name := strings.TrimSpace(*p.Name)
+		if name == "" || len([]rune(name)) > maxNameLength {
+			return common.ErrInvalidParam
+		}
+		p.Name = &name
+	}
+	if p.Description != nil && len([]rune(*p.Description)) > maxDescriptionLength {
+		return common.ErrInvalidParam
+	}
+	if p.Icon != nil && *p.Icon != "" && !isValidLink(*p.Icon) {
+		return common.ErrInvalidParam
+	}
+	if p.Color != nil && *p.Color != "" && !colorPattern.MatchString(*p.Color) {
+		return common.ErrInvalidParam
+	}
+	if p.DefaultTTL != nil && (*p.DefaultTTL < 0 || *p.DefaultTTL > maxDefaultTTL) {
+		return common.ErrInvalidParam
+	}
+	if p.DefaultUrgency != nil && *p.DefaultUrgency != "" && !urgencies[*p.DefaultUrgency] {
+		return common.ErrInvalidParam
+	}
+	if p.DefaultClickURL != nil && *p.DefaultClickURL != "" && !isValidLink(*p.DefaultClickURL) {
+		return common.ErrInvalidParam
+	}
+	return nil
+}
+
+// isValidLink 같은 origin 경로("/...") 또는 http(s) 절대 URL만 허용
+func isValidLink(link string) bool {
+	if len(link) > maxURLLength {
+		return false
+	}
+	if strings.HasPrefix(link, "/") {
+		return !strings.HasPrefix(link, "//")
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
 func (s *EndpointService) genEndpoint() (string, error) {
 	endpoint, err := token.GenerateEndpointToken(endpointLength)
 	if err != nil {
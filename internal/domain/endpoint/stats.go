@@ -0,0 +1,59 @@
+package endpoint
+
+import "time"
+
+const (
+	DefaultStatsDays = 30
+	MaxStatsDays     = 90
+)
+
+// StatsCount 발송(message) 단위 집계. Muted, Failed는 멤버별 알림 수
+type StatsCount struct {
+	Sent     int64
+	Muted    int64
+	Failed   int64
+	Asks     int64
+	Answered int64
+	TimedOut int64
+	// ask에 처음 응답하기까지 걸린 시간의 중앙값. 응답이 없으면 nil
+	MedianReaction *time.Duration
+}
+
+// DailyStats 하루(UTC) 단위 집계
+type DailyStats struct {
+	Day time.Time
+	StatsCount
+}
+
+type Stats struct {
+	Since      time.Time
+	Total      StatsCount
+	Daily      []DailyStats // Since부터 오늘까지 하루도 빠짐없이
+	LastUsedAt *time.Time   // 기간과 관계없이 마지막으로 알림을 보낸 시각
+}
+
+// statsSince 오늘(UTC)을 포함해 days일 전 자정
+func statsSince(now time.Time, days int) time.Time {
+	today := now.UTC().Truncate(24 * time.Hour)
+	return today.AddDate(0, 0, -(days - 1))
+}
+
+// fillDaily 발송이 없는 날을 0으로 채워 since부터 now까지 연속된 일별 집계를 만든다
+func fillDaily(since, now time.Time, rows []DailyStats) []DailyStats {
+	byDay := make(map[time.Time]DailyStats, len(rows))
+	for _, r := range rows {
+		byDay[r.Day.UTC().Truncate(24*time.Hour)] = r
+	}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	var result []DailyStats
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		d, ok := byDay[day]
+		if !ok {
+			d = DailyStats{}
+		}
+		d.Day = day
+		result = append(result, d)
+	}
+	return result
+}
@@ -0,0 +1,57 @@
+-- name: GetEndpointStats :one
+-- since 이후 합계. 알림은 멤버마다 한 row이므로 발송(message_id) 단위로 묶어서 센다.
+-- muted, failed는 멤버별 알림 수. status는 ask 응답으로 바뀌므로 delivery를 센다 (delivery 이전 알림은 status)
+WITH messages AS (
+    SELECT
+        message_id,
+        min(created_at) AS created_at,
+        bool_or(actions IS NOT NULL) AS is_ask,
+        bool_or(status = 'timeout_reply') AS timed_out,
+        min(reaction_at) AS reaction_at,
+        count(*) FILTER (WHERE coalesce(delivery, status) = 'mute') AS muted,
+        count(*) FILTER (WHERE coalesce(delivery, status) = 'failed') AS failed
+    FROM notifications
+    WHERE endpoint_id = sqlc.arg('endpoint_id')
+      AND created_at >= sqlc.arg('since')::timestamp AT TIME ZONE 'UTC'
+    GROUP BY message_id
+)
+SELECT
+    count(*) AS sent,
+    coalesce(sum(muted), 0)::bigint AS muted,
+    coalesce(sum(failed), 0)::bigint AS failed,
+    count(*) FILTER (WHERE is_ask) AS asks,
+    count(*) FILTER (WHERE is_ask AND reaction_at IS NOT NULL) AS answered,
+    count(*) FILTER (WHERE is_ask AND timed_out) AS timed_out,
+    (percentile_cont(0.5) WITHIN GROUP (ORDER BY extract(epoch FROM reaction_at - created_at)))::float8 AS median_reaction_seconds,
+    (SELECT max(n.created_at) FROM notifications n WHERE n.endpoint_id = sqlc.arg('endpoint_id'))::timestamp AS last_used_at
+FROM messages;
+
+-- name: ListEndpointDailyStats :many
+-- since 이후 일별(UTC) 합계. 발송이 없는 날은 row가 없다.
+-- created_at은 세션 시간대 기준이므로 timestamptz로 바꾼 뒤 UTC 자정으로 자른다
+WITH messages AS (
+    SELECT
+        message_id,
+        min(created_at) AS created_at,
+        bool_or(actions IS NOT NULL) AS is_ask,
+        bool_or(status = 'timeout_reply') AS timed_out,
+        min(reaction_at) AS reaction_at,
+        count(*) FILTER (WHERE coalesce(delivery, status) = 'mute') AS muted,
+        count(*) FILTER (WHERE coalesce(delivery, status) = 'failed') AS failed
+    FROM notifications
+    WHERE endpoint_id = sqlc.arg('endpoint_id')
+      AND created_at >= sqlc.arg('since')::timestamp AT TIME ZONE 'UTC'
+    GROUP BY message_id
+)
+SELECT
+    (date_trunc('day', created_at::timestamptz, 'UTC') AT TIME ZONE 'UTC')::timestamp AS day,
+    count(*) AS sent,
+    coalesce(sum(muted), 0)::bigint AS muted,
+    coalesce(sum(failed), 0)::bigint AS failed,
+    count(*) FILTER (WHERE is_ask) AS asks,
+    count(*) FILTER (WHERE is_ask AND reaction_at IS NOT NULL) AS answered,
+    count(*) FILTER (WHERE is_ask AND timed_out) AS timed_out,
+    (percentile_cont(0.5) WITHIN GROUP (ORDER BY extract(epoch FROM reaction_at - created_at)))::float8 AS median_reaction_seconds
+FROM messages
+GROUP BY day
+ORDER BY day;
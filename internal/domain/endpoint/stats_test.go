@@ -0,0 +1,36 @@
+package endpoint
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFillDaily(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 4, 5, 0, time.UTC)
+	since := statsSince(now, 3)
+
+	if want := time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC); !since.Equal(want) {
+		t.Fatalf("since expected: %v, got: %v", want, since)
+	}
+
+	rows := []DailyStats{
+		{Day: time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC), StatsCount: StatsCount{Sent: 4, Muted: 1}},
+	}
+	daily := fillDaily(since, now, rows)
+
+	if len(daily) != 3 {
+		t.Fatalf("len expected: 3, got: %d", len(daily))
+	}
+	wantSent := []int64{0, 4, 0}
+	for i, d := range daily {
+		if want := since.AddDate(0, 0, i); !d.Day.Equal(want) {
+			t.Errorf("day[%d] expected: %v, got: %v", i, want, d.Day)
+		}
+		if d.Sent != wantSent[i] {
+			t.Errorf("sent[%d] expected: %d, got: %d", i, wantSent[i], d.Sent)
+		}
+	}
+	if daily[1].Muted != 1 {
+		t.Errorf("muted expected: 1, got: %d", daily[1].Muted)
+	}
+}
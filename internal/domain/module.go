@@ -4,7 +4,9 @@ import (
 	"torchi/internal/domain/auth"
 	"torchi/internal/domain/endpoint"
 	"torchi/internal/domain/notifications"
+	"torchi/internal/domain/organization"
 	"torchi/internal/domain/push"
+	"torchi/internal/domain/retention"
 	"torchi/internal/domain/sse"
 	"torchi/internal/domain/token"
 	"torchi/internal/domain/user"
@@ -19,6 +21,8 @@ var Module = fx.Options(
 	user.Module,
 	token.Module,
 	endpoint.Module,
+	organization.Module,
 	notifications.Module,
+	retention.Module,
 	sse.Module,
 )
@@ -0,0 +1,80 @@
+package notifications
+
+import (
+	"time"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+// BulkAction 여러 알림에 한 번에 적용할 동작
+type BulkAction string
+
+const (
+	BulkRead    BulkAction = "read"
+	BulkUnread  BulkAction = "unread"
+	BulkDelete  BulkAction = "delete"  // 휴지통으로 이동
+	BulkRestore BulkAction = "restore" // 휴지통에서 되돌리기. ID 목록으로만 가능
+)
+
+// MaxBulkIDs 한 요청에 지정할 수 있는 ID 수
+const MaxBulkIDs = 500
+
+// BulkTarget ID 목록과 필터 중 하나만 지정한다
+type BulkTarget struct {
+	IDs    []uuid.UUID
+	Filter *ListFilter
+}
+
+// BulkResult Affected는 조건에 맞은 알림 수. 이미 같은 상태였던 알림도 포함된다
+type BulkResult struct {
+	Affected   int64
+	MessageIDs []uuid.UUID // 지운 알림의 발송 ID (중복 없음). 대기 중인 ask 정리용
+}
+
+func (a BulkAction) valid() bool {
+	switch a {
+	case BulkRead, BulkUnread, BulkDelete, BulkRestore:
+		return true
+	}
+	return false
+}
+
+func (t *BulkTarget) validate(action BulkAction) error {
+	if !action.valid() {
+		return common.ErrInvalidParam
+	}
+
+	hasIDs := len(t.IDs) > 0
+	if hasIDs == (t.Filter != nil) || len(t.IDs) > MaxBulkIDs {
+		return common.ErrInvalidParam
+	}
+	if action == BulkRestore && !hasIDs {
+		return common.ErrInvalidParam
+	}
+	if t.Filter != nil {
+		return t.Filter.validate()
+	}
+	return nil
+}
+
+// trashCutoff olderThanDays일 전보다 먼저 지운 항목이 영구 삭제 대상. 0이면 휴지통 전체
+func trashCutoff(now time.Time, olderThanDays int) (time.Time, error) {
+	if olderThanDays < 0 {
+		return time.Time{}, common.ErrInvalidParam
+	}
+	return now.AddDate(0, 0, -olderThanDays), nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	result := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+	return result
+}
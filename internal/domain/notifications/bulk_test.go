@@ -0,0 +1,64 @@
+package notifications
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+func TestBulkTarget_Validate(t *testing.T) {
+	ids := []uuid.UUID{uuid.New()}
+	from := time.Now()
+	to := from.Add(-time.Hour)
+
+	cases := []struct {
+		name   string
+		action BulkAction
+		target BulkTarget
+		want   error
+	}{
+		{"ids", BulkRead, BulkTarget{IDs: ids}, nil},
+		{"filter", BulkDelete, BulkTarget{Filter: &ListFilter{UnreadOnly: true}}, nil},
+		{"restore ids", BulkRestore, BulkTarget{IDs: ids}, nil},
+		{"unknown action", "archive", BulkTarget{IDs: ids}, common.ErrInvalidParam},
+		{"no target", BulkRead, BulkTarget{}, common.ErrInvalidParam},
+		{"both", BulkUnread, BulkTarget{IDs: ids, Filter: &ListFilter{}}, common.ErrInvalidParam},
+		{"restore filter", BulkRestore, BulkTarget{Filter: &ListFilter{}}, common.ErrInvalidParam},
+		{"too many ids", BulkRead, BulkTarget{IDs: make([]uuid.UUID, MaxBulkIDs+1)}, common.ErrInvalidParam},
+		{"invalid range", BulkRead, BulkTarget{Filter: &ListFilter{CreatedFrom: &from, CreatedTo: &to}}, common.ErrInvalidParam},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.target.validate(c.action); !errors.Is(err, c.want) {
+				t.Errorf("expected: %v, got: %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestTrashCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	got, err := trashCutoff(now, 30)
+	if err != nil || !got.Equal(time.Date(2026, 2, 8, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected: 2026-02-08 12:00, got: %v (%v)", got, err)
+	}
+	if got, _ := trashCutoff(now, 0); !got.Equal(now) {
+		t.Errorf("expected: %v, got: %v", now, got)
+	}
+	if _, err := trashCutoff(now, -1); !errors.Is(err, common.ErrInvalidParam) {
+		t.Errorf("expected: %v, got: %v", common.ErrInvalidParam, err)
+	}
+}
+
+func TestUniqueIDs(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	got := uniqueIDs([]uuid.UUID{a, b, a, a})
+	if len(got) != 2 || got[0] != a || got[1] != b {
+		t.Errorf("expected: [%v %v], got: %v", a, b, got)
+	}
+}
@@ -0,0 +1,134 @@
+package notifications
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+// TombstoneTTL 영구 삭제 기록을 남겨두는 기간. 이보다 오래된 sync token으로는 삭제를 다 알려줄 수 없다
+const TombstoneTTL = 30 * 24 * time.Hour
+
+type ChangeType string
+
+const (
+	ChangeCreated ChangeType = "created"
+	ChangeUpdated ChangeType = "updated"
+	ChangeDeleted ChangeType = "deleted" // 휴지통으로 옮겼거나 영구 삭제됨
+)
+
+// ChangePos 변경 피드 순서. 바꾼 트랜잭션(xid8)이 먼저고 같은 트랜잭션 안에서는 change_seq.
+// change_seq는 커밋 전에 받으므로 그것만으로는 늦게 커밋된 변경이 이미 넘긴 위치 뒤에 끼어들 수 있다
+type ChangePos struct {
+	Xid uint64
+	Seq int64
+}
+
+func (p ChangePos) before(o ChangePos) bool {
+	return p.Xid < o.Xid || (p.Xid == o.Xid && p.Seq < o.Seq)
+}
+
+// Change 변경 피드 한 건. deleted면 Noti는 nil
+type Change struct {
+	Type ChangeType
+	ID   uuid.UUID
+	Pos  ChangePos
+	Noti *Noti
+}
+
+// SyncToken 클라이언트가 어디까지 받았는지. IssuedAt은 그 시점까지의 변경을 모두 받았다는 시각
+type SyncToken struct {
+	Pos      ChangePos
+	IssuedAt time.Time
+}
+
+// Encode 클라이언트에 넘길 불투명한 토큰 문자열
+func (t SyncToken) Encode() string {
+	raw := strconv.FormatUint(t.Pos.Xid, 10) + "_" + strconv.FormatInt(t.Pos.Seq, 10) + "_" + strconv.FormatInt(t.IssuedAt.Unix(), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeSyncToken(s string) (*SyncToken, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+	parts := strings.Split(string(raw), "_")
+	if len(parts) != 3 {
+		return nil, common.ErrInvalidParam
+	}
+	xid, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+	seq, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || seq < 0 {
+		return nil, common.ErrInvalidParam
+	}
+	issued, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+	return &SyncToken{Pos: ChangePos{Xid: xid, Seq: seq}, IssuedAt: time.Unix(issued, 0)}, nil
+}
+
+func (t SyncToken) expired(now time.Time) bool {
+	return now.Sub(t.IssuedAt) > TombstoneTTL
+}
+
+// ChangeSet 변경 피드 한 페이지. HasMore면 Next로 바로 다시 요청한다
+type ChangeSet struct {
+	Changes []Change
+	Next    SyncToken
+	HasMore bool
+}
+
+// changeType since 이후에 만들어졌으면 created, 그 전부터 있던 알림이면 updated
+func changeType(since, created ChangePos, isDeleted bool) ChangeType {
+	switch {
+	case isDeleted:
+		return ChangeDeleted
+	case since.before(created):
+		return ChangeCreated
+	}
+	return ChangeUpdated
+}
+
+// mergeChanges Pos 오름차순인 두 목록을 합쳐 앞에서 limit개
+func mergeChanges(a, b []Change, limit int) []Change {
+	result := make([]Change, 0, min(len(a)+len(b), limit))
+	for len(result) < limit && (len(a) > 0 || len(b) > 0) {
+		if len(b) == 0 || (len(a) > 0 && a[0].Pos.before(b[0].Pos)) {
+			result = append(result, a[0])
+			a = a[1:]
+		} else {
+			result = append(result, b[0])
+			b = b[1:]
+		}
+	}
+	return result
+}
+
+// nextChangeSet 다 따라잡았을 때만 IssuedAt을 now로 옮긴다.
+// 페이지를 넘기는 중이면 아직 받지 못한 삭제가 있을 수 있으므로 시작 시각을 유지한다
+func nextChangeSet(token SyncToken, changes []Change, more bool, limit int, now time.Time) ChangeSet {
+	next := token
+	if len(changes) > 0 {
+		next.Pos = changes[len(changes)-1].Pos
+	}
+
+	hasMore := more || len(changes) == limit
+	if !hasMore {
+		next.IssuedAt = now
+	}
+
+	return ChangeSet{
+		Changes: changes,
+		Next:    next,
+		HasMore: hasMore,
+	}
+}
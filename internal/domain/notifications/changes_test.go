@@ -0,0 +1,135 @@
+package notifications
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"torchi/internal/domain/common"
+)
+
+func TestSyncToken_RoundTrip(t *testing.T) {
+	want := SyncToken{Pos: ChangePos{Xid: 1 << 33, Seq: 12345}, IssuedAt: time.Unix(1760000000, 0)}
+
+	got, err := DecodeSyncToken(want.Encode())
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Pos != want.Pos || !got.IssuedAt.Equal(want.IssuedAt) {
+		t.Errorf("expected: %+v, got: %+v", want, *got)
+	}
+}
+
+func TestDecodeSyncToken_Invalid(t *testing.T) {
+	// 12_1760000000 (xid 없는 예전 형식), 1_-1_1760000000
+	for _, s := range []string{"!!", "MTIz", "YWJjXzE", "MTJfMTc2MDAwMDAwMA", "MV8tMV8xNzYwMDAwMDAw"} {
+		if _, err := DecodeSyncToken(s); !errors.Is(err, common.ErrInvalidParam) {
+			t.Errorf("DecodeSyncToken(%q) expected: %v, got: %v", s, common.ErrInvalidParam, err)
+		}
+	}
+}
+
+func TestChangeType(t *testing.T) {
+	since := ChangePos{Xid: 100, Seq: 10}
+	cases := []struct {
+		created   ChangePos
+		isDeleted bool
+		want      ChangeType
+	}{
+		{ChangePos{Xid: 100, Seq: 11}, false, ChangeCreated},
+		{ChangePos{Xid: 100, Seq: 10}, false, ChangeUpdated},
+		{ChangePos{Xid: 100, Seq: 3}, false, ChangeUpdated},
+		{ChangePos{Xid: 100, Seq: 11}, true, ChangeDeleted},
+		// 늦게 커밋된 트랜잭션은 seq가 작아도 since 뒤다
+		{ChangePos{Xid: 101, Seq: 3}, false, ChangeCreated},
+		{ChangePos{Xid: 99, Seq: 20}, false, ChangeUpdated},
+	}
+
+	for _, c := range cases {
+		if got := changeType(since, c.created, c.isDeleted); got != c.want {
+			t.Errorf("changeType(%+v, %+v, %v) expected: %s, got: %s", since, c.created, c.isDeleted, c.want, got)
+		}
+	}
+}
+
+func seqs(changes []Change) []int64 {
+	result := make([]int64, len(changes))
+	for i, c := range changes {
+		result[i] = c.Pos.Seq
+	}
+	return result
+}
+
+func changesOf(s ...int64) []Change {
+	result := make([]Change, len(s))
+	for i, seq := range s {
+		result[i] = Change{Pos: ChangePos{Xid: 1, Seq: seq}}
+	}
+	return result
+}
+
+func TestMergeChanges(t *testing.T) {
+	cases := []struct {
+		name  string
+		a, b  []Change
+		limit int
+		want  []int64
+	}{
+		{"empty", nil, nil, 10, []int64{}},
+		{"only live", changesOf(1, 2), nil, 10, []int64{1, 2}},
+		{"only tombstones", nil, changesOf(3, 5), 10, []int64{3, 5}},
+		{"interleaved", changesOf(1, 4, 6), changesOf(2, 3, 7), 10, []int64{1, 2, 3, 4, 6, 7}},
+		{"limited", changesOf(1, 4, 6), changesOf(2, 3, 7), 4, []int64{1, 2, 3, 4}},
+		{"xid first", []Change{{Pos: ChangePos{Xid: 2, Seq: 1}}}, changesOf(5), 10, []int64{5, 1}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := seqs(mergeChanges(c.a, c.b, c.limit))
+			if len(got) != len(c.want) {
+				t.Fatalf("expected: %v, got: %v", c.want, got)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Fatalf("expected: %v, got: %v", c.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNextChangeSet(t *testing.T) {
+	start := time.Unix(1760000000, 0)
+	now := start.Add(time.Hour)
+	token := SyncToken{Pos: ChangePos{Xid: 1, Seq: 5}, IssuedAt: start}
+
+	t.Run("caught up", func(t *testing.T) {
+		set := nextChangeSet(token, changesOf(6, 8), false, 10, now)
+		if set.HasMore || set.Next.Pos.Seq != 8 || !set.Next.IssuedAt.Equal(now) {
+			t.Errorf("unexpected: %+v", set)
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		set := nextChangeSet(token, nil, false, 10, now)
+		if set.HasMore || set.Next.Pos.Seq != 5 || !set.Next.IssuedAt.Equal(now) {
+			t.Errorf("unexpected: %+v", set)
+		}
+	})
+
+	t.Run("more pages keep issued at", func(t *testing.T) {
+		set := nextChangeSet(token, changesOf(6, 7), true, 2, now)
+		if !set.HasMore || set.Next.Pos.Seq != 7 || !set.Next.IssuedAt.Equal(start) {
+			t.Errorf("unexpected: %+v", set)
+		}
+	})
+}
+
+func TestSyncToken_Expired(t *testing.T) {
+	now := time.Now()
+	if (SyncToken{IssuedAt: now.Add(-TombstoneTTL + time.Minute)}).expired(now) {
+		t.Error("token within ttl should not expire")
+	}
+	if !(SyncToken{IssuedAt: now.Add(-TombstoneTTL - time.Minute)}).expired(now) {
+		t.Error("token older than ttl should expire")
+	}
+}
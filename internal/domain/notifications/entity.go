@@ -22,10 +22,12 @@ const (
 	notiStatusMute         notiStatus = "mute"          // endpoint의 알림 끈 경우
 	notiStatusTimeoutReply notiStatus = "timeout_reply" // 리액션 타임아웃 경우
 	notiStatusCancelled    notiStatus = "cancelled"     // 요청자가 취소한 경우
+	notiStatusReacted      notiStatus = "reacted"       // 리액션 완료
 )
 
 type Noti struct {
 	ID           uuid.UUID
+	MessageID    uuid.UUID // 같은 발송으로 만들어진 멤버별 알림은 MessageID가 같다
 	EndpointID   *uuid.UUID
 	EndpointName string
 	UserID       uuid.UUID
@@ -37,6 +39,36 @@ type Noti struct {
 	Actions      []string
 	Reaction     *string
 	ReactionAt   *time.Time
+	Sensitive    bool       // true면 리액션 시 step-up 재인증 필요
+	DeletedAt    *time.Time // 휴지통으로 옮긴 시각
+
+	// endpoint의 sender key로 보낸 경우 (endpoint 토큰이면 nil)
+	SenderKeyID    *uuid.UUID
+	SenderKeyLabel *string
+
+	ReactionComment     *string
+	ReactionUserID      *uuid.UUID
+	ReactionUserEmail   *string // 내보내기에서만 채운다
+	ReactionPushTokenID *uuid.UUID
+	ReactionIP          *string
+	ReactionUserAgent   *string
+	ReactionAuthMethod  *string
+	ReactionAuthAt      *time.Time
+}
+
+// Reaction ask에 대한 응답 한 건. notification_reactions 에 append-only로 쌓인다.
+type Reaction struct {
+	ID             uuid.UUID
+	NotificationID uuid.UUID
+	Reaction       string
+	Comment        *string
+	UserID         *uuid.UUID // 응답한 유저
+	PushTokenID    *uuid.UUID // 응답한 기기
+	IP             *string
+	UserAgent      *string
+	AuthMethod     *string
+	AuthAt         *time.Time
+	CreatedAt      time.Time
 }
 
 func (n *Noti) IsMute() bool {
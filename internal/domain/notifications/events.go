@@ -0,0 +1,50 @@
+package notifications
+
+import (
+	"torchi/internal/domain/sse"
+
+	"github.com/google/uuid"
+)
+
+// 다른 탭, 기기의 목록을 맞추기 위한 SSE 이벤트.
+// ids가 null이면 목록 필터로 처리한 것이라 클라이언트가 변경 피드(GET /notifications/changes)로 맞춰야 한다
+const (
+	EventRead     = "read"     // {ids, read, until, endpoint_id}
+	EventDeleted  = "deleted"  // {ids}
+	EventRestored = "restored" // {ids}
+	EventReacted  = "reacted"  // {id, reaction, comment}
+)
+
+// readEvent until이 있으면 그 알림까지 모두 읽음 (MarkAllAsRead)
+func readEvent(ids []uuid.UUID, read bool, until *uuid.UUID, endpointID *uuid.UUID) sse.SSEEvent {
+	return sse.SSEEvent{
+		Event: EventRead,
+		Data: map[string]any{
+			"ids":         ids,
+			"read":        read,
+			"until":       until,
+			"endpoint_id": endpointID,
+		},
+		EndpointID: endpointID,
+	}
+}
+
+func idsEvent(event string, ids []uuid.UUID) sse.SSEEvent {
+	return sse.SSEEvent{
+		Event: event,
+		Data:  map[string]any{"ids": ids},
+	}
+}
+
+// ReactedEvent ask에 누군가 응답했을 때. 같은 발송을 받은 멤버마다 자기 알림 ID로 보낸다
+func ReactedEvent(noti Noti, reaction, comment string) sse.SSEEvent {
+	return sse.SSEEvent{
+		Event: EventReacted,
+		Data: map[string]any{
+			"id":       noti.ID,
+			"reaction": reaction,
+			"comment":  comment,
+		},
+		EndpointID: noti.EndpointID,
+	}
+}
@@ -0,0 +1,240 @@
+package notifications
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+type ExportFormat string
+
+const (
+	ExportCSV    ExportFormat = "csv"
+	ExportJSON   ExportFormat = "json"
+	ExportNDJSON ExportFormat = "ndjson"
+)
+
+// ParseExportFormat 생략하면 csv
+func ParseExportFormat(v string) (ExportFormat, error) {
+	switch f := ExportFormat(strings.ToLower(strings.TrimSpace(v))); f {
+	case "":
+		return ExportCSV, nil
+	case ExportCSV, ExportJSON, ExportNDJSON:
+		return f, nil
+	}
+	return "", common.ErrInvalidParam
+}
+
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportJSON:
+		return "application/json; charset=utf-8"
+	case ExportNDJSON:
+		return "application/x-ndjson; charset=utf-8"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// exportRecord 내보내기 한 행. csv 열 순서도 이 순서를 따른다
+type exportRecord struct {
+	ID                 uuid.UUID  `json:"id"`
+	MessageID          uuid.UUID  `json:"message_id"`
+	EndpointID         *uuid.UUID `json:"endpoint_id"`
+	EndpointName       string     `json:"endpoint_name"`
+	Body               string     `json:"body"`
+	Status             string     `json:"status"`
+	Actions            []string   `json:"actions"`
+	Sensitive          bool       `json:"sensitive"`
+	SenderKeyLabel     *string    `json:"sender_key_label"`
+	CreatedAt          time.Time  `json:"created_at"`
+	ReadAt             *time.Time `json:"read_at"`
+	Reaction           *string    `json:"reaction"`
+	ReactionComment    *string    `json:"reaction_comment"`
+	ReactionUserEmail  *string    `json:"reaction_user_email"`
+	ReactionAuthMethod *string    `json:"reaction_auth_method"`
+	ReactionAt         *time.Time `json:"reaction_at"`
+}
+
+var exportColumns = []string{
+	"id", "message_id", "endpoint_id", "endpoint_name", "body", "status", "actions", "sensitive",
+	"sender_key_label", "created_at", "read_at",
+	"reaction", "reaction_comment", "reaction_user_email", "reaction_auth_method", "reaction_at",
+}
+
+func toExportRecord(n *Noti) exportRecord {
+	return exportRecord{
+		ID:                 n.ID,
+		MessageID:          n.MessageID,
+		EndpointID:         n.EndpointID,
+		EndpointName:       n.EndpointName,
+		Body:               n.Body,
+		Status:             string(n.Status),
+		Actions:            n.Actions,
+		Sensitive:          n.Sensitive,
+		SenderKeyLabel:     n.SenderKeyLabel,
+		CreatedAt:          n.CreatedAt,
+		ReadAt:             n.ReadAt,
+		Reaction:           n.Reaction,
+		ReactionComment:    n.ReactionComment,
+		ReactionUserEmail:  n.ReactionUserEmail,
+		ReactionAuthMethod: n.ReactionAuthMethod,
+		ReactionAt:         n.ReactionAt,
+	}
+}
+
+func (r exportRecord) csvRow() []string {
+	var endpointID string
+	if r.EndpointID != nil {
+		endpointID = r.EndpointID.String()
+	}
+
+	row := []string{
+		r.ID.String(),
+		r.MessageID.String(),
+		endpointID,
+		r.EndpointName,
+		r.Body,
+		r.Status,
+		strings.Join(r.Actions, "|"),
+		strconv.FormatBool(r.Sensitive),
+		csvString(r.SenderKeyLabel),
+		r.CreatedAt.Format(time.RFC3339),
+		csvTime(r.ReadAt),
+		csvString(r.Reaction),
+		csvString(r.ReactionComment),
+		csvString(r.ReactionUserEmail),
+		csvString(r.ReactionAuthMethod),
+		csvTime(r.ReactionAt),
+	}
+	for i := range row {
+		row[i] = escapeFormula(row[i])
+	}
+	return row
+}
+
+// escapeFormula 스프레드시트가 수식으로 실행하지 않도록 앞에 '를 붙인다
+func escapeFormula(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func csvString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// ExportWriter 알림을 한 건씩 받아 바로 w에 쓴다. 마지막에 Close로 형식을 마무리해야 한다
+type ExportWriter interface {
+	Write(*Noti) error
+	// 버퍼에 남은 내용을 w로 내보낸다
+	Flush() error
+	Close() error
+}
+
+func NewExportWriter(format ExportFormat, w io.Writer) ExportWriter {
+	switch format {
+	case ExportJSON:
+		return &jsonExportWriter{w: w, enc: json.NewEncoder(w)}
+	case ExportNDJSON:
+		return &ndjsonExportWriter{enc: json.NewEncoder(w)}
+	}
+	return &csvExportWriter{w: csv.NewWriter(w)}
+}
+
+type csvExportWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvExportWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.w.Write(exportColumns)
+}
+
+func (c *csvExportWriter) Write(n *Noti) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	return c.w.Write(toExportRecord(n).csvRow())
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// Close 내보낼 알림이 없어도 헤더는 쓴다
+func (c *csvExportWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	return c.Flush()
+}
+
+// jsonExportWriter 전체를 하나의 배열로 쓴다. 중간에 끊기면 닫히지 않은 배열이 되어 잘린 걸 알 수 있다
+type jsonExportWriter struct {
+	w       io.Writer
+	enc     *json.Encoder
+	started bool
+}
+
+func (j *jsonExportWriter) Write(n *Noti) error {
+	sep := ","
+	if !j.started {
+		sep = "["
+		j.started = true
+	}
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	return j.enc.Encode(toExportRecord(n))
+}
+
+func (j *jsonExportWriter) Flush() error {
+	return nil
+}
+
+func (j *jsonExportWriter) Close() error {
+	end := "]\n"
+	if !j.started {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (nd *ndjsonExportWriter) Write(n *Noti) error {
+	return nd.enc.Encode(toExportRecord(n))
+}
+
+func (nd *ndjsonExportWriter) Flush() error {
+	return nil
+}
+
+func (nd *ndjsonExportWriter) Close() error {
+	return nil
+}
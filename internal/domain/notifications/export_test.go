@@ -0,0 +1,158 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+func TestParseExportFormat(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    ExportFormat
+		wantErr error
+	}{
+		{"", ExportCSV, nil},
+		{"csv", ExportCSV, nil},
+		{"JSON", ExportJSON, nil},
+		{"ndjson", ExportNDJSON, nil},
+		{"xml", "", common.ErrInvalidParam},
+	}
+
+	for _, c := range cases {
+		got, err := ParseExportFormat(c.in)
+		if !errors.Is(err, c.wantErr) || got != c.want {
+			t.Errorf("ParseExportFormat(%q) expected: %q, %v, got: %q, %v", c.in, c.want, c.wantErr, got, err)
+		}
+	}
+}
+
+func exportSample(body string) *Noti {
+	return &Noti{
+		ID:           uuid.New(),
+		MessageID:    uuid.New(),
+		EndpointName: "deploy",
+		Body:         body,
+		Status:       notiStatusReacted,
+		Actions:      []string{"approve", "reject"},
+		Reaction:     ptr("approve"),
+		CreatedAt:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func writeAll(t *testing.T, format ExportFormat, notis ...*Noti) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewExportWriter(format, &buf)
+	for _, n := range notis {
+		if err := w.Write(n); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	return buf.String()
+}
+
+func TestExportWriter_CSV(t *testing.T) {
+	out := writeAll(t, ExportCSV, exportSample("=HYPERLINK(\"x\")"), exportSample("line1\nline2"))
+
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("csv parse: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("rows expected: 3, got: %d", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(exportColumns, ",") {
+		t.Errorf("header expected: %v, got: %v", exportColumns, records[0])
+	}
+	if got := records[1][4]; got != "'=HYPERLINK(\"x\")" {
+		t.Errorf("formula not escaped: %q", got)
+	}
+	if got := records[2][4]; got != "line1\nline2" {
+		t.Errorf("multiline body expected to round trip, got: %q", got)
+	}
+	if got := records[1][6]; got != "approve|reject" {
+		t.Errorf("actions expected: approve|reject, got: %q", got)
+	}
+	if got := records[1][9]; got != "2026-01-02T03:04:05Z" {
+		t.Errorf("created_at expected RFC3339, got: %q", got)
+	}
+}
+
+func TestExportWriter_CSVEmpty(t *testing.T) {
+	out := writeAll(t, ExportCSV)
+	if out != strings.Join(exportColumns, ",")+"\n" {
+		t.Errorf("expected header only, got: %q", out)
+	}
+}
+
+func TestExportWriter_JSON(t *testing.T) {
+	for _, n := range []int{0, 1, 3} {
+		notis := make([]*Noti, n)
+		for i := range notis {
+			notis[i] = exportSample("body")
+		}
+
+		var got []exportRecord
+		if err := json.Unmarshal([]byte(writeAll(t, ExportJSON, notis...)), &got); err != nil {
+			t.Fatalf("%d rows: invalid json: %v", n, err)
+		}
+		if len(got) != n {
+			t.Errorf("rows expected: %d, got: %d", n, len(got))
+		}
+	}
+}
+
+func TestExportWriter_NDJSON(t *testing.T) {
+	out := writeAll(t, ExportNDJSON, exportSample("a"), exportSample("b"))
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines expected: 2, got: %d", len(lines))
+	}
+	for _, line := range lines {
+		var r exportRecord
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Errorf("invalid line %q: %v", line, err)
+		}
+	}
+}
+
+// 열 이름과 필드가 어긋나면 RowToStructByName이 실행 중에야 실패하므로 미리 맞춰본다
+func TestExportRow_MatchesColumns(t *testing.T) {
+	selectList, _, _ := strings.Cut(strings.SplitN(exportNotificationsSQL, "SELECT", 2)[1], "FROM")
+	var columns []string
+	for _, expr := range strings.Split(selectList, ",") {
+		fields := strings.Fields(expr)
+		name := fields[len(fields)-1]
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		columns = append(columns, name)
+	}
+
+	var fields []string
+	rt := reflect.TypeFor[exportRow]()
+	for i := range rt.NumField() {
+		name := rt.Field(i).Tag.Get("db")
+		if name == "" {
+			name = strings.ToLower(rt.Field(i).Name)
+		}
+		fields = append(fields, name)
+	}
+
+	if !reflect.DeepEqual(columns, fields) {
+		t.Errorf("expected: %v, got: %v", columns, fields)
+	}
+}
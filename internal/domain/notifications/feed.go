@@ -0,0 +1,150 @@
+package notifications
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+	"torchi/internal/domain/common"
+	"torchi/internal/domain/sse"
+
+	"github.com/google/uuid"
+)
+
+// endpoint 피드 (read scope 키로 구독). 멤버별 알림이 아니라 발송 단위
+type FeedEventType string
+
+const (
+	FeedMessage  FeedEventType = "message"
+	FeedReaction FeedEventType = "reaction"
+)
+
+// FeedEvent message면 ID는 MessageID, reaction이면 응답 이력 ID
+type FeedEvent struct {
+	Type      FeedEventType
+	ID        uuid.UUID
+	MessageID uuid.UUID
+	Body      string
+	Actions   []string
+	Sensitive bool
+	Reaction  *string
+	Comment   *string
+	At        time.Time
+}
+
+// MessageFeedEvent 발송 직후 만든 멤버별 알림 중 하나로
+func MessageFeedEvent(noti Noti) FeedEvent {
+	return FeedEvent{
+		Type:      FeedMessage,
+		ID:        noti.MessageID,
+		MessageID: noti.MessageID,
+		Body:      noti.Body,
+		Actions:   noti.Actions,
+		Sensitive: noti.Sensitive,
+		At:        noti.CreatedAt,
+	}
+}
+
+// ReactionFeedEvent 저장된 응답 이력으로. noti는 응답한 알림
+func ReactionFeedEvent(noti Noti, reaction Reaction) FeedEvent {
+	return FeedEvent{
+		Type:      FeedReaction,
+		ID:        reaction.ID,
+		MessageID: noti.MessageID,
+		Body:      noti.Body,
+		Actions:   noti.Actions,
+		Sensitive: noti.Sensitive,
+		Reaction:  &reaction.Reaction,
+		Comment:   reaction.Comment,
+		At:        reaction.CreatedAt,
+	}
+}
+
+// Cursor 이 이벤트까지 받았다는 poll 커서
+func (e FeedEvent) Cursor() FeedCursor {
+	return FeedCursor{At: e.At, ID: e.ID}
+}
+
+// SSEEvent 실시간 스트림용. data는 poll 응답의 항목과 같다
+func (e FeedEvent) SSEEvent() sse.SSEEvent {
+	return sse.SSEEvent{Event: string(e.Type), Data: e.Data()}
+}
+
+func (e FeedEvent) Data() map[string]any {
+	data := map[string]any{
+		"type":       e.Type,
+		"cursor":     e.Cursor().Encode(),
+		"message_id": e.MessageID,
+		"body":       e.Body,
+		"actions":    e.Actions,
+		"sensitive":  e.Sensitive,
+		"at":         e.At,
+	}
+	if e.Type == FeedReaction {
+		data["id"] = e.ID
+		data["reaction"] = e.Reaction
+		data["comment"] = e.Comment
+	}
+	return data
+}
+
+// FeedCursor (At, ID) 순서에서 어디까지 받았는지
+type FeedCursor struct {
+	At time.Time
+	ID uuid.UUID
+}
+
+func (c FeedCursor) Encode() string {
+	raw := strconv.FormatInt(c.At.UnixMicro(), 10) + "_" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseFeedSince 이전 응답의 커서 또는 "10m" 같은 기간. 비어 있으면 처음부터
+func ParseFeedSince(s string, now time.Time) (FeedCursor, error) {
+	if s == "" {
+		return FeedCursor{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		if d <= 0 {
+			return FeedCursor{}, common.ErrInvalidParam
+		}
+		return FeedCursor{At: now.Add(-d).UTC()}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return FeedCursor{}, common.ErrInvalidParam
+	}
+	atStr, idStr, ok := strings.Cut(string(raw), "_")
+	if !ok {
+		return FeedCursor{}, common.ErrInvalidParam
+	}
+	at, err := strconv.ParseInt(atStr, 10, 64)
+	if err != nil {
+		return FeedCursor{}, common.ErrInvalidParam
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return FeedCursor{}, common.ErrInvalidParam
+	}
+	return FeedCursor{At: time.UnixMicro(at).UTC(), ID: id}, nil
+}
+
+// FeedPage HasMore면 Next로 바로 다시 요청한다. 이벤트가 없으면 Next는 since 그대로
+type FeedPage struct {
+	Events  []FeedEvent
+	Next    FeedCursor
+	HasMore bool
+}
+
+// newFeedPage limit+1개까지 조회한 결과
+func newFeedPage(since FeedCursor, events []FeedEvent, limit int) FeedPage {
+	page := FeedPage{Events: events, Next: since}
+	if len(events) > limit {
+		page.Events, page.HasMore = events[:limit], true
+	}
+	if len(page.Events) > 0 {
+		page.Next = page.Events[len(page.Events)-1].Cursor()
+	}
+	return page
+}
@@ -0,0 +1,88 @@
+package notifications
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+func TestParseFeedSince(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+
+	want := FeedCursor{At: time.UnixMicro(1760000000123456).UTC(), ID: uuid.New()}
+	got, err := ParseFeedSince(want.Encode(), now)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !got.At.Equal(want.At) || got.ID != want.ID {
+		t.Errorf("expected: %+v, got: %+v", want, got)
+	}
+
+	got, err = ParseFeedSince("1h", now)
+	if err != nil || !got.At.Equal(now.Add(-time.Hour)) || got.ID != uuid.Nil {
+		t.Errorf("duration: %+v %v", got, err)
+	}
+
+	got, err = ParseFeedSince("", now)
+	if err != nil || !got.At.IsZero() {
+		t.Errorf("empty should start from the beginning: %+v %v", got, err)
+	}
+
+	for _, s := range []string{"-1h", "0s", "!!", "MTIz", "YWJjXzE"} {
+		if _, err := ParseFeedSince(s, now); !errors.Is(err, common.ErrInvalidParam) {
+			t.Errorf("ParseFeedSince(%q) expected: %v, got: %v", s, common.ErrInvalidParam, err)
+		}
+	}
+}
+
+func feedEvents(n int) []FeedEvent {
+	start := time.Unix(1760000000, 0)
+	events := make([]FeedEvent, n)
+	for i := range events {
+		events[i] = FeedEvent{Type: FeedMessage, ID: uuid.New(), At: start.Add(time.Duration(i) * time.Second)}
+	}
+	return events
+}
+
+func TestNewFeedPage(t *testing.T) {
+	since := FeedCursor{At: time.Unix(1750000000, 0)}
+
+	t.Run("empty keeps since", func(t *testing.T) {
+		page := newFeedPage(since, nil, 10)
+		if page.HasMore || page.Next != since {
+			t.Errorf("unexpected: %+v", page)
+		}
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		events := feedEvents(3)
+		page := newFeedPage(since, events, 10)
+		if page.HasMore || len(page.Events) != 3 || page.Next != events[2].Cursor() {
+			t.Errorf("unexpected: %+v", page)
+		}
+	})
+
+	t.Run("more", func(t *testing.T) {
+		events := feedEvents(3)
+		page := newFeedPage(since, events, 2)
+		if !page.HasMore || len(page.Events) != 2 || page.Next != events[1].Cursor() {
+			t.Errorf("unexpected: %+v", page)
+		}
+	})
+}
+
+func TestFeedEvent_Data(t *testing.T) {
+	reaction := "yes"
+	e := ReactionFeedEvent(Noti{MessageID: uuid.New(), Body: "deploy?"}, Reaction{ID: uuid.New(), Reaction: reaction, CreatedAt: time.Now()})
+
+	data := e.Data()
+	if data["type"] != FeedReaction || data["id"] != e.ID || data["message_id"] != e.MessageID {
+		t.Errorf("unexpected data: %v", data)
+	}
+	if _, ok := MessageFeedEvent(Noti{}).Data()["reaction"]; ok {
+		t.Error("message event should not have reaction fields")
+	}
+}
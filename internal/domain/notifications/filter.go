@@ -0,0 +1,65 @@
+package notifications
+
+import (
+	"strings"
+	"time"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+// ListFilter 목록 조회 필터. 비어있는 항목은 조건에서 빠지고 나머지는 모두 AND로 조합한다
+type ListFilter struct {
+	EndpointID  *uuid.UUID
+	Query       *string // 검색과 같은 문법. 검색 문법이 없으면 본문 부분 일치도 포함
+	Statuses    []string
+	UnreadOnly  bool
+	HasActions  bool // 리액션 버튼이 있는 알림만
+	PendingAsks bool // 아직 응답하지 않았고 끝나지 않은 ask만
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time // 포함하지 않음
+	Reaction    *string
+}
+
+var filterableStatuses = map[notiStatus]struct{}{
+	notiStatusPending:      {},
+	notiStatusSent:         {},
+	notiStatusFailed:       {},
+	notiStatusMute:         {},
+	notiStatusReacted:      {},
+	notiStatusTimeoutReply: {},
+	notiStatusCancelled:    {},
+}
+
+// ParseStatuses 쉼표로 구분한 상태 목록. 알 수 없는 상태가 있으면 ErrInvalidParam
+func ParseStatuses(values []string) ([]string, error) {
+	var result []string
+	for _, v := range values {
+		for _, s := range strings.Split(v, ",") {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			if _, ok := filterableStatuses[notiStatus(s)]; !ok {
+				return nil, common.ErrInvalidParam
+			}
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+// substring Query를 부분 일치에도 쓸 수 있으면 LIKE 이스케이프한 값
+func (f *ListFilter) substring() *string {
+	if f.Query == nil {
+		return nil
+	}
+	return searchSubstring(*f.Query)
+}
+
+func (f *ListFilter) validate() error {
+	if f.CreatedFrom != nil && f.CreatedTo != nil && !f.CreatedFrom.Before(*f.CreatedTo) {
+		return common.ErrInvalidParam
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package notifications
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	"torchi/internal/domain/common"
+)
+
+func TestParseStatuses(t *testing.T) {
+	cases := []struct {
+		name    string
+		values  []string
+		want    []string
+		wantErr error
+	}{
+		{"empty", nil, nil, nil},
+		{"comma separated", []string{"sent,mute"}, []string{"sent", "mute"}, nil},
+		{"repeated", []string{"failed", "reacted"}, []string{"failed", "reacted"}, nil},
+		{"blank items", []string{"sent,, "}, []string{"sent"}, nil},
+		{"unknown", []string{"sent,done"}, nil, common.ErrInvalidParam},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ParseStatuses(c.values)
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("err expected: %v, got: %v", c.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected: %v, got: %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestListFilter_Validate(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	if err := (&ListFilter{CreatedFrom: &now, CreatedTo: &later}).validate(); err != nil {
+		t.Errorf("expected valid range, got: %v", err)
+	}
+	if err := (&ListFilter{CreatedFrom: &later, CreatedTo: &now}).validate(); !errors.Is(err, common.ErrInvalidParam) {
+		t.Errorf("expected ErrInvalidParam, got: %v", err)
+	}
+}
+
+func TestListFilter_Substring(t *testing.T) {
+	if got := (&ListFilter{}).substring(); got != nil {
+		t.Errorf("expected nil without query, got: %v", *got)
+	}
+	if got := (&ListFilter{Query: ptr("50%")}).substring(); got == nil || *got != `50\%` {
+		t.Errorf("expected escaped substring, got: %v", deref(got))
+	}
+	if got := (&ListFilter{Query: ptr("deploy -staging")}).substring(); got != nil {
+		t.Errorf("expected nil for search syntax, got: %v", *got)
+	}
+}
@@ -1,4 +1,6 @@
--- name: CreateNotification :one
+-- name: CreateNotifications :many
+-- endpoint 멤버마다 한 row. 음소거한 멤버의 알림은 mute 상태로 읽음 처리.
+-- 기한이 지난 음소거는 해제 작업 전이라도 음소거로 취급하지 않는다
 INSERT INTO notifications (
     endpoint_id,
     endpoint_name,
@@ -6,19 +8,34 @@ INSERT INTO notifications (
     body,
     actions,
     status,
-    read_at
+    read_at,
+    sensitive,
+    sender_key_id,
+    sender_key_label,
+    message_id,
+    delivery
 )
 SELECT
     e.id,
     e.name,
-    $1,
-    $2,
-    $3,
-    sqlc.narg('status'),
-    sqlc.narg('read_at')
+    m.user_id,
+    sqlc.arg('body'),
+    sqlc.arg('actions'),
+    CASE WHEN m.notification_enabled OR m.muted_until <= now() THEN 'pending' ELSE 'mute' END,
+    CASE WHEN m.notification_enabled OR m.muted_until <= now() THEN NULL ELSE now() END,
+    sqlc.arg('sensitive'),
+    sqlc.narg('sender_key_id'),
+    sqlc.narg('sender_key_label'),
+    sqlc.arg('message_id'),
+    CASE WHEN m.notification_enabled OR m.muted_until <= now() THEN NULL ELSE 'mute' END
 FROM endpoints e
-WHERE e.id = $4
-RETURNING id, endpoint_id, body, actions, created_at, status, read_at;
+JOIN endpoint_members m ON m.endpoint_id = e.id
+WHERE e.id = sqlc.arg('endpoint_id')
+RETURNING id, endpoint_id, user_id, body, actions, created_at, status, read_at, sensitive, sender_key_id, sender_key_label, message_id;
+
+-- name: FindNotificationByID :one
+SELECT * FROM notifications
+WHERE id = $1;
 
 -- name: SaveReaction :exec
 UPDATE notifications
@@ -26,19 +43,105 @@ SET reaction = $2,
     reaction_at = now()
 WHERE id = $1;
 
--- name: SaveReactionIfActive :exec
-UPDATE notifications
-SET reaction = $2,
-    reaction_at = now(),
-    status = 'reacted'
-WHERE id = $1
-  AND status NOT IN ('timeout_reply', 'cancelled');
+-- name: SaveReactionIfActive :one
+-- 같은 발송의 멤버별 알림에 모두 반영하고, 이력은 응답한 알림 기준으로 한 번만 남긴다.
+-- 응답할 수 없는 상태면 행이 없다
+WITH updated AS (
+    UPDATE notifications
+    SET reaction = sqlc.arg('reaction'),
+        reaction_at = now(),
+        reaction_comment = sqlc.narg('comment'),
+        reaction_user_id = sqlc.narg('user_id'),
+        reaction_push_token_id = sqlc.narg('push_token_id'),
+        reaction_ip = sqlc.narg('ip'),
+        reaction_user_agent = sqlc.narg('user_agent'),
+        reaction_auth_method = sqlc.narg('auth_method'),
+        reaction_auth_at = sqlc.narg('auth_at'),
+        status = 'reacted'
+    WHERE notifications.message_id = (
+        SELECT message_id FROM notifications WHERE notifications.id = sqlc.arg('id')
+    )
+      AND status NOT IN ('timeout_reply', 'cancelled')
+    RETURNING
+        notifications.id,
+        message_id,
+        endpoint_id,
+        reaction,
+        reaction_comment,
+        reaction_user_id,
+        reaction_push_token_id,
+        reaction_ip,
+        reaction_user_agent,
+        reaction_auth_method,
+        reaction_auth_at,
+        reaction_at
+)
+INSERT INTO notification_reactions (
+    notification_id,
+    message_id,
+    endpoint_id,
+    reaction,
+    comment,
+    user_id,
+    push_token_id,
+    ip,
+    user_agent,
+    auth_method,
+    auth_at,
+    created_at
+)
+SELECT
+    id,
+    message_id,
+    endpoint_id,
+    reaction,
+    reaction_comment,
+    reaction_user_id,
+    reaction_push_token_id,
+    reaction_ip,
+    reaction_user_agent,
+    reaction_auth_method,
+    reaction_auth_at,
+    reaction_at
+FROM updated
+WHERE id = sqlc.arg('id')
+RETURNING id, created_at;
+
+-- name: FindReactionsByNotificationID :many
+-- 다른 멤버가 응답한 이력도 같은 발송이면 포함. 응답한 알림이 영구 삭제된 이력도 남아있다
+SELECT r.* FROM notification_reactions r
+JOIN notifications n ON n.message_id = r.message_id
+WHERE n.id = sqlc.arg('notification_id')
+  AND n.user_id = sqlc.arg('user_id')
+ORDER BY r.id;
 
 -- name: UpdateStatusNotification :exec
+-- web push 결과 (sent, failed). status는 ask 응답으로 바뀌므로 통계용으로 delivery에도 남긴다
 UPDATE notifications
-SET status = $2
+SET status = $2,
+    delivery = $2
 WHERE id = $1;
 
+-- name: UpdateStatusByMessageID :exec
+-- 같은 발송의 멤버별 알림 상태를 한 번에 변경 (ask 타임아웃, 취소)
+UPDATE notifications
+SET status = $2
+WHERE message_id = $1;
+
+-- name: CountActiveNotificationsByMessageID :one
+SELECT count(*) FROM notifications
+WHERE message_id = $1
+  AND is_deleted = false;
+
+-- name: CountUnreadNotifications :many
+-- 여러 유저를 한 번에 집계 (발송 시 멤버 전체). endpoint가 삭제된 알림은 endpoint_id가 NULL
+SELECT user_id, endpoint_id, count(*) AS count
+FROM notifications
+WHERE user_id = ANY(sqlc.arg('user_ids')::uuid[])
+  AND read_at IS NULL
+  AND is_deleted = false
+GROUP BY user_id, endpoint_id;
+
 -- name: FindNotificationByUserID :many
 SELECT 
     n.*,
@@ -48,6 +151,8 @@ JOIN endpoints e ON n.endpoint_id = e.id
 WHERE n.user_id = $1;
 
 -- name: GetNotificationsWithCursor :many
+-- 필터는 모두 AND로 조합. created_to는 포함하지 않는다.
+-- query는 검색과 같이 tsvector로 찾고, substring이 있으면 부분 일치도 포함한다 (trigram 인덱스)
 SELECT 
     n.id,
     n.endpoint_id,
@@ -59,19 +164,139 @@ SELECT
     n.endpoint_name,
     n.actions,
     n.reaction,
-    n.reaction_at
+    n.reaction_at,
+    n.sensitive,
+    n.reaction_comment,
+    n.sender_key_id,
+    n.sender_key_label
 FROM notifications n
 WHERE n.user_id = $1 
   AND n.is_deleted = false
   AND (sqlc.narg('endpoint_id')::uuid IS NULL OR n.endpoint_id = sqlc.narg('endpoint_id'))
   AND (sqlc.narg('last_id')::uuid IS NULL OR n.id < sqlc.narg('last_id'))
   AND (
-    sqlc.narg('query')::text IS NULL 
-    OR n.body ILIKE '%' || sqlc.narg('query') || '%'
-)
+    sqlc.narg('query')::text IS NULL
+    OR n.search_vector @@ websearch_to_tsquery('simple', sqlc.narg('query'))
+    OR (sqlc.narg('substring')::text IS NOT NULL AND n.body ILIKE '%' || sqlc.narg('substring') || '%')
+  )
+  AND (sqlc.narg('statuses')::text[] IS NULL OR n.status = ANY(sqlc.narg('statuses')::text[]))
+  AND (NOT sqlc.arg('unread_only')::bool OR n.read_at IS NULL)
+  AND (NOT sqlc.arg('has_actions')::bool OR n.actions IS NOT NULL)
+  AND (
+    NOT sqlc.arg('pending_asks')::bool
+    OR (n.actions IS NOT NULL AND n.reaction IS NULL AND n.status NOT IN ('timeout_reply', 'cancelled'))
+  )
+  AND (sqlc.narg('created_from')::timestamp IS NULL OR n.created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamp IS NULL OR n.created_at < sqlc.narg('created_to'))
+  AND (sqlc.narg('reaction')::text IS NULL OR n.reaction = sqlc.narg('reaction'))
 ORDER BY n.id DESC
 LIMIT $2;
 
+-- name: NotificationChangeHorizon :one
+-- 진행 중인 트랜잭션 중 가장 오래된 것. 이보다 앞선 트랜잭션은 모두 끝났으므로 그 변경은 더 늘어나지 않는다.
+-- 변경 피드는 이 앞까지만 내려줘 먼저 받은 change_seq가 늦게 커밋되어도 건너뛰지 않는다
+SELECT pg_snapshot_xmin(pg_current_snapshot())::xid8 AS horizon;
+
+-- name: ListNotificationChanges :many
+-- 변경 피드. since 이후 만들어지거나 바뀐 알림 (휴지통으로 옮긴 것 포함)을 (change_xid, change_seq) 순으로
+SELECT
+    n.id,
+    n.endpoint_id,
+    n.user_id,
+    n.body,
+    n.status,
+    n.read_at,
+    n.created_at,
+    n.endpoint_name,
+    n.actions,
+    n.reaction,
+    n.reaction_at,
+    n.sensitive,
+    n.reaction_comment,
+    n.sender_key_id,
+    n.sender_key_label,
+    n.is_deleted,
+    n.deleted_at,
+    n.change_seq,
+    n.created_seq,
+    n.change_xid,
+    n.created_xid
+FROM notifications n
+WHERE n.user_id = sqlc.arg('user_id')
+  AND (n.change_xid, n.change_seq) > (sqlc.arg('since_xid')::xid8, sqlc.arg('since_seq')::bigint)
+  AND n.change_xid < sqlc.arg('horizon')::xid8
+ORDER BY n.change_xid, n.change_seq
+LIMIT sqlc.arg('limit');
+
+-- name: ListNotificationTombstones :many
+-- 변경 피드. since 이후 영구 삭제된 알림
+SELECT notification_id, change_seq, change_xid
+FROM notification_tombstones
+WHERE user_id = sqlc.arg('user_id')
+  AND (change_xid, change_seq) > (sqlc.arg('since_xid')::xid8, sqlc.arg('since_seq')::bigint)
+  AND change_xid < sqlc.arg('horizon')::xid8
+ORDER BY change_xid, change_seq
+LIMIT sqlc.arg('limit');
+
+-- name: SearchNotifications :many
+-- websearch_to_tsquery 문법("구문", -제외, or)으로 검색. substring이 있으면 부분 일치도 포함한다.
+-- (rank, id) 내림차순이며 커서도 같은 순서로 비교
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg('query')) AS tsq
+), matched AS (
+    SELECT
+        n.id,
+        n.endpoint_id,
+        n.user_id,
+        n.body,
+        n.status,
+        n.read_at,
+        n.created_at,
+        n.endpoint_name,
+        n.actions,
+        n.reaction,
+        n.reaction_at,
+        n.sensitive,
+        n.reaction_comment,
+        n.sender_key_id,
+        n.sender_key_label,
+        ts_rank(n.search_vector, q.tsq) AS rank
+    FROM notifications n, q
+    WHERE n.user_id = sqlc.arg('user_id')
+      AND n.is_deleted = false
+      AND (sqlc.narg('endpoint_id')::uuid IS NULL OR n.endpoint_id = sqlc.narg('endpoint_id'))
+      AND (
+        n.search_vector @@ q.tsq
+        OR (sqlc.narg('substring')::text IS NOT NULL AND n.body ILIKE '%' || sqlc.narg('substring') || '%')
+      )
+), page AS (
+    SELECT * FROM matched
+    WHERE sqlc.narg('cursor_id')::uuid IS NULL
+       OR (matched.rank, matched.id) < (sqlc.narg('cursor_rank')::real, sqlc.narg('cursor_id')::uuid)
+    ORDER BY matched.rank DESC, matched.id DESC
+    LIMIT sqlc.arg('limit')
+)
+SELECT
+    page.id,
+    page.endpoint_id,
+    page.user_id,
+    page.body,
+    page.status,
+    page.read_at,
+    page.created_at,
+    page.endpoint_name,
+    page.actions,
+    page.reaction,
+    page.reaction_at,
+    page.sensitive,
+    page.reaction_comment,
+    page.sender_key_id,
+    page.sender_key_label,
+    page.rank,
+    ts_headline('simple', page.body, q.tsq, 'StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5, MaxFragments=2')::text AS snippet
+FROM page, q
+ORDER BY page.rank DESC, page.id DESC;
+
 -- name: MarkNotificationsAsReadBefore :exec
 UPDATE notifications
 SET read_at = now()
@@ -82,6 +307,164 @@ WHERE user_id = $1
 
 -- name: MarkDeleteNotificationByID :exec
 UPDATE notifications
-SET is_deleted = true
+SET is_deleted = true,
+    deleted_at = now()
 WHERE user_id = $1
-  AND id = $2;  
\ No newline at end of file
+  AND id = $2
+  AND is_deleted = false;
+
+-- name: SetNotificationsRead :execrows
+-- 이미 같은 상태인 알림도 영향받은 수에 포함된다 (읽은 시각은 처음 값 유지)
+UPDATE notifications
+SET read_at = CASE WHEN sqlc.arg('read')::bool THEN coalesce(read_at, now()) ELSE NULL END
+WHERE user_id = sqlc.arg('user_id')
+  AND id = ANY(sqlc.arg('ids')::uuid[])
+  AND is_deleted = false;
+
+-- name: SetNotificationsReadByFilter :execrows
+-- 필터 조건은 GetNotificationsWithCursor와 같다
+UPDATE notifications n
+SET read_at = CASE WHEN sqlc.arg('read')::bool THEN coalesce(n.read_at, now()) ELSE NULL END
+WHERE n.user_id = sqlc.arg('user_id')
+  AND n.is_deleted = false
+  AND (sqlc.narg('endpoint_id')::uuid IS NULL OR n.endpoint_id = sqlc.narg('endpoint_id'))
+  AND (
+    sqlc.narg('query')::text IS NULL
+    OR n.search_vector @@ websearch_to_tsquery('simple', sqlc.narg('query'))
+    OR (sqlc.narg('substring')::text IS NOT NULL AND n.body ILIKE '%' || sqlc.narg('substring') || '%')
+  )
+  AND (sqlc.narg('statuses')::text[] IS NULL OR n.status = ANY(sqlc.narg('statuses')::text[]))
+  AND (NOT sqlc.arg('unread_only')::bool OR n.read_at IS NULL)
+  AND (NOT sqlc.arg('has_actions')::bool OR n.actions IS NOT NULL)
+  AND (
+    NOT sqlc.arg('pending_asks')::bool
+    OR (n.actions IS NOT NULL AND n.reaction IS NULL AND n.status NOT IN ('timeout_reply', 'cancelled'))
+  )
+  AND (sqlc.narg('created_from')::timestamp IS NULL OR n.created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamp IS NULL OR n.created_at < sqlc.narg('created_to'))
+  AND (sqlc.narg('reaction')::text IS NULL OR n.reaction = sqlc.narg('reaction'));
+
+-- name: MarkDeleteNotifications :many
+-- 대기 중인 ask를 끝낼지 확인하도록 지운 알림의 message_id를 반환
+UPDATE notifications
+SET is_deleted = true,
+    deleted_at = now()
+WHERE user_id = sqlc.arg('user_id')
+  AND id = ANY(sqlc.arg('ids')::uuid[])
+  AND is_deleted = false
+RETURNING message_id;
+
+-- name: MarkDeleteNotificationsByFilter :many
+-- 필터 조건은 GetNotificationsWithCursor와 같다
+UPDATE notifications n
+SET is_deleted = true,
+    deleted_at = now()
+WHERE n.user_id = sqlc.arg('user_id')
+  AND n.is_deleted = false
+  AND (sqlc.narg('endpoint_id')::uuid IS NULL OR n.endpoint_id = sqlc.narg('endpoint_id'))
+  AND (
+    sqlc.narg('query')::text IS NULL
+    OR n.search_vector @@ websearch_to_tsquery('simple', sqlc.narg('query'))
+    OR (sqlc.narg('substring')::text IS NOT NULL AND n.body ILIKE '%' || sqlc.narg('substring') || '%')
+  )
+  AND (sqlc.narg('statuses')::text[] IS NULL OR n.status = ANY(sqlc.narg('statuses')::text[]))
+  AND (NOT sqlc.arg('unread_only')::bool OR n.read_at IS NULL)
+  AND (NOT sqlc.arg('has_actions')::bool OR n.actions IS NOT NULL)
+  AND (
+    NOT sqlc.arg('pending_asks')::bool
+    OR (n.actions IS NOT NULL AND n.reaction IS NULL AND n.status NOT IN ('timeout_reply', 'cancelled'))
+  )
+  AND (sqlc.narg('created_from')::timestamp IS NULL OR n.created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamp IS NULL OR n.created_at < sqlc.narg('created_to'))
+  AND (sqlc.narg('reaction')::text IS NULL OR n.reaction = sqlc.narg('reaction'))
+RETURNING n.message_id;
+
+-- name: RestoreNotifications :execrows
+UPDATE notifications
+SET is_deleted = false,
+    deleted_at = NULL
+WHERE user_id = sqlc.arg('user_id')
+  AND id = ANY(sqlc.arg('ids')::uuid[])
+  AND is_deleted = true;
+
+-- name: ListDeletedNotifications :many
+-- 휴지통. 목록과 같은 id 내림차순 커서
+SELECT
+    n.id,
+    n.endpoint_id,
+    n.user_id,
+    n.body,
+    n.status,
+    n.read_at,
+    n.created_at,
+    n.endpoint_name,
+    n.actions,
+    n.reaction,
+    n.reaction_at,
+    n.sensitive,
+    n.reaction_comment,
+    n.sender_key_id,
+    n.sender_key_label,
+    n.deleted_at
+FROM notifications n
+WHERE n.user_id = sqlc.arg('user_id')
+  AND n.is_deleted = true
+  AND (sqlc.narg('last_id')::uuid IS NULL OR n.id < sqlc.narg('last_id'))
+ORDER BY n.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: PurgeDeletedNotifications :execrows
+-- 휴지통에서 영구 삭제. 리액션 이력은 notification_id만 비워진 채 남는다
+DELETE FROM notifications
+WHERE user_id = sqlc.arg('user_id')
+  AND is_deleted = true
+  AND deleted_at < sqlc.arg('deleted_before');  
+
+-- name: ListEndpointFeed :many
+-- endpoint로 온 발송(멤버별 알림 중 하나)과 응답 이력을 (at, id) 순으로. 휴지통으로 옮긴 알림도 포함.
+-- 알림이 영구 삭제돼 notification_id가 비어도 응답 이력은 endpoint_id로 찾고, 본문은 남은 알림이 있을 때만 채운다.
+-- at은 트랜잭션 시작 시각이라 늦게 커밋된 행이 이미 지나간 커서 앞에 끼어들 수 있으므로 10초가 지난 행만 내려준다
+SELECT feed.kind, feed.id, feed.message_id, feed.body, feed.actions, feed.sensitive, feed.reaction, feed.comment, feed.at
+FROM (
+    (
+        SELECT DISTINCT ON (n.message_id)
+            'message'::text AS kind,
+            n.message_id AS id,
+            n.message_id,
+            n.body,
+            n.actions,
+            n.sensitive,
+            NULL::text AS reaction,
+            NULL::text AS comment,
+            n.created_at AS at
+        FROM notifications n
+        WHERE n.endpoint_id = sqlc.arg('endpoint_id')
+          AND (n.created_at, n.message_id) > (sqlc.arg('since_at')::timestamp, sqlc.arg('since_id')::uuid)
+          AND n.created_at < clock_timestamp()::timestamp - interval '10 seconds'
+        ORDER BY n.message_id
+    )
+    UNION ALL
+    (
+        SELECT
+            'reaction'::text AS kind,
+            r.id,
+            r.message_id,
+            coalesce(n.body, '') AS body,
+            n.actions,
+            coalesce(n.sensitive, false) AS sensitive,
+            r.reaction,
+            r.comment,
+            r.created_at AS at
+        FROM notification_reactions r
+        LEFT JOIN LATERAL (
+            SELECT m.body, m.actions, m.sensitive FROM notifications m
+            WHERE m.message_id = r.message_id
+            LIMIT 1
+        ) n ON true
+        WHERE r.endpoint_id = sqlc.arg('endpoint_id')
+          AND (r.created_at, r.id) > (sqlc.arg('since_at')::timestamp, sqlc.arg('since_id')::uuid)
+          AND r.created_at < clock_timestamp()::timestamp - interval '10 seconds'
+    )
+) feed
+ORDER BY feed.at, feed.id
+LIMIT sqlc.arg('limit');
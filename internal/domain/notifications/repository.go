@@ -2,27 +2,53 @@ package notifications
 
 import (
 	"context"
+	"time"
 	db "torchi/internal/infrastructure/db/postgresql"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 type notiRepository struct {
 	queries *db.Queries
+	// sqlc로 만들 수 없는 쿼리용 (Export)
+	conn db.DBTX
 }
 
 type NotiRepository interface {
-	Create(context.Context, Noti) (Noti, error)
+	// endpoint 멤버마다 한 건씩 만든다
+	Create(context.Context, Noti) ([]Noti, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*Noti, error)
 	UpdateStatus(context.Context, Noti) error
-	GetWithCursor(ctx context.Context, userID uuid.UUID, lastID *uuid.UUID, limit int32, endpointID *uuid.UUID, query *string) ([]Noti, error)
+	UpdateStatusByMessageID(ctx context.Context, messageID uuid.UUID, status notiStatus) error
+	CountActiveByMessageID(ctx context.Context, messageID uuid.UUID) (int64, error)
+	// 요청한 유저는 읽지 않은 알림이 없어도 결과에 포함된다
+	CountUnread(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]UnreadCounts, error)
+	GetWithCursor(ctx context.Context, userID uuid.UUID, lastID *uuid.UUID, limit int32, filter ListFilter) ([]Noti, error)
+	Search(ctx context.Context, params searchParams) ([]SearchResult, error)
+	ChangeHorizon(ctx context.Context) (uint64, error)
+	// since 이후 변경을 seq 순으로 limit개까지. 더 남아있으면 true
+	ListChanges(ctx context.Context, userID uuid.UUID, since ChangePos, limit int32) ([]Change, bool, error)
+	// 결과를 메모리에 모으지 않고 한 건씩 fn으로 넘긴다
+	Export(ctx context.Context, userID uuid.UUID, filter ListFilter, fn func(*Noti) error) error
 	MarkAsReadBefore(ctx context.Context, userID uuid.UUID, lastID uuid.UUID, endpointID *uuid.UUID) error
 	MarkDelete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
-	SaveReaction(ctx context.Context, notiID uuid.UUID, reaction string) error
+	SetRead(ctx context.Context, userID uuid.UUID, target BulkTarget, read bool) (int64, error)
+	// 지운 알림의 message_id를 반환
+	MarkDeleteMany(ctx context.Context, userID uuid.UUID, target BulkTarget) ([]uuid.UUID, error)
+	Restore(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int64, error)
+	GetTrash(ctx context.Context, userID uuid.UUID, lastID *uuid.UUID, limit int32) ([]Noti, error)
+	PurgeTrash(ctx context.Context, userID uuid.UUID, deletedBefore time.Time) (int64, error)
+	// SaveReaction 응답할 수 없는 상태면 nil
+	SaveReaction(ctx context.Context, reaction Reaction) (*Reaction, error)
+	FindReactions(ctx context.Context, userID uuid.UUID, notiID uuid.UUID) ([]Reaction, error)
+	ListFeed(ctx context.Context, endpointID uuid.UUID, since FeedCursor, limit int32) ([]FeedEvent, error)
 }
 
-func NewNotiRepository(queries *db.Queries) NotiRepository {
+func NewNotiRepository(queries *db.Queries, database *db.Database) NotiRepository {
 	return &notiRepository{
 		queries: queries,
+		conn:    database.Pool,
 	}
 }
 
@@ -41,14 +67,188 @@ func (r *notiRepository) MarkAsReadBefore(ctx context.Context, userID uuid.UUID,
 	})
 }
 
-func (r *notiRepository) GetWithCursor(ctx context.Context, userID uuid.UUID, lastID *uuid.UUID, limit int32, endpointID *uuid.UUID, query *string) ([]Noti, error) {
+func (r *notiRepository) SetRead(ctx context.Context, userID uuid.UUID, target BulkTarget, read bool) (int64, error) {
+	if target.Filter == nil {
+		return r.queries.SetNotificationsRead(ctx, db.SetNotificationsReadParams{
+			Read:   read,
+			UserID: userID,
+			Ids:    target.IDs,
+		})
+	}
+
+	f := target.Filter
+	return r.queries.SetNotificationsReadByFilter(ctx, db.SetNotificationsReadByFilterParams{
+		Read:        read,
+		UserID:      userID,
+		EndpointID:  f.EndpointID,
+		Query:       f.Query,
+		Substring:   f.substring(),
+		Statuses:    f.Statuses,
+		UnreadOnly:  f.UnreadOnly,
+		HasActions:  f.HasActions,
+		PendingAsks: f.PendingAsks,
+		CreatedFrom: f.CreatedFrom,
+		CreatedTo:   f.CreatedTo,
+		Reaction:    f.Reaction,
+	})
+}
+
+func (r *notiRepository) MarkDeleteMany(ctx context.Context, userID uuid.UUID, target BulkTarget) ([]uuid.UUID, error) {
+	if target.Filter == nil {
+		return r.queries.MarkDeleteNotifications(ctx, db.MarkDeleteNotificationsParams{
+			UserID: userID,
+			Ids:    target.IDs,
+		})
+	}
+
+	f := target.Filter
+	return r.queries.MarkDeleteNotificationsByFilter(ctx, db.MarkDeleteNotificationsByFilterParams{
+		UserID:      userID,
+		EndpointID:  f.EndpointID,
+		Query:       f.Query,
+		Substring:   f.substring(),
+		Statuses:    f.Statuses,
+		UnreadOnly:  f.UnreadOnly,
+		HasActions:  f.HasActions,
+		PendingAsks: f.PendingAsks,
+		CreatedFrom: f.CreatedFrom,
+		CreatedTo:   f.CreatedTo,
+		Reaction:    f.Reaction,
+	})
+}
+
+func (r *notiRepository) Restore(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	return r.queries.RestoreNotifications(ctx, db.RestoreNotificationsParams{
+		UserID: userID,
+		Ids:    ids,
+	})
+}
+
+func (r *notiRepository) GetTrash(ctx context.Context, userID uuid.UUID, lastID *uuid.UUID, limit int32) ([]Noti, error) {
+	rows, err := r.queries.ListDeletedNotifications(ctx, db.ListDeletedNotificationsParams{
+		UserID: userID,
+		LastID: lastID,
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Noti, 0, len(rows))
+	for _, row := range rows {
+		var s notiStatus
+		if row.Status != nil {
+			s = notiStatus(*row.Status)
+		}
+
+		result = append(result, Noti{
+			ID:           row.ID,
+			EndpointID:   row.EndpointID,
+			EndpointName: row.EndpointName,
+			UserID:       row.UserID,
+			Body:         row.Body,
+			Status:       s,
+			ReadAt:       row.ReadAt,
+			CreatedAt:    row.CreatedAt,
+			IsDeleted:    true,
+			Actions:      row.Actions,
+			Reaction:     row.Reaction,
+			ReactionAt:   row.ReactionAt,
+			Sensitive:    row.Sensitive,
+			DeletedAt:    row.DeletedAt,
+
+			ReactionComment: row.ReactionComment,
+			SenderKeyID:     row.SenderKeyID,
+			SenderKeyLabel:  row.SenderKeyLabel,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *notiRepository) PurgeTrash(ctx context.Context, userID uuid.UUID, deletedBefore time.Time) (int64, error) {
+	return r.queries.PurgeDeletedNotifications(ctx, db.PurgeDeletedNotificationsParams{
+		UserID:        userID,
+		DeletedBefore: &deletedBefore,
+	})
+}
+
+type searchParams struct {
+	userID     uuid.UUID
+	query      string
+	substring  *string
+	endpointID *uuid.UUID
+	cursor     *SearchCursor
+	limit      int32
+}
+
+func (r *notiRepository) Search(ctx context.Context, params searchParams) ([]SearchResult, error) {
+	arg := db.SearchNotificationsParams{
+		Query:      params.query,
+		UserID:     params.userID,
+		EndpointID: params.endpointID,
+		Substring:  params.substring,
+		Limit:      params.limit,
+	}
+	if params.cursor != nil {
+		arg.CursorID = &params.cursor.ID
+		arg.CursorRank = &params.cursor.Rank
+	}
+
+	rows, err := r.queries.SearchNotifications(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SearchResult, 0, len(rows))
+	for _, row := range rows {
+		var s notiStatus
+		if row.Status != nil {
+			s = notiStatus(*row.Status)
+		}
+
+		result = append(result, SearchResult{
+			Noti: Noti{
+				ID:           row.ID,
+				EndpointID:   row.EndpointID,
+				EndpointName: row.EndpointName,
+				UserID:       row.UserID,
+				Body:         row.Body,
+				Status:       s,
+				ReadAt:       row.ReadAt,
+				CreatedAt:    row.CreatedAt,
+				Actions:      row.Actions,
+				Reaction:     row.Reaction,
+				ReactionAt:   row.ReactionAt,
+				Sensitive:    row.Sensitive,
+
+				ReactionComment: row.ReactionComment,
+				SenderKeyID:     row.SenderKeyID,
+				SenderKeyLabel:  row.SenderKeyLabel,
+			},
+			Rank:    row.Rank,
+			Snippet: escapeSnippet(row.Snippet),
+		})
+	}
+	return result, nil
+}
+
+func (r *notiRepository) GetWithCursor(ctx context.Context, userID uuid.UUID, lastID *uuid.UUID, limit int32, filter ListFilter) ([]Noti, error) {
 
 	params := db.GetNotificationsWithCursorParams{
-		UserID:     userID,
-		Limit:      limit,
-		LastID:     lastID,
-		EndpointID: endpointID,
-		Query:      query,
+		UserID:      userID,
+		Limit:       limit,
+		LastID:      lastID,
+		EndpointID:  filter.EndpointID,
+		Query:       filter.Query,
+		Substring:   filter.substring(),
+		Statuses:    filter.Statuses,
+		UnreadOnly:  filter.UnreadOnly,
+		HasActions:  filter.HasActions,
+		PendingAsks: filter.PendingAsks,
+		CreatedFrom: filter.CreatedFrom,
+		CreatedTo:   filter.CreatedTo,
+		Reaction:    filter.Reaction,
 	}
 
 	rows, err := r.queries.GetNotificationsWithCursor(ctx, params)
@@ -75,47 +275,301 @@ func (r *notiRepository) GetWithCursor(ctx context.Context, userID uuid.UUID, la
 			Actions:      row.Actions,
 			Reaction:     row.Reaction,
 			ReactionAt:   row.ReactionAt,
+			Sensitive:    row.Sensitive,
+
+			ReactionComment: row.ReactionComment,
+			SenderKeyID:     row.SenderKeyID,
+			SenderKeyLabel:  row.SenderKeyLabel,
 		})
 	}
 
 	return result, nil
 }
 
-func (r *notiRepository) Create(ctx context.Context, noti Noti) (Noti, error) {
-	var statusPtr *string
-	if noti.Status != "" {
-		s := string(noti.Status)
-		statusPtr = &s
+func (r *notiRepository) ChangeHorizon(ctx context.Context) (uint64, error) {
+	return r.queries.NotificationChangeHorizon(ctx)
+}
+
+func (r *notiRepository) ListChanges(ctx context.Context, userID uuid.UUID, since ChangePos, limit int32) ([]Change, bool, error) {
+	// 두 조회가 같은 지점까지 보도록 horizon을 먼저 구한다
+	horizon, err := r.queries.NotificationChangeHorizon(ctx)
+	if err != nil {
+		return nil, false, err
 	}
 
-	createdRow, err := r.queries.CreateNotification(ctx, db.CreateNotificationParams{
-		UserID:  noti.UserID,
-		Body:    noti.Body,
-		Actions: noti.Actions,
-		ID:      *noti.EndpointID,
-		Status:  statusPtr,
-		ReadAt:  noti.ReadAt,
+	rows, err := r.queries.ListNotificationChanges(ctx, db.ListNotificationChangesParams{
+		UserID:   userID,
+		SinceXid: since.Xid,
+		SinceSeq: since.Seq,
+		Horizon:  horizon,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	tombstones, err := r.queries.ListNotificationTombstones(ctx, db.ListNotificationTombstonesParams{
+		UserID:   userID,
+		SinceXid: since.Xid,
+		SinceSeq: since.Seq,
+		Horizon:  horizon,
+		Limit:    limit,
 	})
 	if err != nil {
-		return Noti{}, err
+		return nil, false, err
+	}
+
+	live := make([]Change, 0, len(rows))
+	for _, row := range rows {
+		change := Change{
+			Type: changeType(since, ChangePos{Xid: row.CreatedXid, Seq: row.CreatedSeq}, row.IsDeleted),
+			ID:   row.ID,
+			Pos:  ChangePos{Xid: row.ChangeXid, Seq: row.ChangeSeq},
+		}
+		if change.Type != ChangeDeleted {
+			var s notiStatus
+			if row.Status != nil {
+				s = notiStatus(*row.Status)
+			}
+
+			change.Noti = &Noti{
+				ID:           row.ID,
+				EndpointID:   row.EndpointID,
+				EndpointName: row.EndpointName,
+				UserID:       row.UserID,
+				Body:         row.Body,
+				Status:       s,
+				ReadAt:       row.ReadAt,
+				CreatedAt:    row.CreatedAt,
+				Actions:      row.Actions,
+				Reaction:     row.Reaction,
+				ReactionAt:   row.ReactionAt,
+				Sensitive:    row.Sensitive,
+
+				ReactionComment: row.ReactionComment,
+				SenderKeyID:     row.SenderKeyID,
+				SenderKeyLabel:  row.SenderKeyLabel,
+			}
+		}
+		live = append(live, change)
+	}
+
+	deleted := make([]Change, 0, len(tombstones))
+	for _, row := range tombstones {
+		deleted = append(deleted, Change{
+			Type: ChangeDeleted,
+			ID:   row.NotificationID,
+			Pos:  ChangePos{Xid: row.ChangeXid, Seq: row.ChangeSeq},
+		})
+	}
+
+	merged := mergeChanges(live, deleted, int(limit))
+	return merged, len(live)+len(deleted) > len(merged), nil
+}
+
+// exportNotificationsSQL sqlc의 :many는 결과를 슬라이스에 모두 모으므로 내보내기만 직접 쿼리해서 한 행씩 읽는다.
+// 필터 조건은 GetNotificationsWithCursor와 같다
+const exportNotificationsSQL = `
+SELECT
+    n.id,
+    n.message_id,
+    n.endpoint_id,
+    n.endpoint_name,
+    n.body,
+    n.status,
+    n.actions,
+    n.sensitive,
+    n.read_at,
+    n.created_at,
+    n.reaction,
+    n.reaction_at,
+    n.reaction_comment,
+    n.reaction_auth_method,
+    ru.email AS reaction_user_email,
+    n.sender_key_label
+FROM notifications n
+LEFT JOIN users ru ON ru.id = n.reaction_user_id
+WHERE n.user_id = @user_id
+  AND n.is_deleted = false
+  AND (@endpoint_id::uuid IS NULL OR n.endpoint_id = @endpoint_id)
+  AND (
+    @query::text IS NULL
+    OR n.search_vector @@ websearch_to_tsquery('simple', @query)
+    OR (@substring::text IS NOT NULL AND n.body ILIKE '%' || @substring || '%')
+  )
+  AND (@statuses::text[] IS NULL OR n.status = ANY(@statuses::text[]))
+  AND (NOT @unread_only::bool OR n.read_at IS NULL)
+  AND (NOT @has_actions::bool OR n.actions IS NOT NULL)
+  AND (
+    NOT @pending_asks::bool
+    OR (n.actions IS NOT NULL AND n.reaction IS NULL AND n.status NOT IN ('timeout_reply', 'cancelled'))
+  )
+  AND (@created_from::timestamp IS NULL OR n.created_at >= @created_from)
+  AND (@created_to::timestamp IS NULL OR n.created_at < @created_to)
+  AND (@reaction::text IS NULL OR n.reaction = @reaction)
+ORDER BY n.id DESC
+`
+
+// exportRow exportNotificationsSQL의 한 행. 열 이름으로 채운다
+type exportRow struct {
+	ID                 uuid.UUID
+	MessageID          uuid.UUID  `db:"message_id"`
+	EndpointID         *uuid.UUID `db:"endpoint_id"`
+	EndpointName       string     `db:"endpoint_name"`
+	Body               string
+	Status             *string
+	Actions            []string
+	Sensitive          bool
+	ReadAt             *time.Time `db:"read_at"`
+	CreatedAt          time.Time  `db:"created_at"`
+	Reaction           *string
+	ReactionAt         *time.Time `db:"reaction_at"`
+	ReactionComment    *string    `db:"reaction_comment"`
+	ReactionAuthMethod *string    `db:"reaction_auth_method"`
+	ReactionUserEmail  *string    `db:"reaction_user_email"`
+	SenderKeyLabel     *string    `db:"sender_key_label"`
+}
+
+func (r *notiRepository) Export(ctx context.Context, userID uuid.UUID, filter ListFilter, fn func(*Noti) error) error {
+	rows, err := r.conn.Query(ctx, exportNotificationsSQL, pgx.NamedArgs{
+		"user_id":      userID,
+		"endpoint_id":  filter.EndpointID,
+		"query":        filter.Query,
+		"substring":    filter.substring(),
+		"statuses":     filter.Statuses,
+		"unread_only":  filter.UnreadOnly,
+		"has_actions":  filter.HasActions,
+		"pending_asks": filter.PendingAsks,
+		"created_from": filter.CreatedFrom,
+		"created_to":   filter.CreatedTo,
+		"reaction":     filter.Reaction,
+	})
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row, err := pgx.RowToStructByName[exportRow](rows)
+		if err != nil {
+			return err
+		}
+
+		var s notiStatus
+		if row.Status != nil {
+			s = notiStatus(*row.Status)
+		}
+
+		if err := fn(&Noti{
+			ID:           row.ID,
+			MessageID:    row.MessageID,
+			EndpointID:   row.EndpointID,
+			EndpointName: row.EndpointName,
+			UserID:       userID,
+			Body:         row.Body,
+			Status:       s,
+			CreatedAt:    row.CreatedAt,
+			ReadAt:       row.ReadAt,
+			Actions:      row.Actions,
+			Reaction:     row.Reaction,
+			ReactionAt:   row.ReactionAt,
+			Sensitive:    row.Sensitive,
+
+			ReactionComment:    row.ReactionComment,
+			ReactionUserEmail:  row.ReactionUserEmail,
+			ReactionAuthMethod: row.ReactionAuthMethod,
+			SenderKeyLabel:     row.SenderKeyLabel,
+		}); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *notiRepository) FindByID(ctx context.Context, id uuid.UUID) (*Noti, error) {
+	row, err := r.queries.FindNotificationByID(ctx, id)
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, nil
+		}
+		return nil, err
 	}
 
 	var s notiStatus
-	if createdRow.Status != nil {
-		s = notiStatus(*createdRow.Status)
-	}
-
-	return Noti{
-		ID:         createdRow.ID,
-		EndpointID: createdRow.EndpointID,
-		Body:       createdRow.Body,
-		Actions:    createdRow.Actions,
-		CreatedAt:  createdRow.CreatedAt,
-		Status:     s,
-		ReadAt:     createdRow.ReadAt,
+	if row.Status != nil {
+		s = notiStatus(*row.Status)
+	}
+
+	return &Noti{
+		ID:           row.ID,
+		MessageID:    row.MessageID,
+		EndpointID:   row.EndpointID,
+		EndpointName: row.EndpointName,
+		UserID:       row.UserID,
+		Body:         row.Body,
+		Status:       s,
+		CreatedAt:    row.CreatedAt,
+		ReadAt:       row.ReadAt,
+		IsDeleted:    row.IsDeleted,
+		Actions:      row.Actions,
+		Reaction:     row.Reaction,
+		ReactionAt:   row.ReactionAt,
+		Sensitive:    row.Sensitive,
+		DeletedAt:    row.DeletedAt,
+
+		ReactionComment:     row.ReactionComment,
+		ReactionUserID:      row.ReactionUserID,
+		ReactionPushTokenID: row.ReactionPushTokenID,
+		ReactionIP:          row.ReactionIp,
+		ReactionUserAgent:   row.ReactionUserAgent,
+		ReactionAuthMethod:  row.ReactionAuthMethod,
+		ReactionAuthAt:      row.ReactionAuthAt,
+
+		SenderKeyID:    row.SenderKeyID,
+		SenderKeyLabel: row.SenderKeyLabel,
 	}, nil
 }
 
+func (r *notiRepository) Create(ctx context.Context, noti Noti) ([]Noti, error) {
+	rows, err := r.queries.CreateNotifications(ctx, db.CreateNotificationsParams{
+		Body:       noti.Body,
+		Actions:    noti.Actions,
+		Sensitive:  noti.Sensitive,
+		MessageID:  noti.MessageID,
+		EndpointID: *noti.EndpointID,
+
+		SenderKeyID:    noti.SenderKeyID,
+		SenderKeyLabel: noti.SenderKeyLabel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Noti, 0, len(rows))
+	for _, row := range rows {
+		var s notiStatus
+		if row.Status != nil {
+			s = notiStatus(*row.Status)
+		}
+
+		result = append(result, Noti{
+			ID:         row.ID,
+			MessageID:  row.MessageID,
+			EndpointID: row.EndpointID,
+			UserID:     row.UserID,
+			Body:       row.Body,
+			Actions:    row.Actions,
+			CreatedAt:  row.CreatedAt,
+			Status:     s,
+			ReadAt:     row.ReadAt,
+			Sensitive:  row.Sensitive,
+
+			SenderKeyID:    row.SenderKeyID,
+			SenderKeyLabel: row.SenderKeyLabel,
+		})
+	}
+	return result, nil
+}
+
 func (r *notiRepository) UpdateStatus(ctx context.Context, noti Noti) error {
 	status := string(noti.Status)
 	err := r.queries.UpdateStatusNotification(ctx, db.UpdateStatusNotificationParams{
@@ -125,9 +579,117 @@ func (r *notiRepository) UpdateStatus(ctx context.Context, noti Noti) error {
 	return err
 }
 
-func (r *notiRepository) SaveReaction(ctx context.Context, notiID uuid.UUID, reaction string) error {
-	return r.queries.SaveReactionIfActive(ctx, db.SaveReactionIfActiveParams{
-		ID:       notiID,
-		Reaction: &reaction,
+func (r *notiRepository) UpdateStatusByMessageID(ctx context.Context, messageID uuid.UUID, status notiStatus) error {
+	s := string(status)
+	return r.queries.UpdateStatusByMessageID(ctx, db.UpdateStatusByMessageIDParams{
+		MessageID: messageID,
+		Status:    &s,
+	})
+}
+
+func (r *notiRepository) CountActiveByMessageID(ctx context.Context, messageID uuid.UUID) (int64, error) {
+	return r.queries.CountActiveNotificationsByMessageID(ctx, messageID)
+}
+
+func (r *notiRepository) CountUnread(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]UnreadCounts, error) {
+	rows, err := r.queries.CountUnreadNotifications(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID]UnreadCounts, len(userIDs))
+	for _, id := range userIDs {
+		result[id] = newUnreadCounts()
+	}
+	for _, row := range rows {
+		c := result[row.UserID]
+		c.add(row.EndpointID, row.Count)
+		result[row.UserID] = c
+	}
+	return result, nil
+}
+
+func (r *notiRepository) SaveReaction(ctx context.Context, reaction Reaction) (*Reaction, error) {
+	row, err := r.queries.SaveReactionIfActive(ctx, db.SaveReactionIfActiveParams{
+		ID:          reaction.NotificationID,
+		Reaction:    &reaction.Reaction,
+		Comment:     reaction.Comment,
+		UserID:      reaction.UserID,
+		PushTokenID: reaction.PushTokenID,
+		Ip:          reaction.IP,
+		UserAgent:   reaction.UserAgent,
+		AuthMethod:  reaction.AuthMethod,
+		AuthAt:      reaction.AuthAt,
+	})
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	reaction.ID = row.ID
+	reaction.CreatedAt = row.CreatedAt
+	return &reaction, nil
+}
+
+func (r *notiRepository) ListFeed(ctx context.Context, endpointID uuid.UUID, since FeedCursor, limit int32) ([]FeedEvent, error) {
+	rows, err := r.queries.ListEndpointFeed(ctx, db.ListEndpointFeedParams{
+		EndpointID: &endpointID,
+		SinceAt:    since.At,
+		SinceID:    since.ID,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]FeedEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, FeedEvent{
+			Type:      FeedEventType(row.Kind),
+			ID:        row.ID,
+			MessageID: row.MessageID,
+			Body:      row.Body,
+			Actions:   row.Actions,
+			Sensitive: row.Sensitive,
+			Reaction:  row.Reaction,
+			Comment:   row.Comment,
+			At:        row.At,
+		})
+	}
+	return events, nil
+}
+
+func (r *notiRepository) FindReactions(ctx context.Context, userID uuid.UUID, notiID uuid.UUID) ([]Reaction, error) {
+	rows, err := r.queries.FindReactionsByNotificationID(ctx, db.FindReactionsByNotificationIDParams{
+		NotificationID: notiID,
+		UserID:         userID,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Reaction, 0, len(rows))
+	for _, row := range rows {
+		// 응답한 알림이 영구 삭제된 이력은 NotificationID가 비어있다
+		var notiID uuid.UUID
+		if row.NotificationID != nil {
+			notiID = *row.NotificationID
+		}
+		result = append(result, Reaction{
+			ID:             row.ID,
+			NotificationID: notiID,
+			Reaction:       row.Reaction,
+			Comment:        row.Comment,
+			UserID:         row.UserID,
+			PushTokenID:    row.PushTokenID,
+			IP:             row.Ip,
+			UserAgent:      row.UserAgent,
+			AuthMethod:     row.AuthMethod,
+			AuthAt:         row.AuthAt,
+			CreatedAt:      row.CreatedAt,
+		})
+	}
+	return result, nil
 }
@@ -0,0 +1,91 @@
+package notifications
+
+import (
+	"encoding/base64"
+	"html"
+	"strconv"
+	"strings"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+const (
+	snippetStart = "<mark>"
+	snippetStop  = "</mark>"
+)
+
+// SearchResult 검색 결과. Snippet은 HTML 이스케이프되어 있고 일치한 부분만 <mark>로 감싼다
+type SearchResult struct {
+	Noti
+	Rank    float32
+	Snippet string
+}
+
+// SearchCursor (Rank, ID) 내림차순 정렬 기준의 마지막 항목
+type SearchCursor struct {
+	Rank float32
+	ID   uuid.UUID
+}
+
+// Encode 클라이언트에 넘길 불투명한 커서 문자열
+func (c SearchCursor) Encode() string {
+	raw := strconv.FormatFloat(float64(c.Rank), 'g', -1, 32) + "_" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeSearchCursor(s string) (*SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+
+	rankStr, idStr, ok := strings.Cut(string(raw), "_")
+	if !ok {
+		return nil, common.ErrInvalidParam
+	}
+	rank, err := strconv.ParseFloat(rankStr, 32)
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, common.ErrInvalidParam
+	}
+	return &SearchCursor{Rank: float32(rank), ID: id}, nil
+}
+
+// searchSubstring 검색 문법이 없는 검색어면 부분 일치에도 쓴다.
+// 구문("...")이나 제외(-), or가 있으면 부분 일치로는 의미가 달라지므로 nil
+func searchSubstring(query string) *string {
+	for _, word := range strings.Fields(query) {
+		if strings.HasPrefix(word, "-") || strings.EqualFold(word, "or") {
+			return nil
+		}
+	}
+	if strings.Contains(query, `"`) {
+		return nil
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query)
+	return &escaped
+}
+
+// escapeSnippet ts_headline 결과에서 강조 표시를 제외한 본문을 HTML 이스케이프
+func escapeSnippet(snippet string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(snippet, snippetStart) {
+		if i > 0 {
+			b.WriteString(snippetStart)
+		}
+		hit, rest, found := strings.Cut(part, snippetStop)
+		if !found {
+			b.WriteString(html.EscapeString(part))
+			continue
+		}
+		b.WriteString(html.EscapeString(hit))
+		b.WriteString(snippetStop)
+		b.WriteString(html.EscapeString(rest))
+	}
+	return b.String()
+}
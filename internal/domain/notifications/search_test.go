@@ -0,0 +1,69 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSearchCursor_RoundTrip(t *testing.T) {
+	want := SearchCursor{Rank: 0.0607927, ID: uuid.New()}
+
+	got, err := DecodeSearchCursor(want.Encode())
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if *got != want {
+		t.Errorf("expected: %+v, got: %+v", want, *got)
+	}
+
+	if _, err := DecodeSearchCursor("not-a-cursor"); err == nil {
+		t.Error("expected error for invalid cursor")
+	}
+}
+
+func TestSearchSubstring(t *testing.T) {
+	cases := []struct {
+		query string
+		want  *string
+	}{
+		{"배포", ptr("배포")},
+		{"deploy prod", ptr("deploy prod")},
+		{"100%_done", ptr(`100\%\_done`)},
+		{`"deploy failed"`, nil},
+		{"deploy -staging", nil},
+		{"deploy or rollback", nil},
+	}
+
+	for _, c := range cases {
+		got := searchSubstring(c.query)
+		if (got == nil) != (c.want == nil) || (got != nil && *got != *c.want) {
+			t.Errorf("searchSubstring(%q) expected: %v, got: %v", c.query, deref(c.want), deref(got))
+		}
+	}
+}
+
+func TestEscapeSnippet(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{"<mark>deploy</mark> failed", "<mark>deploy</mark> failed"},
+		{"<script>x</script> <mark>a&b</mark>", "&lt;script&gt;x&lt;/script&gt; <mark>a&amp;b</mark>"},
+	}
+
+	for _, c := range cases {
+		if got := escapeSnippet(c.in); got != c.want {
+			t.Errorf("escapeSnippet(%q) expected: %q, got: %q", c.in, c.want, got)
+		}
+	}
+}
+
+func ptr(s string) *string { return &s }
+
+func deref(s *string) string {
+	if s == nil {
+		return "<nil>"
+	}
+	return *s
+}
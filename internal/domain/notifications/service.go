@@ -2,62 +2,255 @@ package notifications
 
 import (
 	"context"
+	"strings"
 	"time"
+	"torchi/internal/domain/common"
 	"torchi/internal/domain/sse"
+	"torchi/internal/pkg/log"
 
 	"github.com/google/uuid"
 )
 
 type NotiService struct {
+	log       *log.Logger
 	repo      NotiRepository
 	sseBroker *sse.Broker
 }
 
 func NewNotiService(
+	log *log.Logger,
 	repo NotiRepository,
 	sseBroker *sse.Broker,
 ) *NotiService {
 	return &NotiService{
+		log:       log,
 		repo:      repo,
 		sseBroker: sseBroker,
 	}
 }
 
 func (s *NotiService) MarkDelete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
-	return s.repo.MarkDelete(ctx, userID, id)
+	if err := s.repo.MarkDelete(ctx, userID, id); err != nil {
+		return err
+	}
+
+	s.sseBroker.Publish(userID, idsEvent(EventDeleted, []uuid.UUID{id}))
+	s.publishUnread(ctx, userID)
+	return nil
 }
 func (s *NotiService) MarkAllAsRead(ctx context.Context, userID uuid.UUID, lastID uuid.UUID, endpointID *uuid.UUID) error {
-	return s.repo.MarkAsReadBefore(ctx, userID, lastID, endpointID)
+	if err := s.repo.MarkAsReadBefore(ctx, userID, lastID, endpointID); err != nil {
+		return err
+	}
+
+	s.sseBroker.Publish(userID, readEvent(nil, true, &lastID, endpointID))
+	s.publishUnread(ctx, userID)
+	return nil
+}
+
+// SetRead 알림 하나를 읽음 또는 안 읽음으로. 없거나 삭제한 알림이면 ErrNotificationNotFound
+func (s *NotiService) SetRead(ctx context.Context, userID, id uuid.UUID, read bool) error {
+	affected, err := s.repo.SetRead(ctx, userID, BulkTarget{IDs: []uuid.UUID{id}}, read)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return common.ErrNotificationNotFound
+	}
+
+	s.sseBroker.Publish(userID, readEvent([]uuid.UUID{id}, read, nil, nil))
+	s.publishUnread(ctx, userID)
+	return nil
+}
+
+// Bulk ID 목록 또는 목록 필터에 맞는 알림에 한 번에 적용
+func (s *NotiService) Bulk(ctx context.Context, userID uuid.UUID, action BulkAction, target BulkTarget) (BulkResult, error) {
+	if err := target.validate(action); err != nil {
+		return BulkResult{}, err
+	}
+
+	var result BulkResult
+	var err error
+	switch action {
+	case BulkRead, BulkUnread:
+		result.Affected, err = s.repo.SetRead(ctx, userID, target, action == BulkRead)
+	case BulkDelete:
+		var messageIDs []uuid.UUID
+		messageIDs, err = s.repo.MarkDeleteMany(ctx, userID, target)
+		result.Affected = int64(len(messageIDs))
+		result.MessageIDs = uniqueIDs(messageIDs)
+	case BulkRestore:
+		result.Affected, err = s.repo.Restore(ctx, userID, target.IDs)
+	}
+	if err != nil {
+		return BulkResult{}, err
+	}
+
+	if result.Affected > 0 {
+		s.publishBulk(userID, action, target)
+		s.publishUnread(ctx, userID)
+	}
+	return result, nil
+}
+
+// GetTrash 휴지통 목록. 목록과 같은 id 커서
+func (s *NotiService) GetTrash(ctx context.Context, userID uuid.UUID, lastID *uuid.UUID, limit int32) ([]Noti, error) {
+	return s.repo.GetTrash(ctx, userID, lastID, limit)
+}
+
+// PurgeTrash olderThanDays일보다 오래전에 지운 알림을 영구 삭제. 0이면 휴지통 비우기
+func (s *NotiService) PurgeTrash(ctx context.Context, userID uuid.UUID, olderThanDays int) (int64, error) {
+	cutoff, err := trashCutoff(time.Now(), olderThanDays)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.PurgeTrash(ctx, userID, cutoff)
+}
+
+// UnreadCounts 전체, endpoint별 읽지 않은 알림 수
+func (s *NotiService) UnreadCounts(ctx context.Context, userID uuid.UUID) (UnreadCounts, error) {
+	counts, err := s.repo.CountUnread(ctx, []uuid.UUID{userID})
+	if err != nil {
+		return UnreadCounts{}, err
+	}
+	return counts[userID], nil
+}
+
+// UnreadCountsOf 여러 유저를 한 번에 집계. 발송 직후 멤버 전체의 배지 갱신용
+func (s *NotiService) UnreadCountsOf(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]UnreadCounts, error) {
+	return s.repo.CountUnread(ctx, userIDs)
+}
+
+// publishBulk 필터로 처리했으면 ids 없이 보낸다
+func (s *NotiService) publishBulk(userID uuid.UUID, action BulkAction, target BulkTarget) {
+	switch action {
+	case BulkRead, BulkUnread:
+		s.sseBroker.Publish(userID, readEvent(target.IDs, action == BulkRead, nil, nil))
+	case BulkDelete:
+		s.sseBroker.Publish(userID, idsEvent(EventDeleted, target.IDs))
+	case BulkRestore:
+		s.sseBroker.Publish(userID, idsEvent(EventRestored, target.IDs))
+	}
+}
+
+// publishUnread 다른 탭, 기기의 배지를 맞추기 위해 바뀐 수를 보낸다.
+// 읽음 처리 자체는 끝났으므로 집계 실패는 기록만 한다
+func (s *NotiService) publishUnread(ctx context.Context, userID uuid.UUID) {
+	counts, err := s.UnreadCounts(ctx, userID)
+	if err != nil {
+		s.log.Error("count unread", "user_id", userID, "err", err)
+		return
+	}
+
+	s.sseBroker.Publish(userID, sse.SSEEvent{
+		Event: EventUnread,
+		Data:  counts.EventData(),
+	})
+}
+
+func (s *NotiService) GetListWithCursor(ctx context.Context, userID uuid.UUID, lastID *uuid.UUID, limit int32, filter ListFilter) ([]Noti, error) {
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetWithCursor(ctx, userID, lastID, limit, filter)
 }
 
-func (s *NotiService) GetListWithCursor(ctx context.Context, userID uuid.UUID, lastID *uuid.UUID, limit int32, endpointID *uuid.UUID, query *string) ([]Noti, error) {
+// Changes token 이후의 변경. token이 없으면 변경 없이 지금 시점의 token만 준다.
+// 삭제 기록이 지워졌을 만큼 오래된 token이면 ErrSyncTokenExpired
+func (s *NotiService) Changes(ctx context.Context, userID uuid.UUID, token *SyncToken, limit int32) (*ChangeSet, error) {
+	now := time.Now()
+	if token == nil {
+		horizon, err := s.repo.ChangeHorizon(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &ChangeSet{Next: SyncToken{Pos: ChangePos{Xid: horizon}, IssuedAt: now}}, nil
+	}
+	if token.expired(now) {
+		return nil, common.ErrSyncTokenExpired
+	}
 
-	return s.repo.GetWithCursor(ctx, userID, lastID, limit, endpointID, query)
+	changes, more, err := s.repo.ListChanges(ctx, userID, token.Pos, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	set := nextChangeSet(*token, changes, more, int(limit), now)
+	return &set, nil
+}
+
+// Export 목록과 같은 필터로 전체 이력을 한 건씩 fn에 넘긴다
+func (s *NotiService) Export(ctx context.Context, userID uuid.UUID, filter ListFilter, fn func(*Noti) error) error {
+	if err := filter.validate(); err != nil {
+		return err
+	}
+
+	return s.repo.Export(ctx, userID, filter, fn)
+}
+
+// Search 검색어 문법은 websearch_to_tsquery를 따른다 ("구문", -제외, or)
+func (s *NotiService) Search(ctx context.Context, userID uuid.UUID, query string, endpointID *uuid.UUID, cursor *SearchCursor, limit int32) ([]SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, common.ErrInvalidParam
+	}
+
+	return s.repo.Search(ctx, searchParams{
+		userID:     userID,
+		query:      query,
+		substring:  searchSubstring(query),
+		endpointID: endpointID,
+		cursor:     cursor,
+		limit:      limit,
+	})
+}
+
+func (s *NotiService) FindByID(ctx context.Context, id uuid.UUID) (*Noti, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Get 본인 알림 하나. 없거나 다른 유저의 알림이면 ErrNotificationNotFound, 삭제했으면 ErrNotificationDeleted
+func (s *NotiService) Get(ctx context.Context, userID, id uuid.UUID) (*Noti, error) {
+	noti, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if noti == nil || noti.UserID != userID {
+		return nil, common.ErrNotificationNotFound
+	}
+	if noti.IsDeleted {
+		return nil, common.ErrNotificationDeleted
+	}
+	return noti, nil
 }
 
 type ReqRegister struct {
-	EndpointID         uuid.UUID
-	UserID             uuid.UUID
-	Body               string
-	NotificationEnable bool
-	Actions            []string
+	EndpointID     uuid.UUID
+	Body           string
+	Actions        []string
+	Sensitive      bool
+	SenderKeyID    *uuid.UUID
+	SenderKeyLabel *string
 }
 
-func (s *NotiService) Register(ctx context.Context, req ReqRegister) (noti Noti, err error) {
-	newNoti := Noti{
+// Register endpoint 멤버마다 알림을 만든다. 음소거한 멤버의 알림은 mute 상태로 읽음 처리되어 있다.
+func (s *NotiService) Register(ctx context.Context, req ReqRegister) ([]Noti, error) {
+	messageID, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(ctx, Noti{
+		MessageID:  messageID,
 		EndpointID: &req.EndpointID,
 		Body:       req.Body,
-		UserID:     req.UserID,
 		Actions:    req.Actions,
-	}
-
-	if !req.NotificationEnable {
-		newNoti.Status = notiStatusMute
-		now := time.Now()
-		newNoti.ReadAt = &now
-	}
+		Sensitive:  req.Sensitive,
 
-	return s.repo.Create(ctx, newNoti)
+		SenderKeyID:    req.SenderKeyID,
+		SenderKeyLabel: req.SenderKeyLabel,
+	})
 }
 
 type ReqUpdateStatus struct {
@@ -82,20 +275,80 @@ func (s *NotiService) UpdateStatusSent(ctx context.Context, reqID uuid.UUID) err
 	})
 }
 
-func (s *NotiService) UpdateStatusTimeout(ctx context.Context, reqID uuid.UUID) error {
+// UpdateStatusFailed 통계에서 실패한 발송을 셀 수 있도록 기록
+func (s *NotiService) UpdateStatusFailed(ctx context.Context, reqID uuid.UUID) error {
 	return s.updateStatus(ctx, ReqUpdateStatus{
 		ID:     reqID,
-		Status: notiStatusTimeoutReply,
+		Status: notiStatusFailed,
 	})
 }
 
-func (s *NotiService) UpdateStatusCancelled(ctx context.Context, reqID uuid.UUID) error {
-	return s.updateStatus(ctx, ReqUpdateStatus{
-		ID:     reqID,
-		Status: notiStatusCancelled,
+// UpdateStatusTimeout 같은 발송의 멤버별 알림을 모두 타임아웃 처리
+func (s *NotiService) UpdateStatusTimeout(ctx context.Context, messageID uuid.UUID) error {
+	return s.repo.UpdateStatusByMessageID(ctx, messageID, notiStatusTimeoutReply)
+}
+
+// UpdateStatusCancelled 같은 발송의 멤버별 알림을 모두 취소 처리
+func (s *NotiService) UpdateStatusCancelled(ctx context.Context, messageID uuid.UUID) error {
+	return s.repo.UpdateStatusByMessageID(ctx, messageID, notiStatusCancelled)
+}
+
+// IsAbandoned 같은 발송의 알림을 모든 멤버가 삭제했는지
+func (s *NotiService) IsAbandoned(ctx context.Context, messageID uuid.UUID) (bool, error) {
+	count, err := s.repo.CountActiveByMessageID(ctx, messageID)
+	if err != nil {
+		return false, err
+	}
+	return count == 0, nil
+}
+
+type ReqSaveReaction struct {
+	ID       uuid.UUID
+	Reaction string
+	Comment  string
+
+	// 응답자 정보. 로그인 없이 응답한 경우 UserID 등은 nil
+	UserID      *uuid.UUID
+	PushTokenID *uuid.UUID
+	IP          string
+	UserAgent   string
+	AuthMethod  *string
+	AuthAt      *time.Time
+}
+
+// SaveReaction 이미 끝난 ask(타임아웃, 취소)면 저장하지 않고 nil
+func (s *NotiService) SaveReaction(ctx context.Context, req ReqSaveReaction) (*Reaction, error) {
+	return s.repo.SaveReaction(ctx, Reaction{
+		NotificationID: req.ID,
+		Reaction:       req.Reaction,
+		Comment:        nilIfEmpty(req.Comment),
+		UserID:         req.UserID,
+		PushTokenID:    req.PushTokenID,
+		IP:             nilIfEmpty(req.IP),
+		UserAgent:      nilIfEmpty(req.UserAgent),
+		AuthMethod:     req.AuthMethod,
+		AuthAt:         req.AuthAt,
 	})
 }
 
-func (s *NotiService) SaveReaction(ctx context.Context, notiID uuid.UUID, reaction string) error {
-	return s.repo.SaveReaction(ctx, notiID, reaction)
+// Feed endpoint로 온 발송과 응답을 since 이후부터. 멤버별 알림이 아닌 발송 단위
+func (s *NotiService) Feed(ctx context.Context, endpointID uuid.UUID, since FeedCursor, limit int32) (*FeedPage, error) {
+	events, err := s.repo.ListFeed(ctx, endpointID, since, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := newFeedPage(since, events, int(limit))
+	return &page, nil
+}
+
+// FindReactions 알림의 리액션 이력 (오래된 순)
+func (s *NotiService) FindReactions(ctx context.Context, userID uuid.UUID, notiID uuid.UUID) ([]Reaction, error) {
+	return s.repo.FindReactions(ctx, userID, notiID)
+}
+
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
 }
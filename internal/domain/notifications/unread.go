@@ -0,0 +1,31 @@
+package notifications
+
+import "github.com/google/uuid"
+
+// EventUnread 읽지 않은 알림 수가 바뀌었을 때 보내는 SSE 이벤트 (모두 읽음 처리, 삭제)
+const EventUnread = "unread"
+
+// UnreadCounts 읽지 않은 알림 수. endpoint가 삭제된 알림은 Total에만 들어간다
+type UnreadCounts struct {
+	Total      int64
+	ByEndpoint map[uuid.UUID]int64
+}
+
+func newUnreadCounts() UnreadCounts {
+	return UnreadCounts{ByEndpoint: map[uuid.UUID]int64{}}
+}
+
+func (c *UnreadCounts) add(endpointID *uuid.UUID, count int64) {
+	c.Total += count
+	if endpointID != nil {
+		c.ByEndpoint[*endpointID] += count
+	}
+}
+
+// EventData SSE 이벤트에 싣는 형태. GET /notifications/unread-count 응답과 같다
+func (c UnreadCounts) EventData() map[string]any {
+	return map[string]any{
+		"total":     c.Total,
+		"endpoints": c.ByEndpoint,
+	}
+}
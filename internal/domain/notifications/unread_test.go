@@ -0,0 +1,27 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestUnreadCounts_Add(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+
+	c := newUnreadCounts()
+	c.add(&a, 2)
+	c.add(&b, 1)
+	c.add(nil, 3)
+	c.add(&a, 1)
+
+	if c.Total != 7 {
+		t.Errorf("total expected: 7, got: %d", c.Total)
+	}
+	if c.ByEndpoint[a] != 3 || c.ByEndpoint[b] != 1 {
+		t.Errorf("by endpoint expected: a=3 b=1, got: %v", c.ByEndpoint)
+	}
+	if len(c.ByEndpoint) != 2 {
+		t.Errorf("deleted endpoint should not be listed, got: %v", c.ByEndpoint)
+	}
+}
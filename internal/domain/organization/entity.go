@@ -0,0 +1,73 @@
+package organization
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// organization_members.role
+const (
+	RoleOwner  = "owner"  // 조직 삭제, 역할 변경
+	RoleAdmin  = "admin"  // 멤버 추가/제외, 조직 endpoint 관리
+	RoleMember = "member" // 조직 endpoint 조회와 알림 구독
+)
+
+type Organization struct {
+	ID        uuid.UUID
+	Name      string
+	Role      string // 조회한 유저의 역할
+	CreatedAt time.Time
+}
+
+type Member struct {
+	UserID   uuid.UUID
+	Role     string
+	Email    *string
+	Guest    bool
+	JoinedAt time.Time
+}
+
+// Invite 이메일로 보낸 조직 초대. 그 이메일로 가입한 유저가 수락하면 Role로 멤버가 된다
+type Invite struct {
+	ID        uuid.UUID
+	OrgID     uuid.UUID
+	OrgName   string // 받은 초대 목록에서만 채워진다
+	Email     string // 보낸 초대 목록에서만 채워진다
+	Role      string
+	InvitedBy *uuid.UUID
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// CanManageEndpoints 조직 endpoint의 설정, 토큰, sender key, 초대, 멤버를 관리할 수 있는 역할
+func CanManageEndpoints(role string) bool {
+	return role == RoleOwner || role == RoleAdmin
+}
+
+func isValidRole(role string) bool {
+	return role == RoleOwner || role == RoleAdmin || role == RoleMember
+}
+
+// rank 높을수록 권한이 많음
+func rank(role string) int {
+	switch role {
+	case RoleOwner:
+		return 3
+	case RoleAdmin:
+		return 2
+	case RoleMember:
+		return 1
+	}
+	return 0
+}
+
+// canAssign actor가 다른 멤버에게 role을 줄 수 있는지. admin은 admin까지만 줄 수 있다
+func canAssign(actor, role string) bool {
+	return CanManageEndpoints(actor) && rank(role) <= rank(actor)
+}
+
+// canRemove actor가 target 역할의 멤버를 내보낼 수 있는지. 자기보다 높은 역할은 내보낼 수 없다
+func canRemove(actor, target string) bool {
+	return CanManageEndpoints(actor) && rank(target) <= rank(actor)
+}
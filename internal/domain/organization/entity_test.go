@@ -0,0 +1,44 @@
+package organization
+
+import "testing"
+
+func TestCanAssign(t *testing.T) {
+	cases := []struct {
+		actor, role string
+		want        bool
+	}{
+		{RoleOwner, RoleOwner, true},
+		{RoleOwner, RoleMember, true},
+		{RoleAdmin, RoleAdmin, true},
+		{RoleAdmin, RoleMember, true},
+		{RoleAdmin, RoleOwner, false},
+		{RoleMember, RoleMember, false},
+		{"", RoleMember, false},
+	}
+
+	for _, c := range cases {
+		if got := canAssign(c.actor, c.role); got != c.want {
+			t.Errorf("canAssign(%q, %q) expected: %v, got: %v", c.actor, c.role, c.want, got)
+		}
+	}
+}
+
+func TestCanRemove(t *testing.T) {
+	cases := []struct {
+		actor, target string
+		want          bool
+	}{
+		{RoleOwner, RoleOwner, true},
+		{RoleOwner, RoleAdmin, true},
+		{RoleAdmin, RoleMember, true},
+		{RoleAdmin, RoleAdmin, true},
+		{RoleAdmin, RoleOwner, false},
+		{RoleMember, RoleMember, false},
+	}
+
+	for _, c := range cases {
+		if got := canRemove(c.actor, c.target); got != c.want {
+			t.Errorf("canRemove(%q, %q) expected: %v, got: %v", c.actor, c.target, c.want, got)
+		}
+	}
+}
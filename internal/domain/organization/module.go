@@ -0,0 +1,10 @@
+package organization
+
+import "go.uber.org/fx"
+
+var Module = fx.Options(
+	fx.Provide(
+		NewOrganizationRepository,
+		NewOrganizationService,
+	),
+)
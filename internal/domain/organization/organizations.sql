@@ -0,0 +1,135 @@
+-- name: CreateOrganization :one
+-- 만든 유저를 owner로 함께 등록
+WITH created AS (
+    INSERT INTO organizations (name)
+    VALUES (sqlc.arg('name'))
+    RETURNING id
+), owner AS (
+    INSERT INTO organization_members (org_id, user_id, role)
+    SELECT id, sqlc.arg('user_id'), 'owner' FROM created
+)
+SELECT id FROM created;
+
+-- name: ListOrganizationsByUserID :many
+SELECT o.*, m.role
+FROM organizations o
+JOIN organization_members m ON m.org_id = o.id
+WHERE m.user_id = $1
+ORDER BY o.created_at;
+
+-- name: FindOrganizationRole :one
+SELECT role FROM organization_members
+WHERE org_id = $1
+  AND user_id = $2;
+
+-- name: ListOrganizationMembers :many
+SELECT
+    m.user_id,
+    m.role,
+    m.created_at,
+    u.email,
+    u.guest
+FROM organization_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.org_id = $1
+ORDER BY m.created_at;
+
+-- name: UpsertOrganizationInvite :exec
+-- 같은 이메일로 다시 초대하면 역할과 만료를 새로 정한다
+INSERT INTO organization_invites (org_id, email, role, invited_by, expires_at)
+VALUES (sqlc.arg('org_id'), sqlc.arg('email'), sqlc.arg('role'), sqlc.arg('invited_by'), sqlc.arg('expires_at'))
+ON CONFLICT (org_id, email) DO UPDATE
+SET role = EXCLUDED.role,
+    invited_by = EXCLUDED.invited_by,
+    expires_at = EXCLUDED.expires_at,
+    created_at = now();
+
+-- name: ListOrganizationInvites :many
+-- 만료된 초대도 포함
+SELECT * FROM organization_invites
+WHERE org_id = $1
+ORDER BY created_at;
+
+-- name: DeleteOrganizationInvite :execrows
+DELETE FROM organization_invites
+WHERE id = $1
+  AND org_id = $2;
+
+-- name: ListOrganizationInvitesForUser :many
+-- 유저의 이메일로 온 만료되지 않은 초대. guest는 받을 수 없다
+SELECT
+    i.id,
+    i.org_id,
+    o.name AS org_name,
+    i.role,
+    i.expires_at,
+    i.created_at
+FROM organization_invites i
+JOIN organizations o ON o.id = i.org_id
+JOIN users u ON lower(u.email) = i.email
+WHERE u.id = $1
+  AND u.guest = false
+  AND i.expires_at > now()
+ORDER BY i.created_at;
+
+-- name: AcceptOrganizationInvite :one
+-- 유저의 이메일로 온 초대면 삭제하고 멤버로 등록. 이미 멤버면 역할을 바꾸지 않는다
+WITH accepted AS (
+    DELETE FROM organization_invites i
+    USING users u
+    WHERE i.id = sqlc.arg('id')
+      AND u.id = sqlc.arg('user_id')
+      AND u.guest = false
+      AND lower(u.email) = i.email
+      AND i.expires_at > now()
+    RETURNING i.org_id, i.role
+)
+INSERT INTO organization_members (org_id, user_id, role)
+SELECT org_id, sqlc.arg('user_id'), role FROM accepted
+ON CONFLICT (org_id, user_id) DO UPDATE SET role = organization_members.role
+RETURNING org_id;
+
+-- name: DeclineOrganizationInvite :execrows
+DELETE FROM organization_invites i
+USING users u
+WHERE i.id = $1
+  AND u.id = $2
+  AND lower(u.email) = i.email;
+
+-- name: UpdateOrganizationMemberRole :execrows
+UPDATE organization_members
+SET role = $1
+WHERE org_id = $2
+  AND user_id = $3;
+
+-- name: RemoveOrganizationMember :execrows
+-- 조직 endpoint의 알림 수신도 함께 해제
+WITH unsubscribed AS (
+    DELETE FROM endpoint_members m
+    USING endpoints e, organization_members om
+    WHERE e.id = m.endpoint_id
+      AND e.org_id = $1
+      AND m.user_id = $2
+      AND om.org_id = e.org_id
+      AND om.user_id = m.user_id
+)
+DELETE FROM organization_members
+WHERE org_id = $1
+  AND user_id = $2;
+
+-- name: LockOrganizationForOwners :exec
+-- 같은 문장 안의 잠금은 이미 잡은 스냅샷을 바꾸지 않으므로 따로 잠근다.
+-- 잠금을 얻은 뒤 실행한 CountOrganizationOwners는 먼저 커밋된 역할 변경까지 센다
+SELECT id FROM organizations
+WHERE id = $1
+FOR UPDATE;
+
+-- name: CountOrganizationOwners :one
+SELECT count(*) FROM organization_members
+WHERE org_id = $1
+  AND role = 'owner';
+
+-- name: DeleteOrganization :execrows
+-- 조직 endpoint와 그 알림 수신 설정도 함께 삭제됨
+DELETE FROM organizations
+WHERE id = $1;
@@ -0,0 +1,261 @@
+package organization
+
+import (
+	"context"
+	"time"
+	"torchi/internal/domain/common"
+	db "torchi/internal/infrastructure/db/postgresql"
+
+	"github.com/google/uuid"
+)
+
+type OrganizationRepository interface {
+	Create(ctx context.Context, name string, userID uuid.UUID) (uuid.UUID, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]Organization, error)
+	// 멤버가 아니면 빈 문자열
+	FindRole(ctx context.Context, orgID, userID uuid.UUID) (string, error)
+	ListMembers(ctx context.Context, orgID uuid.UUID) ([]Member, error)
+	// owner를 강등하거나 내보내 owner가 남지 않으면 common.ErrLastOrgOwner
+	UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role string) (int64, error)
+	RemoveMember(ctx context.Context, orgID, userID uuid.UUID) (int64, error)
+	Delete(ctx context.Context, orgID uuid.UUID) (int64, error)
+
+	// 같은 이메일의 초대가 있으면 역할과 만료를 새로 정한다
+	UpsertInvite(ctx context.Context, params upsertInviteParams) error
+	ListInvites(ctx context.Context, orgID uuid.UUID) ([]Invite, error)
+	DeleteInvite(ctx context.Context, orgID, id uuid.UUID) (int64, error)
+	// userID의 이메일로 온 만료되지 않은 초대
+	ListInvitesForUser(ctx context.Context, userID uuid.UUID) ([]Invite, error)
+	// userID의 이메일로 온 만료되지 않은 초대가 아니면 false
+	AcceptInvite(ctx context.Context, id, userID uuid.UUID) (bool, error)
+	DeclineInvite(ctx context.Context, id, userID uuid.UUID) (int64, error)
+}
+
+type organizationRepository struct {
+	queries *db.Queries
+	// 여러 쿼리를 한 트랜잭션으로 묶을 때 (changeMember)
+	database *db.Database
+}
+
+func NewOrganizationRepository(queries *db.Queries, database *db.Database) OrganizationRepository {
+	return &organizationRepository{
+		queries:  queries,
+		database: database,
+	}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, name string, userID uuid.UUID) (uuid.UUID, error) {
+	return r.queries.CreateOrganization(ctx, db.CreateOrganizationParams{
+		Name:   name,
+		UserID: userID,
+	})
+}
+
+func (r *organizationRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]Organization, error) {
+	rows, err := r.queries.ListOrganizationsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Organization, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, Organization{
+			ID:        row.ID,
+			Name:      row.Name,
+			Role:      row.Role,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (r *organizationRepository) FindRole(ctx context.Context, orgID, userID uuid.UUID) (string, error) {
+	role, err := r.queries.FindOrganizationRole(ctx, db.FindOrganizationRoleParams{
+		OrgID:  orgID,
+		UserID: userID,
+	})
+	if err != nil {
+		if db.IsNoRows(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return role, nil
+}
+
+func (r *organizationRepository) ListMembers(ctx context.Context, orgID uuid.UUID) ([]Member, error) {
+	rows, err := r.queries.ListOrganizationMembers(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Member, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, Member{
+			UserID:   row.UserID,
+			Role:     row.Role,
+			Email:    row.Email,
+			Guest:    row.Guest,
+			JoinedAt: row.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (r *organizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role string) (int64, error) {
+	return r.changeMember(ctx, orgID, userID, role == RoleOwner, func(q *db.Queries) (int64, error) {
+		return q.UpdateOrganizationMemberRole(ctx, db.UpdateOrganizationMemberRoleParams{
+			Role:   role,
+			OrgID:  orgID,
+			UserID: userID,
+		})
+	})
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, orgID, userID uuid.UUID) (int64, error) {
+	return r.changeMember(ctx, orgID, userID, false, func(q *db.Queries) (int64, error) {
+		return q.RemoveOrganizationMember(ctx, db.RemoveOrganizationMemberParams{
+			OrgID:  &orgID,
+			UserID: userID,
+		})
+	})
+}
+
+// changeMember 조직을 잠근 뒤 owner 수를 세고 change를 실행해 동시에 owner를 강등하거나 내보내도 owner가 남는다.
+// keepsOwner면 대상이 owner로 남으므로 세지 않는다
+func (r *organizationRepository) changeMember(
+	ctx context.Context,
+	orgID, userID uuid.UUID,
+	keepsOwner bool,
+	change func(q *db.Queries) (int64, error),
+) (int64, error) {
+	tx, err := r.database.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.queries.WithTx(tx)
+	if err := q.LockOrganizationForOwners(ctx, orgID); err != nil {
+		return 0, err
+	}
+
+	if !keepsOwner {
+		role, err := q.FindOrganizationRole(ctx, db.FindOrganizationRoleParams{
+			OrgID:  orgID,
+			UserID: userID,
+		})
+		if err != nil && !db.IsNoRows(err) {
+			return 0, err
+		}
+		if role == RoleOwner {
+			owners, err := q.CountOrganizationOwners(ctx, orgID)
+			if err != nil {
+				return 0, err
+			}
+			if owners <= 1 {
+				return 0, common.ErrLastOrgOwner
+			}
+		}
+	}
+
+	affected, err := change(q)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+func (r *organizationRepository) Delete(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	return r.queries.DeleteOrganization(ctx, orgID)
+}
+
+type upsertInviteParams struct {
+	orgID     uuid.UUID
+	email     string // 소문자
+	role      string
+	invitedBy uuid.UUID
+	expiresAt time.Time
+}
+
+func (r *organizationRepository) UpsertInvite(ctx context.Context, params upsertInviteParams) error {
+	return r.queries.UpsertOrganizationInvite(ctx, db.UpsertOrganizationInviteParams{
+		OrgID:     params.orgID,
+		Email:     params.email,
+		Role:      params.role,
+		InvitedBy: &params.invitedBy,
+		ExpiresAt: params.expiresAt,
+	})
+}
+
+func (r *organizationRepository) ListInvites(ctx context.Context, orgID uuid.UUID) ([]Invite, error) {
+	rows, err := r.queries.ListOrganizationInvites(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Invite, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, Invite{
+			ID:        row.ID,
+			OrgID:     row.OrgID,
+			Email:     row.Email,
+			Role:      row.Role,
+			InvitedBy: row.InvitedBy,
+			ExpiresAt: row.ExpiresAt,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (r *organizationRepository) DeleteInvite(ctx context.Context, orgID, id uuid.UUID) (int64, error) {
+	return r.queries.DeleteOrganizationInvite(ctx, db.DeleteOrganizationInviteParams{
+		ID:    id,
+		OrgID: orgID,
+	})
+}
+
+func (r *organizationRepository) ListInvitesForUser(ctx context.Context, userID uuid.UUID) ([]Invite, error) {
+	rows, err := r.queries.ListOrganizationInvitesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Invite, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, Invite{
+			ID:        row.ID,
+			OrgID:     row.OrgID,
+			OrgName:   row.OrgName,
+			Role:      row.Role,
+			ExpiresAt: row.ExpiresAt,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (r *organizationRepository) AcceptInvite(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	_, err := r.queries.AcceptOrganizationInvite(ctx, db.AcceptOrganizationInviteParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if db.IsNoRows(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *organizationRepository) DeclineInvite(ctx context.Context, id, userID uuid.UUID) (int64, error) {
+	return r.queries.DeclineOrganizationInvite(ctx, db.DeclineOrganizationInviteParams{
+		ID:     id,
+		UserID: userID,
+	})
+}
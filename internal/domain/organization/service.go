@@ -0,0 +1,268 @@
+package organization
+
+import (
+	"context"
+	"strings"
+	"time"
+	"torchi/internal/domain/common"
+	"torchi/internal/pkg/token"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxNameLength  = 30
+	maxEmailLength = 320
+	// inviteTTL 수락하지 않은 초대가 남아있는 기간. 다시 초대하면 새로 시작
+	inviteTTL = 7 * 24 * time.Hour
+)
+
+type OrganizationService struct {
+	repo OrganizationRepository
+}
+
+func NewOrganizationService(repo OrganizationRepository) *OrganizationService {
+	return &OrganizationService{
+		repo: repo,
+	}
+}
+
+// Create 만든 유저가 owner가 된다
+func (s *OrganizationService) Create(ctx context.Context, name string) (uuid.UUID, error) {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxNameLength {
+		return uuid.Nil, common.ErrInvalidParam
+	}
+
+	return s.repo.Create(ctx, name, userClaim.UserID)
+}
+
+// List 속한 조직 목록
+func (s *OrganizationService) List(ctx context.Context) ([]Organization, error) {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListByUserID(ctx, userClaim.UserID)
+}
+
+// MemberRole 조직 멤버가 아니면 빈 문자열
+func (s *OrganizationService) MemberRole(ctx context.Context, orgID, userID uuid.UUID) (string, error) {
+	return s.repo.FindRole(ctx, orgID, userID)
+}
+
+// ListMembers 조직 멤버라면 누구나 조회 가능
+func (s *OrganizationService) ListMembers(ctx context.Context, orgID uuid.UUID) ([]Member, error) {
+	if _, _, err := s.requireMember(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListMembers(ctx, orgID)
+}
+
+// Invite 이메일로 초대. 그 이메일로 가입한 유저가 수락해야 멤버가 된다.
+// 가입한 이메일인지 드러나지 않도록 유저가 없어도 같은 결과를 반환한다
+func (s *OrganizationService) Invite(ctx context.Context, orgID uuid.UUID, email, role string) error {
+	userID, actor, err := s.requireMember(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" || len(email) > maxEmailLength || !isValidRole(role) {
+		return common.ErrInvalidParam
+	}
+	if !canAssign(actor, role) {
+		return common.ErrOrgPermission
+	}
+
+	return s.repo.UpsertInvite(ctx, upsertInviteParams{
+		orgID:     orgID,
+		email:     email,
+		role:      role,
+		invitedBy: userID,
+		expiresAt: time.Now().Add(inviteTTL),
+	})
+}
+
+// ListInvites 수락하지 않은 초대. 초대한 이메일이 보이므로 owner/admin만 조회 가능
+func (s *OrganizationService) ListInvites(ctx context.Context, orgID uuid.UUID) ([]Invite, error) {
+	if err := s.requireManager(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListInvites(ctx, orgID)
+}
+
+// RevokeInvite owner/admin만 가능
+func (s *OrganizationService) RevokeInvite(ctx context.Context, orgID, inviteID uuid.UUID) error {
+	if err := s.requireManager(ctx, orgID); err != nil {
+		return err
+	}
+
+	affected, err := s.repo.DeleteInvite(ctx, orgID, inviteID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return common.ErrInviteNotFound
+	}
+	return nil
+}
+
+// ListMyInvites 내 이메일로 온 초대
+func (s *OrganizationService) ListMyInvites(ctx context.Context) ([]Invite, error) {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListInvitesForUser(ctx, userClaim.UserID)
+}
+
+// AcceptInvite 내 이메일로 온 초대를 수락. 이미 멤버면 역할은 그대로 둔다
+func (s *OrganizationService) AcceptInvite(ctx context.Context, inviteID uuid.UUID) error {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	accepted, err := s.repo.AcceptInvite(ctx, inviteID, userClaim.UserID)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return common.ErrInviteNotFound
+	}
+	return nil
+}
+
+// DeclineInvite 내 이메일로 온 초대를 거절. 만료된 초대도 지울 수 있다
+func (s *OrganizationService) DeclineInvite(ctx context.Context, inviteID uuid.UUID) error {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	affected, err := s.repo.DeclineInvite(ctx, inviteID, userClaim.UserID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return common.ErrInviteNotFound
+	}
+	return nil
+}
+
+// UpdateMemberRole owner만 역할을 바꿀 수 있다. 마지막 owner는 강등할 수 없다
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, orgID, memberID uuid.UUID, role string) error {
+	_, actor, err := s.requireMember(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	if !isValidRole(role) {
+		return common.ErrInvalidParam
+	}
+	if actor != RoleOwner {
+		return common.ErrOrgPermission
+	}
+
+	target, err := s.repo.FindRole(ctx, orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if target == "" {
+		return common.ErrMemberNotFound
+	}
+
+	affected, err := s.repo.UpdateMemberRole(ctx, orgID, memberID, role)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return common.ErrMemberNotFound
+	}
+	return nil
+}
+
+// RemoveMember 자기 자신이면 조직에서 나가기. 조직 endpoint 알림 수신도 함께 해제된다. 마지막 owner는 나갈 수 없다
+func (s *OrganizationService) RemoveMember(ctx context.Context, orgID, memberID uuid.UUID) error {
+	userID, actor, err := s.requireMember(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	if memberID != userID {
+		target, err := s.repo.FindRole(ctx, orgID, memberID)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			return common.ErrMemberNotFound
+		}
+		if !canRemove(actor, target) {
+			return common.ErrOrgPermission
+		}
+	}
+
+	affected, err := s.repo.RemoveMember(ctx, orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return common.ErrMemberNotFound
+	}
+	return nil
+}
+
+// Delete owner만 가능. 조직 endpoint도 함께 삭제된다
+func (s *OrganizationService) Delete(ctx context.Context, orgID uuid.UUID) error {
+	_, actor, err := s.requireMember(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if actor != RoleOwner {
+		return common.ErrOrgPermission
+	}
+
+	affected, err := s.repo.Delete(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return common.ErrOrgNotFound
+	}
+	return nil
+}
+
+// requireMember 조직 멤버가 아니면 조직의 존재 여부를 드러내지 않도록 ErrOrgNotFound
+func (s *OrganizationService) requireMember(ctx context.Context, orgID uuid.UUID) (uuid.UUID, string, error) {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	role, err := s.repo.FindRole(ctx, orgID, userClaim.UserID)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	if role == "" {
+		return uuid.Nil, "", common.ErrOrgNotFound
+	}
+	return userClaim.UserID, role, nil
+}
+
+// requireManager 조직 owner/admin이 아니면 ErrOrgPermission
+func (s *OrganizationService) requireManager(ctx context.Context, orgID uuid.UUID) error {
+	_, actor, err := s.requireMember(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if !CanManageEndpoints(actor) {
+		return common.ErrOrgPermission
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 	"torchi/internal/domain/common"
 	"torchi/internal/domain/endpoint"
 	"torchi/internal/domain/notifications"
@@ -12,19 +13,27 @@ import (
 	"torchi/internal/domain/token"
 	"torchi/internal/pkg/config"
 	"torchi/internal/pkg/log"
+	authtoken "torchi/internal/pkg/token"
 
 	"github.com/SherClockHolmes/webpush-go"
 	"github.com/google/uuid"
 )
 
+// 구독(push 토큰) 등록, 해제 SSE 이벤트
+const (
+	EventDeviceAdded   = "device_added"
+	EventDeviceRemoved = "device_removed"
+)
+
 type PushService struct {
-	vapidKey config.Vapid
-	log      *log.Logger
+	vapidKey     config.Vapid
+	stepUpMaxAge time.Duration
+	log          *log.Logger
 
-	tokenService    *token.TokenService
-	endpointService *endpoint.EndpointService
-	notiService     *notifications.NotiService
-	sseBroker       *sse.Broker
+	tokenService *token.TokenService
+	notiService  *notifications.NotiService
+	sseBroker    *sse.Broker
+	feedBroker   *sse.EndpointBroker
 
 	waitMap *WaitMap
 }
@@ -34,19 +43,20 @@ func NewPushService(
 	log *log.Logger,
 
 	tokenService *token.TokenService,
-	endpointService *endpoint.EndpointService,
 	notiService *notifications.NotiService,
 	sseBroker *sse.Broker,
+	feedBroker *sse.EndpointBroker,
 	waitMap *WaitMap,
 ) *PushService {
 	return &PushService{
-		log:             log,
-		vapidKey:        env.Vapid,
-		tokenService:    tokenService,
-		endpointService: endpointService,
-		notiService:     notiService,
-		sseBroker:       sseBroker,
-		waitMap:         waitMap,
+		log:          log,
+		vapidKey:     env.Vapid,
+		stepUpMaxAge: env.Auth.StepUpMaxAge,
+		tokenService: tokenService,
+		notiService:  notiService,
+		sseBroker:    sseBroker,
+		feedBroker:   feedBroker,
+		waitMap:      waitMap,
 	}
 }
 
@@ -61,6 +71,7 @@ func (s *PushService) Subscribe(ctx context.Context, sub Subscription) error {
 		return err
 	}
 
+	s.publishDevice(sub.UserID, EventDeviceAdded, sub.Endpoint)
 	return nil
 }
 
@@ -74,9 +85,18 @@ func (s *PushService) Unsubscribe(ctx context.Context, sub Subscription) error {
 		return err
 	}
 
+	s.publishDevice(sub.UserID, EventDeviceRemoved, sub.Endpoint)
 	return nil
 }
 
+// publishDevice 설정 화면의 기기 목록을 다른 탭, 기기에서도 맞춘다
+func (s *PushService) publishDevice(userID uuid.UUID, event, pushEndpoint string) {
+	s.sseBroker.Publish(userID, sse.SSEEvent{
+		Event: event,
+		Data:  map[string]any{"endpoint": pushEndpoint},
+	})
+}
+
 func (s *PushService) PushByEndpoint(ctx context.Context, endpoint string, message string) error {
 	token, err := s.tokenService.FindByEndpoint(ctx, endpoint)
 	if err != nil {
@@ -86,7 +106,7 @@ func (s *PushService) PushByEndpoint(ctx context.Context, endpoint string, messa
 		return common.ErrEndpointNotFound
 	}
 
-	if err := s.pushNotification(*token, "TEST!", message); err != nil {
+	if err := s.pushNotification(*token, pushMessage{title: "TEST!", body: message}); err != nil {
 		return err
 	}
 
@@ -94,53 +114,134 @@ func (s *PushService) PushByEndpoint(ctx context.Context, endpoint string, messa
 
 }
 
-// Push notification using endpoint token
-func (s *PushService) Push(ctx context.Context, endpointToken string, message string) (uint64, error) {
-
-	endpoint, err := s.endpointService.FindByToken(ctx, endpointToken)
+// Push endpoint 정책 확인은 호출하는 쪽(ApiHandler)에서 한다.
+// 알림은 endpoint 멤버마다 만들어지고, 음소거하지 않은 멤버의 기기로 보낸다.
+func (s *PushService) Push(ctx context.Context, endpoint *endpoint.Endpoint, message string) (uint64, error) {
+	req := notifications.ReqRegister{
+		EndpointID: endpoint.ID,
+		Body:       message,
+	}
+	withSenderKey(&req, endpoint)
 
+	notis, err := s.notiService.Register(ctx, req)
 	if err != nil {
 		return 0, err
 	}
-	if endpoint == nil {
-		return 0, common.ErrEndpointNotFound
+
+	return s.fanOut(ctx, endpoint, notis, message)
+}
+
+// fanOut 멤버별 알림을 SSE와 web push로 전달. 한 멤버의 실패가 다른 멤버 전달을 막지 않도록
+// 모두 시도하고, 보낸 멤버 모두에게 실패했을 때만 에러를 반환한다. 일부 실패는 로그만 남긴다.
+func (s *PushService) fanOut(ctx context.Context, endpoint *endpoint.Endpoint, notis []notifications.Noti, message string) (uint64, error) {
+	var count uint64
+	var result deliveryResult
+
+	if len(notis) > 0 {
+		s.feedBroker.Publish(endpoint.ID, notifications.MessageFeedEvent(notis[0]).SSEEvent())
 	}
-	userID := endpoint.UserID
 
-	tokens, err := s.tokenService.FindByUserID(ctx, userID)
-	if err != nil {
-		return 0, err
+	unread := s.unreadCounts(ctx, notis)
+
+	for _, noti := range notis {
+		counts, hasCounts := unread[noti.UserID]
+		s.publishSSE(noti.UserID, noti, endpoint.Name, counts, hasCounts)
+
+		if noti.IsMute() {
+			continue
+		}
+
+		msg := newPushMessage(endpoint, message)
+		if hasCounts {
+			msg.unread = &counts.Total
+		}
+
+		sent, err := s.deliver(ctx, noti, msg)
+		count += sent
+		result.add(err)
+		if err != nil {
+			s.log.Error("push to member", "user_id", noti.UserID, "err", err)
+		}
 	}
 
-	noti, err := s.notiService.Register(ctx, notifications.ReqRegister{
-		EndpointID:         endpoint.ID,
-		UserID:             userID,
-		Body:               message,
-		NotificationEnable: endpoint.NotificationEnable,
-	})
+	return count, result.err()
+}
 
-	s.publishSSE(userID, noti, endpoint.Name)
+// deliveryResult 멤버별 전달 결과
+type deliveryResult struct {
+	attempted int
+	failed    int
+	firstErr  error
+}
+
+func (r *deliveryResult) add(err error) {
+	r.attempted++
+	if err == nil {
+		return
+	}
+	r.failed++
+	if r.firstErr == nil {
+		r.firstErr = err
+	}
+}
+
+// err 보낸 멤버 모두에게 실패했으면 첫 에러. 보낼 멤버가 없었으면 nil
+func (r *deliveryResult) err() error {
+	if r.attempted > 0 && r.failed == r.attempted {
+		return r.firstErr
+	}
+	return nil
+}
+
+// unreadCounts 배지 갱신용 멤버별 읽지 않은 수. 집계에 실패해도 발송은 계속한다
+func (s *PushService) unreadCounts(ctx context.Context, notis []notifications.Noti) map[uuid.UUID]notifications.UnreadCounts {
+	userIDs := make([]uuid.UUID, 0, len(notis))
+	for _, noti := range notis {
+		userIDs = append(userIDs, noti.UserID)
+	}
+
+	counts, err := s.notiService.UnreadCountsOf(ctx, userIDs)
+	if err != nil {
+		s.log.Error("count unread", "err", err)
+		return nil
+	}
+	return counts
+}
 
-	if !endpoint.NotificationEnable {
+// deliver 멤버 한 명의 기기들로 push 후 sent 처리
+func (s *PushService) deliver(ctx context.Context, noti notifications.Noti, msg pushMessage) (uint64, error) {
+	tokens, err := s.tokenService.FindByUserID(ctx, noti.UserID)
+	if err != nil {
 		return 0, err
 	}
 
 	var count uint64
-
 	for _, token := range tokens {
-		if err := s.pushNotification(token, endpoint.Name, message); err != nil {
+		if err := s.pushNotification(token, msg); err != nil {
 			// TODO: 에러 처리 개선 필요.
+			if updateErr := s.notiService.UpdateStatusFailed(ctx, noti.ID); updateErr != nil {
+				s.log.Error("update status failed", "id", noti.ID, "err", updateErr)
+			}
 			return count, err
 		}
 		count = count + 1
 	}
-	if err = s.notiService.UpdateStatusSent(ctx, noti.ID); err != nil {
+	if err := s.notiService.UpdateStatusSent(ctx, noti.ID); err != nil {
 		return count, err
 	}
 
 	return count, nil
 }
 
+// withSenderKey sender key로 보낸 요청이면 알림에 어떤 키였는지 기록
+func withSenderKey(req *notifications.ReqRegister, e *endpoint.Endpoint) {
+	if e.SenderKey == nil {
+		return
+	}
+	req.SenderKeyID = &e.SenderKey.ID
+	req.SenderKeyLabel = &e.SenderKey.Label
+}
+
 type DemoPushParams struct {
 	Endpoint string
 	Auth     string
@@ -153,14 +254,47 @@ func (s *PushService) DemoPush(ctx context.Context, req DemoPushParams, message
 		P256dh:   req.P256dh,
 		Auth:     req.Auth,
 		EndPoint: req.Endpoint,
-	}, "Demo", message); err != nil {
+	}, pushMessage{title: "Demo", body: message}); err != nil {
 		return nil, err
 	}
 
 	return nil, nil
 }
 
-func (s *PushService) pushNotification(token token.Token, title, body string) error {
+const defaultPushTTL = 300
+
+type pushMessage struct {
+	title   string
+	body    string
+	icon    string // 비어있으면 service worker 기본 아이콘
+	url     string // 알림 클릭 시 이동할 경로. 비어있으면 service worker 기본값
+	ttl     int
+	urgency webpush.Urgency
+	unread  *int64 // 앱 배지에 표시할 읽지 않은 수. nil이면 배지를 건드리지 않는다
+}
+
+// newPushMessage endpoint에 설정된 아이콘, 클릭 URL, TTL, urgency를 적용
+func newPushMessage(e *endpoint.Endpoint, body string) pushMessage {
+	msg := pushMessage{
+		title: e.Name,
+		body:  body,
+	}
+	if e.Icon != nil {
+		msg.icon = *e.Icon
+	}
+	if e.DefaultClickURL != nil {
+		msg.url = *e.DefaultClickURL
+	}
+	if e.DefaultTTL != nil {
+		msg.ttl = *e.DefaultTTL
+	}
+	if e.DefaultUrgency != nil {
+		msg.urgency = webpush.Urgency(*e.DefaultUrgency)
+	}
+	return msg
+}
+
+func (s *PushService) pushNotification(token token.Token, msg pushMessage) error {
 
 	subs := &webpush.Subscription{
 		Endpoint: token.EndPoint,
@@ -173,17 +307,31 @@ func (s *PushService) pushNotification(token token.Token, title, body string) er
 	options := &webpush.Options{
 		VAPIDPublicKey:  s.vapidKey.PublicKey,
 		VAPIDPrivateKey: s.vapidKey.PrivateKey,
-		TTL:             300,
+		TTL:             defaultPushTTL,
+		Urgency:         msg.urgency,
 		Subscriber:      "jtpark1957@gmail.com",
 	}
+	if msg.ttl > 0 {
+		options.TTL = msg.ttl
+	}
 	payload := map[string]interface{}{
-		"title": title,
-		"body":  body,
+		"title": msg.title,
+		"body":  msg.body,
 		"data": map[string]string{
 			"url":       "/",
 			"timestamp": fmt.Sprintf("%d", 1234567890),
 		},
 	}
+	// service worker는 최상위 icon, url을 읽는다
+	if msg.icon != "" {
+		payload["icon"] = msg.icon
+	}
+	if msg.url != "" {
+		payload["url"] = msg.url
+	}
+	if msg.unread != nil {
+		payload["unread"] = *msg.unread
+	}
 
 	payloadBytes, _ := json.Marshal(payload)
 
@@ -198,63 +346,53 @@ func (s *PushService) pushNotification(token token.Token, title, body string) er
 
 }
 
-func (s *PushService) PushAndWait(ctx context.Context, endpointToken string, message string, actions []string) (string, error) {
-	endpoint, err := s.endpointService.FindByToken(ctx, endpointToken)
-	if err != nil {
-		return "", err
-	}
-	if endpoint == nil {
-		return "", common.ErrEndpointNotFound
-	}
-
-	userID := endpoint.UserID
+type AskResult struct {
+	Reaction string
+	Comment  string
+}
 
-	tokens, err := s.tokenService.FindByUserID(ctx, userID)
-	if err != nil {
-		return "", err
+// PushAndWait sensitive가 true면 응답 시 step-up 재인증을 요구한다. (React 참고)
+// 멤버 중 한 명이라도 응답하면 대기가 끝난다.
+func (s *PushService) PushAndWait(ctx context.Context, endpoint *endpoint.Endpoint, message string, actions []string, sensitive bool) (AskResult, error) {
+	req := notifications.ReqRegister{
+		EndpointID: endpoint.ID,
+		Body:       message,
+		Actions:    actions,
+		Sensitive:  sensitive,
 	}
+	withSenderKey(&req, endpoint)
 
-	noti, err := s.notiService.Register(ctx, notifications.ReqRegister{
-		EndpointID:         endpoint.ID,
-		UserID:             userID,
-		Body:               message,
-		Actions:            actions,
-		NotificationEnable: endpoint.NotificationEnable,
-	})
+	notis, err := s.notiService.Register(ctx, req)
 	if err != nil {
-		return "", err
+		return AskResult{}, err
 	}
+	if len(notis) == 0 {
+		return AskResult{}, common.ErrEndpointNotFound
+	}
+	messageID := notis[0].MessageID
 
-	s.publishSSE(userID, noti, endpoint.Name)
-
-	if endpoint.NotificationEnable {
-		for _, token := range tokens {
-			if err := s.pushNotification(token, endpoint.Name, message); err != nil {
-				return "", err
-			}
-		}
-		if err = s.notiService.UpdateStatusSent(ctx, noti.ID); err != nil {
-			return "", err
-		}
+	// 일부 멤버에게만 실패했으면 받은 멤버의 응답을 기다린다
+	if _, err := s.fanOut(ctx, endpoint, notis, message); err != nil {
+		return AskResult{}, err
 	}
 
 	// 채널 등록 후 대기
 	ch := make(chan WaitResult, 1)
-	s.waitMap.Set(noti.ID.String(), ch)
+	s.waitMap.Set(messageID.String(), ch)
 	reacted := false
 	cancelled := false
 
 	defer func() {
-		s.waitMap.Delete(noti.ID.String())
+		s.waitMap.Delete(messageID.String())
 		if reacted {
 			return
 		}
 		if cancelled {
-			if err := s.notiService.UpdateStatusCancelled(context.Background(), noti.ID); err != nil {
+			if err := s.notiService.UpdateStatusCancelled(context.Background(), messageID); err != nil {
 				s.log.Error("update status cancelled", "err", err)
 			}
 		} else {
-			if err := s.notiService.UpdateStatusTimeout(context.Background(), noti.ID); err != nil {
+			if err := s.notiService.UpdateStatusTimeout(context.Background(), messageID); err != nil {
 				s.log.Error("update status timeout", "err", err)
 			}
 		}
@@ -262,58 +400,132 @@ func (s *PushService) PushAndWait(ctx context.Context, endpointToken string, mes
 		if cancelled {
 			event = "cancelled"
 		}
-		s.sseBroker.Publish(userID, sse.SSEEvent{
-			Event: event,
-			Data:  map[string]any{"id": noti.ID},
-		})
+		for _, noti := range notis {
+			s.sseBroker.Publish(noti.UserID, sse.SSEEvent{
+				Event:      event,
+				Data:       map[string]any{"id": noti.ID},
+				EndpointID: noti.EndpointID,
+			})
+		}
 	}()
 
 	select {
 	case result := <-ch:
 		reacted = true
 		if result.Deleted {
-			return "", common.ErrNotificationDeleted
+			return AskResult{}, common.ErrNotificationDeleted
 		}
-		return result.Reaction, nil
+		// 응답하지 않은 멤버의 다른 탭, 기기에서도 응답 버튼을 닫는다
+		for _, noti := range notis {
+			s.sseBroker.Publish(noti.UserID, notifications.ReactedEvent(noti, result.Reaction, result.Comment))
+		}
+		return AskResult{Reaction: result.Reaction, Comment: result.Comment}, nil
 	case <-ctx.Done():
 		if errors.Is(ctx.Err(), context.Canceled) {
 			cancelled = true
 			s.log.Debug("PushAndWait cancelled by caller")
-			return "", context.Canceled
+			return AskResult{}, context.Canceled
 		}
 		s.log.Debug("PushAndWait timeout")
-		return "", context.DeadlineExceeded
+		return AskResult{}, context.DeadlineExceeded
 	}
 }
 
-func (s *PushService) React(ctx context.Context, notiID uuid.UUID, reaction string) error {
-	if err := s.notiService.SaveReaction(ctx, notiID, reaction); err != nil {
+type ReactParams struct {
+	NotiID       uuid.UUID
+	Reaction     string
+	Comment      string
+	PushEndpoint string // 응답한 기기의 push subscription endpoint (선택)
+	IP           string
+	UserAgent    string
+}
+
+// React ask에 대한 응답 저장.
+// sensitive ask는 알림 소유자가 stepUpMaxAge 이내에 다시 로그인한 세션에서만 응답할 수 있다.
+func (s *PushService) React(ctx context.Context, params ReactParams) error {
+	noti, err := s.notiService.FindByID(ctx, params.NotiID)
+	if err != nil {
 		return err
 	}
+	if noti == nil || noti.IsDeleted {
+		return common.ErrNotificationNotFound
+	}
+
+	claims, authErr := authtoken.UserFromContext(ctx)
+	isOwner := authErr == nil && claims.UserID == noti.UserID
+
+	if noti.Sensitive && (!isOwner || !claims.AuthenticatedWithin(s.stepUpMaxAge)) {
+		return common.ErrStepUpRequired
+	}
+
+	req := notifications.ReqSaveReaction{
+		ID:        params.NotiID,
+		Reaction:  params.Reaction,
+		Comment:   params.Comment,
+		IP:        params.IP,
+		UserAgent: params.UserAgent,
+	}
+	if isOwner {
+		req.UserID = &claims.UserID
+
+		auth := claims.AuthInfo()
+		req.AuthMethod = &auth.Method
+		if !auth.Time.IsZero() {
+			req.AuthAt = &auth.Time
+		}
+
+		// 본인 기기로 등록된 구독만 기록
+		if params.PushEndpoint != "" {
+			device, err := s.tokenService.FindByEndpoint(ctx, params.PushEndpoint)
+			if err != nil {
+				return err
+			}
+			if device != nil && device.UserID == claims.UserID {
+				req.PushTokenID = &device.ID
+			}
+		}
+	}
 
-	if ch, ok := s.waitMap.Get(notiID.String()); ok {
-		ch <- WaitResult{Reaction: reaction}
+	saved, err := s.notiService.SaveReaction(ctx, req)
+	if err != nil {
+		return err
+	}
+	if saved != nil && noti.EndpointID != nil {
+		s.feedBroker.Publish(*noti.EndpointID, notifications.ReactionFeedEvent(*noti, *saved).SSEEvent())
 	}
 
+	// 대기는 발송(MessageID) 단위. 어느 멤버가 응답해도 끝난다
+	s.waitMap.Deliver(noti.MessageID.String(), WaitResult{Reaction: params.Reaction, Comment: params.Comment})
+
 	return nil
 }
 
+// publishSSE hasUnread면 갱신된 읽지 않은 수를 unread 필드로 함께 보낸다
+func (s *PushService) publishSSE(userID uuid.UUID, noti notifications.Noti, endpointName string, unread notifications.UnreadCounts, hasUnread bool) {
+	data := map[string]interface{}{
+		"id":            noti.ID,
+		"endpoint_name": endpointName,
+		"endpoint_id":   noti.EndpointID,
+		"body":          noti.Body,
+		"is_read":       false,
+		"created_at":    noti.CreatedAt,
+		"mute":          noti.IsMute(),
+		"actions":       noti.Actions,
+		"reaction":      noti.Reaction,
+		"reaction_at":   noti.ReactionAt,
+		"status":        string(noti.Status),
+		"sensitive":     noti.Sensitive,
+
+		"sender_key_id":    noti.SenderKeyID,
+		"sender_key_label": noti.SenderKeyLabel,
+	}
+	if hasUnread {
+		data["unread"] = unread.EventData()
+	}
 
-func (s *PushService) publishSSE(userID uuid.UUID, noti notifications.Noti, endpointName string) {
 	s.sseBroker.Publish(userID, sse.SSEEvent{
-		Event: "notification",
-		Data: map[string]interface{}{
-			"id":            noti.ID,
-			"endpoint_name": endpointName,
-			"endpoint_id":   noti.EndpointID,
-			"body":          noti.Body,
-			"is_read":       false,
-			"created_at":    noti.CreatedAt,
-			"mute":          noti.IsMute(),
-			"actions":       noti.Actions,
-			"reaction":      noti.Reaction,
-			"reaction_at":   noti.ReactionAt,
-			"status":        string(noti.Status),
-		},
+		Event:      "notification",
+		Data:       data,
+		EndpointID: noti.EndpointID,
 	})
 }
@@ -0,0 +1,35 @@
+package push
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDeliveryResult(t *testing.T) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	cases := []struct {
+		name string
+		errs []error
+		want error
+	}{
+		{"no members", nil, nil},
+		{"all delivered", []error{nil, nil}, nil},
+		{"partial failure", []error{errA, nil, errB}, nil},
+		{"all failed", []error{errA, errB}, errA},
+		{"single failed", []error{errB}, errB},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var r deliveryResult
+			for _, err := range c.errs {
+				r.add(err)
+			}
+			if got := r.err(); got != c.want {
+				t.Errorf("expected: %v, got: %v", c.want, got)
+			}
+		})
+	}
+}
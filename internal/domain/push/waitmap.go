@@ -4,6 +4,7 @@ import "sync"
 
 type WaitResult struct {
 	Reaction string
+	Comment  string
 	Deleted  bool
 }
 
@@ -34,3 +35,18 @@ func (w *WaitMap) Delete(id string) {
 	defer w.mu.Unlock()
 	delete(w.m, id)
 }
+
+// Deliver 대기 중인 발송에 결과를 넘긴다. 대기가 없거나 이미 다른 결과가 들어가 있으면 버린다.
+// 멤버가 동시에 응답하거나 삭제와 겹쳐도 보내는 쪽이 막히지 않도록 기다리지 않는다
+func (w *WaitMap) Deliver(id string, r WaitResult) bool {
+	ch, ok := w.Get(id)
+	if !ok {
+		return false
+	}
+	select {
+	case ch <- r:
+		return true
+	default:
+		return false
+	}
+}
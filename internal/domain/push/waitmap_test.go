@@ -0,0 +1,37 @@
+package push
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWaitMap_DeliverDoesNotBlock(t *testing.T) {
+	w := NewWaitMap()
+	ch := make(chan WaitResult, 1)
+	w.Set("msg", ch)
+
+	if !w.Deliver("msg", WaitResult{Reaction: "승인"}) {
+		t.Fatal("first result should be delivered")
+	}
+	if got := <-ch; got.Reaction != "승인" {
+		t.Errorf("expected 승인, got: %+v", got)
+	}
+
+	// 대기하던 쪽은 이미 돌아갔고 map에서 지우기 전에 두 명이 더 보낸다
+	done := make(chan struct{})
+	go func() {
+		w.Deliver("msg", WaitResult{Reaction: "거절"})
+		w.Deliver("msg", WaitResult{Deleted: true})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Deliver blocked after the waiter returned")
+	}
+
+	w.Delete("msg")
+	if w.Deliver("msg", WaitResult{}) {
+		t.Error("deleted wait should not accept results")
+	}
+}
@@ -0,0 +1,39 @@
+package retention
+
+import (
+	"time"
+	"torchi/internal/domain/common"
+)
+
+const (
+	// MaxKeepCount 보관 개수 상한
+	MaxKeepCount = 100000
+	// MaxKeepDays 서버 최대 보관 일수가 없을 때의 보관 일수 상한
+	MaxKeepDays = 36500
+)
+
+// Policy 비어있는 항목은 제한 없음. 서버 최대 보관 일수가 있으면 정책과 관계없이 항상 적용되고,
+// 유저와 endpoint 정책이 모두 있으면 더 짧은 쪽이 적용된다
+type Policy struct {
+	KeepDays  *int
+	KeepCount *int       // 유저 정책은 전체, endpoint 정책은 멤버별 개수
+	UpdatedAt *time.Time // 설정한 적 없으면 nil
+}
+
+func (p Policy) isEmpty() bool {
+	return p.KeepDays == nil && p.KeepCount == nil
+}
+
+// validate maxDays가 0이면 서버 최대 보관 일수 없음
+func (p Policy) validate(maxDays int) error {
+	if maxDays == 0 {
+		maxDays = MaxKeepDays
+	}
+	if p.KeepDays != nil && (*p.KeepDays < 1 || *p.KeepDays > maxDays) {
+		return common.ErrInvalidParam
+	}
+	if p.KeepCount != nil && (*p.KeepCount < 1 || *p.KeepCount > MaxKeepCount) {
+		return common.ErrInvalidParam
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package retention
+
+import (
+	"errors"
+	"testing"
+	"torchi/internal/domain/common"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	n := func(v int) *int { return &v }
+
+	cases := []struct {
+		name   string
+		policy Policy
+		want   error
+	}{
+		{"empty", Policy{}, nil},
+		{"days", Policy{KeepDays: n(30)}, nil},
+		{"server max", Policy{KeepDays: n(365)}, nil},
+		{"count", Policy{KeepCount: n(MaxKeepCount)}, nil},
+		{"over server max", Policy{KeepDays: n(366)}, common.ErrInvalidParam},
+		{"zero days", Policy{KeepDays: n(0)}, common.ErrInvalidParam},
+		{"zero count", Policy{KeepCount: n(0)}, common.ErrInvalidParam},
+		{"too many", Policy{KeepCount: n(MaxKeepCount + 1)}, common.ErrInvalidParam},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.policy.validate(365); !errors.Is(err, c.want) {
+				t.Errorf("expected: %v, got: %v", c.want, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_ValidateNoServerMax(t *testing.T) {
+	n := func(v int) *int { return &v }
+
+	if err := (Policy{KeepDays: n(3650)}).validate(0); err != nil {
+		t.Errorf("expected ok without server max, got: %v", err)
+	}
+	if err := (Policy{KeepDays: n(MaxKeepDays + 1)}).validate(0); !errors.Is(err, common.ErrInvalidParam) {
+		t.Errorf("expected: %v, got: %v", common.ErrInvalidParam, err)
+	}
+}
@@ -0,0 +1,139 @@
+package retention
+
+import (
+	"context"
+	"time"
+	"torchi/internal/domain/notifications"
+	"torchi/internal/pkg/config"
+	"torchi/internal/pkg/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/fx"
+)
+
+// batchPause batch 사이에 쉬는 시간. 다른 쓰기 요청이 잠금을 기다리지 않도록 양보한다
+const batchPause = 100 * time.Millisecond
+
+const (
+	purgeReasonAge   = "age"
+	purgeReasonCount = "count"
+)
+
+type purgeJob struct {
+	repo      RetentionRepository
+	log       *log.Logger
+	maxDays   int
+	batchSize int
+	purged    *prometheus.CounterVec
+}
+
+// RegisterPurgeJob 보관 기간, 보관 개수를 넘은 알림을 주기적으로 영구 삭제
+func RegisterPurgeJob(lc fx.Lifecycle, env config.Env, repo RetentionRepository, log *log.Logger) {
+	job := &purgeJob{
+		repo:      repo,
+		log:       log,
+		maxDays:   env.Retention.MaxDays,
+		batchSize: env.Retention.PurgeBatchSize,
+		purged: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "notifications_purged_total",
+			Help: "Total number of notifications deleted by retention policies.",
+		}, []string{"reason"}),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				ticker := time.NewTicker(env.Retention.PurgeInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						job.run(ctx)
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+func (j *purgeJob) run(ctx context.Context) {
+	steps := []struct {
+		reason string
+		purge  func(context.Context) (int64, error)
+	}{
+		{purgeReasonAge, func(ctx context.Context) (int64, error) {
+			return j.repo.PurgeExpired(ctx, j.maxDays, j.batchSize)
+		}},
+		{purgeReasonCount, func(ctx context.Context) (int64, error) {
+			return j.repo.PurgeExcess(ctx, j.batchSize)
+		}},
+	}
+
+	for _, step := range steps {
+		counter := j.purged.WithLabelValues(step.reason)
+		total, err := purgeInBatches(ctx, j.batchSize, batchPause, step.purge, func(n int64) {
+			counter.Add(float64(n))
+		})
+		if err != nil {
+			j.log.Error("notification purge failed", "reason", step.reason, "purged", total, "err", err)
+			continue
+		}
+		if total > 0 {
+			j.log.Info("notifications purged", "reason", step.reason, "purged", total)
+		}
+	}
+
+	// 알림이 아니라 삭제 기록이므로 지표에 세지 않는다
+	total, err := purgeInBatches(ctx, j.batchSize, batchPause, func(ctx context.Context) (int64, error) {
+		return j.repo.PurgeTombstones(ctx, time.Now().Add(-notifications.TombstoneTTL), j.batchSize)
+	}, func(int64) {})
+	if err != nil {
+		j.log.Error("notification tombstone purge failed", "purged", total, "err", err)
+	} else if total > 0 {
+		j.log.Info("notification tombstones purged", "purged", total)
+	}
+}
+
+// purgeInBatches batch가 꽉 차지 않을 때까지 반복. 중간에 실패하면 그때까지 지운 수와 에러를 반환
+func purgeInBatches(
+	ctx context.Context,
+	batchSize int,
+	pause time.Duration,
+	purge func(context.Context) (int64, error),
+	onBatch func(int64),
+) (int64, error) {
+	var total int64
+	for {
+		n, err := purge(ctx)
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n > 0 {
+			onBatch(n)
+		}
+		if n < int64(batchSize) {
+			return total, nil
+		}
+
+		timer := time.NewTimer(pause)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPurgeInBatches(t *testing.T) {
+	batches := []int64{10, 10, 3}
+	calls := 0
+	var reported int64
+
+	total, err := purgeInBatches(context.Background(), 10, 0, func(context.Context) (int64, error) {
+		n := batches[calls]
+		calls++
+		return n, nil
+	}, func(n int64) { reported += n })
+
+	if err != nil || total != 23 || reported != 23 {
+		t.Errorf("expected: 23, got: total=%d reported=%d err=%v", total, reported, err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 batches, got: %d", calls)
+	}
+}
+
+func TestPurgeInBatches_Error(t *testing.T) {
+	errDB := errors.New("db")
+	calls := 0
+
+	total, err := purgeInBatches(context.Background(), 10, 0, func(context.Context) (int64, error) {
+		calls++
+		if calls == 2 {
+			return 0, errDB
+		}
+		return 10, nil
+	}, func(int64) {})
+
+	if !errors.Is(err, errDB) || total != 10 {
+		t.Errorf("expected: 10, %v, got: %d, %v", errDB, total, err)
+	}
+}
+
+func TestPurgeInBatches_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	total, err := purgeInBatches(ctx, 10, 0, func(context.Context) (int64, error) {
+		return 10, nil
+	}, func(int64) {})
+
+	if !errors.Is(err, context.Canceled) || total != 10 {
+		t.Errorf("expected: 10, %v, got: %d, %v", context.Canceled, total, err)
+	}
+}
@@ -0,0 +1,9 @@
+package retention
+
+import "go.uber.org/fx"
+
+var Module = fx.Options(
+	fx.Provide(NewRetentionService),
+	fx.Provide(NewRetentionRepository),
+	fx.Invoke(RegisterPurgeJob),
+)
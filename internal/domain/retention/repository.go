@@ -0,0 +1,122 @@
+package retention
+
+import (
+	"context"
+	"time"
+	db "torchi/internal/infrastructure/db/postgresql"
+
+	"github.com/google/uuid"
+)
+
+type RetentionRepository interface {
+	// 설정한 적 없으면 nil
+	FindByUser(ctx context.Context, userID uuid.UUID) (*Policy, error)
+	FindByEndpoint(ctx context.Context, endpointID uuid.UUID) (*Policy, error)
+	SaveUser(ctx context.Context, userID uuid.UUID, policy Policy) error
+	SaveEndpoint(ctx context.Context, endpointID uuid.UUID, policy Policy) error
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
+	DeleteEndpoint(ctx context.Context, endpointID uuid.UUID) error
+	// 한 번에 batchSize개까지 지우고 지운 수를 반환
+	PurgeExpired(ctx context.Context, maxDays, batchSize int) (int64, error)
+	PurgeExcess(ctx context.Context, batchSize int) (int64, error)
+	// 변경 피드용 삭제 기록
+	PurgeTombstones(ctx context.Context, deletedBefore time.Time, batchSize int) (int64, error)
+}
+
+type retentionRepository struct {
+	queries *db.Queries
+}
+
+func NewRetentionRepository(queries *db.Queries) RetentionRepository {
+	return &retentionRepository{
+		queries: queries,
+	}
+}
+
+func (r *retentionRepository) FindByUser(ctx context.Context, userID uuid.UUID) (*Policy, error) {
+	row, err := r.queries.FindUserRetention(ctx, &userID)
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toPolicy(row), nil
+}
+
+func (r *retentionRepository) FindByEndpoint(ctx context.Context, endpointID uuid.UUID) (*Policy, error) {
+	row, err := r.queries.FindEndpointRetention(ctx, &endpointID)
+	if err != nil {
+		if db.IsNoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toPolicy(row), nil
+}
+
+func (r *retentionRepository) SaveUser(ctx context.Context, userID uuid.UUID, policy Policy) error {
+	return r.queries.UpsertUserRetention(ctx, db.UpsertUserRetentionParams{
+		UserID:    &userID,
+		KeepDays:  toInt32Ptr(policy.KeepDays),
+		KeepCount: toInt32Ptr(policy.KeepCount),
+	})
+}
+
+func (r *retentionRepository) SaveEndpoint(ctx context.Context, endpointID uuid.UUID, policy Policy) error {
+	return r.queries.UpsertEndpointRetention(ctx, db.UpsertEndpointRetentionParams{
+		EndpointID: &endpointID,
+		KeepDays:   toInt32Ptr(policy.KeepDays),
+		KeepCount:  toInt32Ptr(policy.KeepCount),
+	})
+}
+
+func (r *retentionRepository) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	return r.queries.DeleteUserRetention(ctx, &userID)
+}
+
+func (r *retentionRepository) DeleteEndpoint(ctx context.Context, endpointID uuid.UUID) error {
+	return r.queries.DeleteEndpointRetention(ctx, &endpointID)
+}
+
+func (r *retentionRepository) PurgeExpired(ctx context.Context, maxDays, batchSize int) (int64, error) {
+	return r.queries.PurgeExpiredNotifications(ctx, db.PurgeExpiredNotificationsParams{
+		MaxDays:   int32(maxDays),
+		BatchSize: int32(batchSize),
+	})
+}
+
+func (r *retentionRepository) PurgeExcess(ctx context.Context, batchSize int) (int64, error) {
+	return r.queries.PurgeExcessNotifications(ctx, int32(batchSize))
+}
+
+func (r *retentionRepository) PurgeTombstones(ctx context.Context, deletedBefore time.Time, batchSize int) (int64, error) {
+	return r.queries.PurgeNotificationTombstones(ctx, db.PurgeNotificationTombstonesParams{
+		DeletedBefore: deletedBefore,
+		BatchSize:     int32(batchSize),
+	})
+}
+
+func toPolicy(row db.RetentionPolicy) *Policy {
+	return &Policy{
+		KeepDays:  int32Ptr(row.KeepDays),
+		KeepCount: int32Ptr(row.KeepCount),
+		UpdatedAt: &row.UpdatedAt,
+	}
+}
+
+func int32Ptr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+func toInt32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v)
+	return &i
+}
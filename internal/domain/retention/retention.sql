@@ -0,0 +1,107 @@
+-- name: FindUserRetention :one
+SELECT * FROM retention_policies
+WHERE user_id = $1;
+
+-- name: FindEndpointRetention :one
+SELECT * FROM retention_policies
+WHERE endpoint_id = $1;
+
+-- name: UpsertUserRetention :exec
+INSERT INTO retention_policies (user_id, keep_days, keep_count)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET keep_days = EXCLUDED.keep_days,
+    keep_count = EXCLUDED.keep_count,
+    updated_at = now();
+
+-- name: UpsertEndpointRetention :exec
+INSERT INTO retention_policies (endpoint_id, keep_days, keep_count)
+VALUES ($1, $2, $3)
+ON CONFLICT (endpoint_id) DO UPDATE
+SET keep_days = EXCLUDED.keep_days,
+    keep_count = EXCLUDED.keep_count,
+    updated_at = now();
+
+-- name: DeleteUserRetention :exec
+DELETE FROM retention_policies
+WHERE user_id = $1;
+
+-- name: DeleteEndpointRetention :exec
+DELETE FROM retention_policies
+WHERE endpoint_id = $1;
+
+-- name: PurgeExpiredNotifications :execrows
+-- 보관 기간이 지난 알림을 오래된 것부터 batch_size만큼 삭제.
+-- 유저, endpoint 정책과 서버 최대 중 가장 짧은 기간을 적용하고,
+-- 가장 짧은 정책 기간으로 먼저 걸러 created_at 인덱스를 쓴다. max_days가 0이면 서버 최대 없음(NULL은 LEAST에서 빠진다).
+-- 전송 중이거나 응답을 기다리는 ask는 서버 최대 기간이 지나기 전까지(없으면 계속) 남기고, 리액션 이력은 FK SET NULL로 남는다
+DELETE FROM notifications
+WHERE id IN (
+    SELECT n.id
+    FROM notifications n
+    LEFT JOIN retention_policies ur ON ur.user_id = n.user_id
+    LEFT JOIN retention_policies er ON er.endpoint_id = n.endpoint_id
+    WHERE n.created_at < now() - make_interval(days => (
+        SELECT LEAST(min(keep_days), NULLIF(sqlc.arg('max_days')::int, 0)) FROM retention_policies
+    ))
+      AND n.created_at < now() - make_interval(days => LEAST(ur.keep_days, er.keep_days, NULLIF(sqlc.arg('max_days')::int, 0)))
+      AND (
+        NOT (n.status = 'pending' OR (n.actions IS NOT NULL AND n.reaction IS NULL AND n.status = 'sent'))
+        OR n.created_at < now() - make_interval(days => NULLIF(sqlc.arg('max_days')::int, 0))
+      )
+    ORDER BY n.id
+    LIMIT sqlc.arg('batch_size')
+    FOR UPDATE OF n SKIP LOCKED
+);
+
+-- name: PurgeExcessNotifications :execrows
+-- 유저 전체 또는 멤버별 endpoint 보관 개수를 넘은 오래된 알림을 batch_size만큼 삭제.
+-- 정책마다 인덱스로 keep_count+1번째 알림만 찾고 그보다 오래된 것을 지우므로 batch마다 전체 순위를 다시 매기지 않는다.
+-- 휴지통의 알림(휴지통 비우기와 보관 기간으로 정리)과 전송 중이거나 응답을 기다리는 ask는 세지도 지우지도 않는다.
+-- endpoint 정책은 현재 멤버에게만 적용. 리액션 이력은 FK SET NULL로 남는다
+DELETE FROM notifications
+WHERE id IN (
+    SELECT n.id
+    FROM (
+        SELECT p.user_id, NULL::uuid AS endpoint_id, p.keep_count
+        FROM retention_policies p
+        WHERE p.user_id IS NOT NULL
+          AND p.keep_count IS NOT NULL
+        UNION ALL
+        SELECT m.user_id, p.endpoint_id, p.keep_count
+        FROM retention_policies p
+        JOIN endpoint_members m ON m.endpoint_id = p.endpoint_id
+        WHERE p.keep_count IS NOT NULL
+    ) target
+    CROSS JOIN LATERAL (
+        SELECT c.id
+        FROM notifications c
+        WHERE c.user_id = target.user_id
+          AND (target.endpoint_id IS NULL OR c.endpoint_id = target.endpoint_id)
+          AND c.is_deleted = false
+          AND NOT (c.status = 'pending' OR (c.actions IS NOT NULL AND c.reaction IS NULL AND c.status = 'sent'))
+        ORDER BY c.id DESC
+        OFFSET target.keep_count
+        LIMIT 1
+    ) cutoff
+    JOIN notifications n ON n.user_id = target.user_id
+    WHERE (target.endpoint_id IS NULL OR n.endpoint_id = target.endpoint_id)
+      AND n.id <= cutoff.id
+      AND n.is_deleted = false
+      AND NOT (n.status = 'pending' OR (n.actions IS NOT NULL AND n.reaction IS NULL AND n.status = 'sent'))
+    ORDER BY n.id
+    LIMIT sqlc.arg('batch_size')
+    FOR UPDATE OF n SKIP LOCKED
+);
+
+-- name: PurgeNotificationTombstones :execrows
+-- 변경 피드가 더 이상 보여주지 않는 오래된 tombstone을 batch_size만큼 삭제
+DELETE FROM notification_tombstones
+WHERE notification_id IN (
+    SELECT notification_id
+    FROM notification_tombstones
+    WHERE deleted_at < sqlc.arg('deleted_before')
+    ORDER BY deleted_at
+    LIMIT sqlc.arg('batch_size')
+    FOR UPDATE SKIP LOCKED
+);
@@ -0,0 +1,88 @@
+package retention
+
+import (
+	"context"
+	"torchi/internal/domain/endpoint"
+	"torchi/internal/pkg/config"
+	"torchi/internal/pkg/token"
+
+	"github.com/google/uuid"
+)
+
+type RetentionService struct {
+	repo      RetentionRepository
+	endpoints *endpoint.EndpointService
+	maxDays   int
+}
+
+func NewRetentionService(
+	env config.Env,
+	repo RetentionRepository,
+	endpoints *endpoint.EndpointService,
+) *RetentionService {
+	return &RetentionService{
+		repo:      repo,
+		endpoints: endpoints,
+		maxDays:   env.Retention.MaxDays,
+	}
+}
+
+// MaxDays 서버 최대 보관 일수. 0이면 없음
+func (s *RetentionService) MaxDays() int {
+	return s.maxDays
+}
+
+// UserPolicy 내 알림 전체에 적용되는 정책. 설정한 적 없으면 빈 정책
+func (s *RetentionService) UserPolicy(ctx context.Context) (Policy, error) {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return Policy{}, err
+	}
+	return orEmpty(s.repo.FindByUser(ctx, userClaim.UserID))
+}
+
+// UpdateUserPolicy 빈 정책이면 설정을 지운다
+func (s *RetentionService) UpdateUserPolicy(ctx context.Context, policy Policy) error {
+	userClaim, err := token.UserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := policy.validate(s.maxDays); err != nil {
+		return err
+	}
+
+	if policy.isEmpty() {
+		return s.repo.DeleteUser(ctx, userClaim.UserID)
+	}
+	return s.repo.SaveUser(ctx, userClaim.UserID, policy)
+}
+
+// EndpointPolicy endpoint를 볼 수 있는 멤버라면 누구나 조회 가능
+func (s *RetentionService) EndpointPolicy(ctx context.Context, endpointID uuid.UUID) (Policy, error) {
+	if err := s.endpoints.Authorize(ctx, endpointID, false); err != nil {
+		return Policy{}, err
+	}
+	return orEmpty(s.repo.FindByEndpoint(ctx, endpointID))
+}
+
+// UpdateEndpointPolicy 관리 권한 필요. 모든 멤버의 해당 endpoint 알림에 적용된다
+func (s *RetentionService) UpdateEndpointPolicy(ctx context.Context, endpointID uuid.UUID, policy Policy) error {
+	if err := s.endpoints.Authorize(ctx, endpointID, true); err != nil {
+		return err
+	}
+	if err := policy.validate(s.maxDays); err != nil {
+		return err
+	}
+
+	if policy.isEmpty() {
+		return s.repo.DeleteEndpoint(ctx, endpointID)
+	}
+	return s.repo.SaveEndpoint(ctx, endpointID, policy)
+}
+
+func orEmpty(policy *Policy, err error) (Policy, error) {
+	if err != nil || policy == nil {
+		return Policy{}, err
+	}
+	return *policy, nil
+}
@@ -2,25 +2,50 @@ package sse
 
 import (
 	"log/slog"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	clientBuffer = 16
+	// replaySize 유저별로 다시 보내줄 수 있는 최근 이벤트 수
+	replaySize = 256
+	// replayTTL 연결이 없는 유저의 replay buffer를 이 시간 동안 새 이벤트가 없으면 버린다
+	replayTTL = 10 * time.Minute
+)
+
+// EventResync Last-Event-ID 이후를 다 보내줄 수 없을 때. 클라이언트는 목록을 다시 맞춰야 한다
+const EventResync = "resync"
+
 type SSEEvent struct {
+	ID    string      // epoch-seq. seq는 브로커 전체에서 증가. Publish가 채운다
 	Event string      // "notification", "heartbeat" 등
 	Data  interface{} // JSON 직렬화될 데이터
+	// EndpointID 이벤트가 속한 endpoint. 스트림 필터용이며 전송되지 않는다
+	EndpointID *uuid.UUID
+}
+
+type userStream struct {
+	clients    map[chan SSEEvent]struct{}
+	replay     *replayBuffer
+	lastActive time.Time
 }
 
 type Broker struct {
-	mu      sync.RWMutex
-	clients map[uuid.UUID]map[chan SSEEvent]struct{}
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64 // 마지막으로 발행한 이벤트
+	streams map[uuid.UUID]*userStream
 	done    chan struct{}
 }
 
 func NewBroker() *Broker {
 	return &Broker{
-		clients: make(map[uuid.UUID]map[chan SSEEvent]struct{}),
+		epoch:   strconv.FormatInt(time.Now().UnixMilli(), 36),
+		streams: make(map[uuid.UUID]*userStream),
 		done:    make(chan struct{}),
 	}
 }
@@ -35,11 +60,11 @@ func (b *Broker) Shutdown() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	for userID, set := range b.clients {
-		for ch := range set {
+	for userID, stream := range b.streams {
+		for ch := range stream.clients {
 			close(ch)
 		}
-		delete(b.clients, userID)
+		delete(b.streams, userID)
 	}
 
 	close(b.done)
@@ -47,55 +72,110 @@ func (b *Broker) Shutdown() {
 
 // HasSubscribers returns true if there are any subscribers for the userID
 func (b *Broker) HasSubscribers(userID uuid.UUID) bool {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
-	return len(b.clients[userID]) > 0
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stream, ok := b.streams[userID]
+	return ok && len(stream.clients) > 0
 }
 
-func (b *Broker) Subscribe(userID uuid.UUID) chan SSEEvent {
-	ch := make(chan SSEEvent, 16)
+func (b *Broker) stream(userID uuid.UUID) *userStream {
+	stream, ok := b.streams[userID]
+	if !ok {
+		stream = &userStream{
+			clients: make(map[chan SSEEvent]struct{}),
+			replay:  newReplayBuffer(replaySize, b.seq),
+		}
+		b.streams[userID] = stream
+	}
+	stream.lastActive = time.Now()
+	return stream
+}
+
+// Subscribe lastEventID가 있으면 그 이후 놓친 이벤트를 함께 반환한다.
+// 다 보내줄 수 없으면 resync 이벤트 하나를 반환한다. 등록과 같은 잠금 안에서 계산하므로 빠지거나 겹치는 이벤트는 없다
+func (b *Broker) Subscribe(userID uuid.UUID, lastEventID string) (chan SSEEvent, []SSEEvent) {
+	ch := make(chan SSEEvent, clientBuffer)
 
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if b.clients[userID] == nil {
-		b.clients[userID] = make(map[chan SSEEvent]struct{})
+	stream := b.stream(userID)
+	stream.clients[ch] = struct{}{}
+	slog.Debug("subscribe", "userID", userID, "clients", len(stream.clients))
+
+	if lastEventID == "" {
+		return ch, nil
 	}
-	b.clients[userID][ch] = struct{}{}
-	slog.Debug("subscribe", "clients", b.clients[userID])
 
-	return ch
+	epoch, seq, ok := parseEventID(lastEventID)
+	if ok && epoch == b.epoch {
+		if missed, ok := stream.replay.since(seq, b.seq); ok {
+			return ch, missed
+		}
+	}
+
+	return ch, []SSEEvent{{
+		ID:    formatEventID(b.epoch, b.seq),
+		Event: EventResync,
+		Data:  struct{}{},
+	}}
 }
 
 func (b *Broker) Unsubscribe(userID uuid.UUID, ch chan SSEEvent) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	slog.Debug("unsubscribe", "clients", b.clients[userID])
-
-	if set, ok := b.clients[userID]; ok {
-		delete(set, ch)
+	stream, ok := b.streams[userID]
+	if !ok {
+		return
+	}
+	// 버퍼가 넘쳐 Publish가 먼저 끊은 경우 이미 닫혀있다
+	if _, ok := stream.clients[ch]; ok {
+		delete(stream.clients, ch)
 		close(ch)
-		if len(set) == 0 {
-			delete(b.clients, userID)
-		}
 	}
+	slog.Debug("unsubscribe", "userID", userID, "clients", len(stream.clients))
 }
 
+// Publish 연결이 없어도 replay buffer에는 남긴다.
+// 버퍼가 가득 찬 클라이언트는 끊어서 Last-Event-ID로 다시 연결해 놓친 이벤트를 받게 한다
 func (b *Broker) Publish(userID uuid.UUID, event SSEEvent) {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
+	b.mu.Lock()
+	defer b.mu.Unlock()
 
-	set, ok := b.clients[userID]
-	if !ok {
-		return
-	}
+	stream := b.stream(userID)
+	b.seq++
+	event.ID = formatEventID(b.epoch, b.seq)
+	stream.replay.push(b.seq, event)
 
-	for ch := range set {
-		// non-blocking send
+	for ch := range stream.clients {
 		select {
 		case ch <- event:
-		default: // 버퍼가 가득 차있을 때 블락되는 걸 방지
+		default:
+			slog.Info("sse client too slow, disconnecting", "userID", userID)
+			delete(stream.clients, ch)
+			close(ch)
 		}
 	}
 }
+
+// Prune 연결이 없고 replayTTL 동안 새 이벤트가 없던 유저의 replay buffer를 버린다
+func (b *Broker) Prune(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for userID, stream := range b.streams {
+		if len(stream.clients) == 0 && now.Sub(stream.lastActive) > replayTTL {
+			delete(b.streams, userID)
+		}
+	}
+}
+
+// EndpointBroker endpoint 단위 구독 (read scope 키로 /push/{token}/stream). 유저 브로커와 키를 섞지 않도록 따로 둔다
+type EndpointBroker struct {
+	*Broker
+}
+
+func NewEndpointBroker() *EndpointBroker {
+	return &EndpointBroker{Broker: NewBroker()}
+}
@@ -0,0 +1,52 @@
+package sse
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBroker_SubscribeReplay(t *testing.T) {
+	b := NewBroker()
+	userID := uuid.New()
+
+	ch, _ := b.Subscribe(userID, "")
+	b.Publish(userID, SSEEvent{Event: "a"})
+	b.Publish(userID, SSEEvent{Event: "b"})
+	first := <-ch
+	b.Unsubscribe(userID, ch)
+
+	_, missed := b.Subscribe(userID, first.ID)
+	if len(missed) != 1 || missed[0].Event != "b" {
+		t.Fatalf("expected to replay [b], got: %+v", missed)
+	}
+
+	_, missed = b.Subscribe(userID, "old-1")
+	if len(missed) != 1 || missed[0].Event != EventResync {
+		t.Fatalf("expected resync for unknown epoch, got: %+v", missed)
+	}
+}
+
+func TestBroker_DisconnectSlowClient(t *testing.T) {
+	b := NewBroker()
+	userID := uuid.New()
+
+	ch, _ := b.Subscribe(userID, "")
+	for range clientBuffer + 1 {
+		b.Publish(userID, SSEEvent{Event: "n"})
+	}
+
+	received := 0
+	for range ch {
+		received++
+	}
+	if received != clientBuffer {
+		t.Errorf("expected %d buffered events before close, got: %d", clientBuffer, received)
+	}
+	if b.HasSubscribers(userID) {
+		t.Error("slow client should be removed")
+	}
+
+	// 핸들러의 defer Unsubscribe가 다시 닫지 않아야 한다
+	b.Unsubscribe(userID, ch)
+}
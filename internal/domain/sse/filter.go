@@ -0,0 +1,63 @@
+package sse
+
+import (
+	"strings"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+// Filter 스트림에서 받을 이벤트. 비어 있는 조건은 전부 받는다.
+// 모르는 이벤트 이름은 그냥 아무것도 걸리지 않을 뿐 에러가 아니다 (새 이벤트가 추가돼도 이전 클라이언트가 깨지지 않게)
+type Filter struct {
+	Events    map[string]bool
+	Endpoints map[uuid.UUID]bool
+}
+
+// ParseFilter events, endpoint_id 쿼리 값. 쉼표로 구분하거나 여러 번 줄 수 있다
+func ParseFilter(events, endpoints []string) (Filter, error) {
+	var f Filter
+	for _, name := range splitValues(events) {
+		if f.Events == nil {
+			f.Events = map[string]bool{}
+		}
+		f.Events[name] = true
+	}
+	for _, s := range splitValues(endpoints) {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return Filter{}, common.ErrInvalidParam
+		}
+		if f.Endpoints == nil {
+			f.Endpoints = map[uuid.UUID]bool{}
+		}
+		f.Endpoints[id] = true
+	}
+	return f, nil
+}
+
+func splitValues(values []string) []string {
+	var result []string
+	for _, v := range values {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
+
+// Match resync는 항상 보낸다. endpoint와 관계없는 이벤트(unread 등)는 endpoint 조건에 걸리지 않는다
+func (f Filter) Match(event SSEEvent) bool {
+	if event.Event == EventResync {
+		return true
+	}
+	if f.Events != nil && !f.Events[event.Event] {
+		return false
+	}
+	if f.Endpoints != nil && event.EndpointID != nil && !f.Endpoints[*event.EndpointID] {
+		return false
+	}
+	return true
+}
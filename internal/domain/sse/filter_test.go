@@ -0,0 +1,62 @@
+package sse
+
+import (
+	"errors"
+	"testing"
+	"torchi/internal/domain/common"
+
+	"github.com/google/uuid"
+)
+
+func TestParseFilter(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+
+	f, err := ParseFilter([]string{"read, deleted", "reacted"}, []string{a.String() + "," + b.String()})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(f.Events) != 3 || !f.Events["deleted"] || len(f.Endpoints) != 2 || !f.Endpoints[b] {
+		t.Errorf("unexpected filter: %+v", f)
+	}
+
+	f, err = ParseFilter(nil, []string{""})
+	if err != nil || f.Events != nil || f.Endpoints != nil {
+		t.Errorf("empty values should not filter, got: %+v %v", f, err)
+	}
+
+	if _, err := ParseFilter(nil, []string{"nope"}); !errors.Is(err, common.ErrInvalidParam) {
+		t.Errorf("expected: %v, got: %v", common.ErrInvalidParam, err)
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	f := Filter{
+		Events:    map[string]bool{"notification": true, "unread": true},
+		Endpoints: map[uuid.UUID]bool{a: true},
+	}
+
+	cases := []struct {
+		name  string
+		event SSEEvent
+		want  bool
+	}{
+		{"matching endpoint", SSEEvent{Event: "notification", EndpointID: &a}, true},
+		{"other endpoint", SSEEvent{Event: "notification", EndpointID: &b}, false},
+		{"without endpoint", SSEEvent{Event: "unread"}, true},
+		{"other event", SSEEvent{Event: "deleted"}, false},
+		{"resync", SSEEvent{Event: EventResync}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := f.Match(c.event); got != c.want {
+				t.Errorf("expected: %v, got: %v", c.want, got)
+			}
+		})
+	}
+
+	if !(Filter{}).Match(SSEEvent{Event: "anything", EndpointID: &b}) {
+		t.Error("empty filter should match everything")
+	}
+}
@@ -2,18 +2,41 @@ package sse
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/fx"
 )
 
 var Module = fx.Options(
-	fx.Provide(NewBroker),
-	fx.Invoke(func(lc fx.Lifecycle, broker *Broker) {
-		lc.Append(fx.Hook{
-			OnStop: func(ctx context.Context) error {
-				broker.Shutdown()
-				return nil
-			},
-		})
+	fx.Provide(NewBroker, NewEndpointBroker),
+	fx.Invoke(func(lc fx.Lifecycle, broker *Broker, endpointBroker *EndpointBroker) {
+		registerLifecycle(lc, broker)
+		registerLifecycle(lc, endpointBroker.Broker)
 	}),
 )
+
+// registerLifecycle 1분마다 Prune, 종료 시 Shutdown
+func registerLifecycle(lc fx.Lifecycle, broker *Broker) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				ticker := time.NewTicker(time.Minute)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-broker.Done():
+						return
+					case now := <-ticker.C:
+						broker.Prune(now)
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			broker.Shutdown()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,71 @@
+package sse
+
+import (
+	"strconv"
+	"strings"
+)
+
+// replayBuffer 유저별 최근 이벤트. 가득 차면 가장 오래된 것부터 덮어쓴다.
+// seq는 브로커 전체에서 증가하므로 한 유저 안에서는 건너뛸 수 있다
+type replayBuffer struct {
+	events []SSEEvent
+	seqs   []uint64
+	start  int // 가장 오래된 이벤트 위치
+	size   int
+	// floor 이 seq까지의 이벤트는 더 이상 알 수 없다 (덮어썼거나 buffer를 만들기 전)
+	floor uint64
+}
+
+func newReplayBuffer(capacity int, floor uint64) *replayBuffer {
+	return &replayBuffer{
+		events: make([]SSEEvent, capacity),
+		seqs:   make([]uint64, capacity),
+		floor:  floor,
+	}
+}
+
+func (b *replayBuffer) push(seq uint64, event SSEEvent) {
+	capacity := len(b.events)
+	i := (b.start + b.size) % capacity
+	if b.size == capacity {
+		b.floor = b.seqs[b.start]
+		b.start = (b.start + 1) % capacity
+	} else {
+		b.size++
+	}
+	b.events[i] = event
+	b.seqs[i] = seq
+}
+
+// since seq 이후의 이벤트. 이미 덮어써서 빠진 게 있을 수 있거나 current보다 큰 seq면 false
+func (b *replayBuffer) since(seq, current uint64) ([]SSEEvent, bool) {
+	if seq > current || seq < b.floor {
+		return nil, false
+	}
+
+	var result []SSEEvent
+	for k := 0; k < b.size; k++ {
+		i := (b.start + k) % len(b.events)
+		if b.seqs[i] > seq {
+			result = append(result, b.events[i])
+		}
+	}
+	return result, true
+}
+
+// formatEventID epoch-seq. epoch는 브로커가 시작된 시각이라 재시작 전의 ID는 구분된다
+func formatEventID(epoch string, seq uint64) string {
+	return epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+func parseEventID(id string) (string, uint64, bool) {
+	epoch, seqStr, ok := strings.Cut(id, "-")
+	if !ok || epoch == "" {
+		return "", 0, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return epoch, seq, true
+}
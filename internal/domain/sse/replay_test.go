@@ -0,0 +1,74 @@
+package sse
+
+import "testing"
+
+func events(b *replayBuffer, since, current uint64) ([]string, bool) {
+	got, ok := b.since(since, current)
+	names := make([]string, len(got))
+	for i, e := range got {
+		names[i] = e.Event
+	}
+	return names, ok
+}
+
+func TestReplayBuffer_Since(t *testing.T) {
+	b := newReplayBuffer(3, 10)
+	b.push(12, SSEEvent{Event: "a"})
+	b.push(15, SSEEvent{Event: "b"})
+
+	cases := []struct {
+		name   string
+		since  uint64
+		want   []string
+		wantOK bool
+	}{
+		{"from floor", 10, []string{"a", "b"}, true},
+		{"skipped seq", 13, []string{"b"}, true},
+		{"up to date", 15, []string{}, true},
+		{"other users events after", 20, []string{}, true},
+		{"before floor", 9, nil, false},
+		{"future", 21, nil, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := events(b, c.since, 20)
+			if ok != c.wantOK || len(got) != len(c.want) {
+				t.Fatalf("expected: %v %v, got: %v %v", c.want, c.wantOK, got, ok)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Fatalf("expected: %v, got: %v", c.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayBuffer_Overwrite(t *testing.T) {
+	b := newReplayBuffer(2, 0)
+	b.push(1, SSEEvent{Event: "a"})
+	b.push(2, SSEEvent{Event: "b"})
+	b.push(3, SSEEvent{Event: "c"})
+
+	if _, ok := b.since(0, 3); ok {
+		t.Error("expected gap after overwriting seq 1")
+	}
+	got, ok := events(b, 1, 3)
+	if !ok || len(got) != 2 || got[0] != "b" || got[1] != "c" {
+		t.Errorf("expected: [b c], got: %v %v", got, ok)
+	}
+}
+
+func TestParseEventID(t *testing.T) {
+	epoch, seq, ok := parseEventID(formatEventID("abc", 42))
+	if !ok || epoch != "abc" || seq != 42 {
+		t.Errorf("round trip failed: %q %d %v", epoch, seq, ok)
+	}
+
+	for _, id := range []string{"", "abc", "-1", "abc-", "abc-x"} {
+		if _, _, ok := parseEventID(id); ok {
+			t.Errorf("parseEventID(%q) expected invalid", id)
+		}
+	}
+}
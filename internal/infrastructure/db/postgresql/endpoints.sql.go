@@ -13,62 +13,264 @@ import (
 )
 
 const createEndpoint = `-- name: CreateEndpoint :one
-INSERT INTO endpoints (
-    user_id,
-    name,
-    token
-) VALUES (
-    $1, 
-    $2, 
-    $3
+WITH created AS (
+    INSERT INTO endpoints (
+        user_id,
+        org_id,
+        name,
+        token_hash,
+        token_prefix
+    ) VALUES (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5
+    )
+    RETURNING id, user_id
+), owner AS (
+    INSERT INTO endpoint_members (endpoint_id, user_id, role)
+    SELECT id, $6, CASE WHEN user_id IS NULL THEN 'member' ELSE 'owner' END
+    FROM created
 )
-RETURNING id, user_id, name, token, notification_enabled, notification_disabled_at, created_at
+SELECT id FROM created
 `
 
 type CreateEndpointParams struct {
+	UserID      *uuid.UUID
+	OrgID       *uuid.UUID
+	Name        string
+	TokenHash   *string
+	TokenPrefix *string
+	CreatedBy   uuid.UUID
+}
+
+// 만든 유저를 멤버로 함께 등록. 개인 endpoint면 owner, 조직 endpoint면 member
+func (q *Queries) CreateEndpoint(ctx context.Context, arg CreateEndpointParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createEndpoint,
+		arg.UserID,
+		arg.OrgID,
+		arg.Name,
+		arg.TokenHash,
+		arg.TokenPrefix,
+		arg.CreatedBy,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteEndpoint = `-- name: DeleteEndpoint :execrows
+DELETE FROM endpoints
+WHERE id = $1
+  AND (
+      endpoints.user_id = $2::uuid
+      OR endpoints.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = $2
+            AND om.role IN ('owner', 'admin')
+      )
+  )
+`
+
+type DeleteEndpointParams struct {
+	ID     uuid.UUID
 	UserID uuid.UUID
-	Name   string
-	Token  string
 }
 
-func (q *Queries) CreateEndpoint(ctx context.Context, arg CreateEndpointParams) (Endpoint, error) {
-	row := q.db.QueryRow(ctx, createEndpoint, arg.UserID, arg.Name, arg.Token)
-	var i Endpoint
+// 관리 권한이 없으면 row가 없음
+func (q *Queries) DeleteEndpoint(ctx context.Context, arg DeleteEndpointParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteEndpoint, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const disableEndpointSigning = `-- name: DisableEndpointSigning :execrows
+UPDATE endpoints
+SET signing_secret = NULL,
+    signing_required = false
+WHERE id = $1
+  AND (
+      endpoints.user_id = $2::uuid
+      OR endpoints.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = $2
+            AND om.role IN ('owner', 'admin')
+      )
+  )
+`
+
+type DisableEndpointSigningParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+// 관리 권한이 없으면 row가 없음
+func (q *Queries) DisableEndpointSigning(ctx context.Context, arg DisableEndpointSigningParams) (int64, error) {
+	result, err := q.db.Exec(ctx, disableEndpointSigning, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const enableEndpointSigning = `-- name: EnableEndpointSigning :execrows
+UPDATE endpoints
+SET signing_secret = $1,
+    signing_required = true
+WHERE id = $2
+  AND (
+      endpoints.user_id = $3::uuid
+      OR endpoints.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = $3
+            AND om.role IN ('owner', 'admin')
+      )
+  )
+`
+
+type EnableEndpointSigningParams struct {
+	SigningSecret *string
+	ID            uuid.UUID
+	UserID        uuid.UUID
+}
+
+// 이미 활성화된 경우 secret을 새로 발급. 관리 권한이 없으면 row가 없음
+func (q *Queries) EnableEndpointSigning(ctx context.Context, arg EnableEndpointSigningParams) (int64, error) {
+	result, err := q.db.Exec(ctx, enableEndpointSigning, arg.SigningSecret, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const findEndpointAccess = `-- name: FindEndpointAccess :one
+SELECT
+    e.user_id,
+    e.org_id,
+    om.role AS org_role,
+    EXISTS (
+        SELECT 1 FROM endpoint_members m
+        WHERE m.endpoint_id = e.id
+          AND m.user_id = $1
+    ) AS is_member
+FROM endpoints e
+LEFT JOIN organization_members om ON om.org_id = e.org_id AND om.user_id = $1
+WHERE e.id = $2
+`
+
+type FindEndpointAccessParams struct {
+	UserID uuid.UUID
+	ID     uuid.UUID
+}
+
+type FindEndpointAccessRow struct {
+	UserID   *uuid.UUID
+	OrgID    *uuid.UUID
+	OrgRole  *string
+	IsMember bool
+}
+
+// 권한 확인용. 조직 멤버가 아니면 org_role은 NULL
+func (q *Queries) FindEndpointAccess(ctx context.Context, arg FindEndpointAccessParams) (FindEndpointAccessRow, error) {
+	row := q.db.QueryRow(ctx, findEndpointAccess, arg.UserID, arg.ID)
+	var i FindEndpointAccessRow
 	err := row.Scan(
-		&i.ID,
 		&i.UserID,
-		&i.Name,
-		&i.Token,
-		&i.NotificationEnabled,
-		&i.NotificationDisabledAt,
-		&i.CreatedAt,
+		&i.OrgID,
+		&i.OrgRole,
+		&i.IsMember,
 	)
 	return i, err
 }
 
-const deleteEndpointByToken = `-- name: DeleteEndpointByToken :exec
-DELETE FROM endpoints
-WHERE token = $1
-  AND user_id = $2
+const findEndpointByOrgID = `-- name: FindEndpointByOrgID :many
+SELECT
+    e.id, e.user_id, e.name, e.token, e.notification_enabled, e.notification_disabled_at, e.created_at, e.previous_token, e.previous_token_expires_at, e.description, e.icon, e.color, e.default_ttl, e.default_urgency, e.default_click_url, e.allowed_cidrs, e.ask_enabled, e.max_ask_timeout, e.max_body_size, e.expires_at, e.signing_secret, e.signing_required, e.token_hash, e.token_prefix, e.previous_token_hash, e.org_id,
+    m.role AS member_role,
+    m.notification_enabled AS member_notification_enabled,
+    m.muted_until AS member_muted_until
+FROM endpoints e
+LEFT JOIN endpoint_members m ON m.endpoint_id = e.id AND m.user_id = $1
+WHERE e.org_id = $2
+ORDER BY e.created_at
 `
 
-type DeleteEndpointByTokenParams struct {
-	Token  string
+type FindEndpointByOrgIDParams struct {
 	UserID uuid.UUID
+	OrgID  *uuid.UUID
 }
 
-func (q *Queries) DeleteEndpointByToken(ctx context.Context, arg DeleteEndpointByTokenParams) error {
-	_, err := q.db.Exec(ctx, deleteEndpointByToken, arg.Token, arg.UserID)
-	return err
+type FindEndpointByOrgIDRow struct {
+	Endpoint                  Endpoint
+	MemberRole                *string
+	MemberNotificationEnabled *bool
+	MemberMutedUntil          *time.Time
 }
 
-const findEndpointByToken = `-- name: FindEndpointByToken :one
-SELECT id, user_id, name, token, notification_enabled, notification_disabled_at, created_at FROM endpoints
-WHERE token = $1
+// 조직의 모든 endpoint. 알림을 받지 않는 endpoint는 member_role이 NULL
+func (q *Queries) FindEndpointByOrgID(ctx context.Context, arg FindEndpointByOrgIDParams) ([]FindEndpointByOrgIDRow, error) {
+	rows, err := q.db.Query(ctx, findEndpointByOrgID, arg.UserID, arg.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindEndpointByOrgIDRow
+	for rows.Next() {
+		var i FindEndpointByOrgIDRow
+		if err := rows.Scan(
+			&i.Endpoint.ID,
+			&i.Endpoint.UserID,
+			&i.Endpoint.Name,
+			&i.Endpoint.Token,
+			&i.Endpoint.NotificationEnabled,
+			&i.Endpoint.NotificationDisabledAt,
+			&i.Endpoint.CreatedAt,
+			&i.Endpoint.PreviousToken,
+			&i.Endpoint.PreviousTokenExpiresAt,
+			&i.Endpoint.Description,
+			&i.Endpoint.Icon,
+			&i.Endpoint.Color,
+			&i.Endpoint.DefaultTtl,
+			&i.Endpoint.DefaultUrgency,
+			&i.Endpoint.DefaultClickUrl,
+			&i.Endpoint.AllowedCidrs,
+			&i.Endpoint.AskEnabled,
+			&i.Endpoint.MaxAskTimeout,
+			&i.Endpoint.MaxBodySize,
+			&i.Endpoint.ExpiresAt,
+			&i.Endpoint.SigningSecret,
+			&i.Endpoint.SigningRequired,
+			&i.Endpoint.TokenHash,
+			&i.Endpoint.TokenPrefix,
+			&i.Endpoint.PreviousTokenHash,
+			&i.Endpoint.OrgID,
+			&i.MemberRole,
+			&i.MemberNotificationEnabled,
+			&i.MemberMutedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findEndpointByTokenHash = `-- name: FindEndpointByTokenHash :one
+SELECT id, user_id, name, token, notification_enabled, notification_disabled_at, created_at, previous_token, previous_token_expires_at, description, icon, color, default_ttl, default_urgency, default_click_url, allowed_cidrs, ask_enabled, max_ask_timeout, max_body_size, expires_at, signing_secret, signing_required, token_hash, token_prefix, previous_token_hash, org_id FROM endpoints
+WHERE token_hash = $1
+   OR (previous_token_hash = $1 AND previous_token_expires_at > now())
 `
 
-func (q *Queries) FindEndpointByToken(ctx context.Context, token string) (Endpoint, error) {
-	row := q.db.QueryRow(ctx, findEndpointByToken, token)
+// 재발급 유예기간 중이면 이전 토큰으로도 조회됨.
+// 평문 토큰은 서버 시작 시 backfill로 모두 해시로 옮긴 뒤 요청을 받는다
+func (q *Queries) FindEndpointByTokenHash(ctx context.Context, tokenHash *string) (Endpoint, error) {
+	row := q.db.QueryRow(ctx, findEndpointByTokenHash, tokenHash)
 	var i Endpoint
 	err := row.Scan(
 		&i.ID,
@@ -78,32 +280,98 @@ func (q *Queries) FindEndpointByToken(ctx context.Context, token string) (Endpoi
 		&i.NotificationEnabled,
 		&i.NotificationDisabledAt,
 		&i.CreatedAt,
+		&i.PreviousToken,
+		&i.PreviousTokenExpiresAt,
+		&i.Description,
+		&i.Icon,
+		&i.Color,
+		&i.DefaultTtl,
+		&i.DefaultUrgency,
+		&i.DefaultClickUrl,
+		&i.AllowedCidrs,
+		&i.AskEnabled,
+		&i.MaxAskTimeout,
+		&i.MaxBodySize,
+		&i.ExpiresAt,
+		&i.SigningSecret,
+		&i.SigningRequired,
+		&i.TokenHash,
+		&i.TokenPrefix,
+		&i.PreviousTokenHash,
+		&i.OrgID,
 	)
 	return i, err
 }
 
 const findEndpointByUserID = `-- name: FindEndpointByUserID :many
-SELECT id, user_id, name, token, notification_enabled, notification_disabled_at, created_at FROM endpoints
-WHERE user_id = $1
+SELECT
+    e.id, e.user_id, e.name, e.token, e.notification_enabled, e.notification_disabled_at, e.created_at, e.previous_token, e.previous_token_expires_at, e.description, e.icon, e.color, e.default_ttl, e.default_urgency, e.default_click_url, e.allowed_cidrs, e.ask_enabled, e.max_ask_timeout, e.max_body_size, e.expires_at, e.signing_secret, e.signing_required, e.token_hash, e.token_prefix, e.previous_token_hash, e.org_id,
+    m.role,
+    m.notification_enabled AS member_notification_enabled,
+    m.notification_disabled_at AS member_notification_disabled_at,
+    m.muted_until AS member_muted_until,
+    o.name AS org_name,
+    om.role AS org_role
+FROM endpoints e
+JOIN endpoint_members m ON m.endpoint_id = e.id
+LEFT JOIN organizations o ON o.id = e.org_id
+LEFT JOIN organization_members om ON om.org_id = e.org_id AND om.user_id = m.user_id
+WHERE m.user_id = $1
 `
 
-func (q *Queries) FindEndpointByUserID(ctx context.Context, userID uuid.UUID) ([]Endpoint, error) {
+type FindEndpointByUserIDRow struct {
+	Endpoint                     Endpoint
+	Role                         string
+	MemberNotificationEnabled    bool
+	MemberNotificationDisabledAt *time.Time
+	MemberMutedUntil             *time.Time
+	OrgName                      *string
+	OrgRole                      *string
+}
+
+// 소유하거나 참여한 endpoint. 음소거 상태는 멤버별
+func (q *Queries) FindEndpointByUserID(ctx context.Context, userID uuid.UUID) ([]FindEndpointByUserIDRow, error) {
 	rows, err := q.db.Query(ctx, findEndpointByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Endpoint
+	var items []FindEndpointByUserIDRow
 	for rows.Next() {
-		var i Endpoint
+		var i FindEndpointByUserIDRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.Name,
-			&i.Token,
-			&i.NotificationEnabled,
-			&i.NotificationDisabledAt,
-			&i.CreatedAt,
+			&i.Endpoint.ID,
+			&i.Endpoint.UserID,
+			&i.Endpoint.Name,
+			&i.Endpoint.Token,
+			&i.Endpoint.NotificationEnabled,
+			&i.Endpoint.NotificationDisabledAt,
+			&i.Endpoint.CreatedAt,
+			&i.Endpoint.PreviousToken,
+			&i.Endpoint.PreviousTokenExpiresAt,
+			&i.Endpoint.Description,
+			&i.Endpoint.Icon,
+			&i.Endpoint.Color,
+			&i.Endpoint.DefaultTtl,
+			&i.Endpoint.DefaultUrgency,
+			&i.Endpoint.DefaultClickUrl,
+			&i.Endpoint.AllowedCidrs,
+			&i.Endpoint.AskEnabled,
+			&i.Endpoint.MaxAskTimeout,
+			&i.Endpoint.MaxBodySize,
+			&i.Endpoint.ExpiresAt,
+			&i.Endpoint.SigningSecret,
+			&i.Endpoint.SigningRequired,
+			&i.Endpoint.TokenHash,
+			&i.Endpoint.TokenPrefix,
+			&i.Endpoint.PreviousTokenHash,
+			&i.Endpoint.OrgID,
+			&i.Role,
+			&i.MemberNotificationEnabled,
+			&i.MemberNotificationDisabledAt,
+			&i.MemberMutedUntil,
+			&i.OrgName,
+			&i.OrgRole,
 		); err != nil {
 			return nil, err
 		}
@@ -115,31 +383,448 @@ func (q *Queries) FindEndpointByUserID(ctx context.Context, userID uuid.UUID) ([
 	return items, nil
 }
 
-const updateEndpointMute = `-- name: UpdateEndpointMute :exec
+const listPlaintextEndpointTokens = `-- name: ListPlaintextEndpointTokens :many
+SELECT id, token, previous_token FROM endpoints
+WHERE token IS NOT NULL
+   OR previous_token IS NOT NULL
+LIMIT $1
+`
+
+type ListPlaintextEndpointTokensRow struct {
+	ID            uuid.UUID
+	Token         *string
+	PreviousToken *string
+}
+
+// 해시로 옮기지 않은 평문 토큰 (backfill용)
+func (q *Queries) ListPlaintextEndpointTokens(ctx context.Context, limit int32) ([]ListPlaintextEndpointTokensRow, error) {
+	rows, err := q.db.Query(ctx, listPlaintextEndpointTokens, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPlaintextEndpointTokensRow
+	for rows.Next() {
+		var i ListPlaintextEndpointTokensRow
+		if err := rows.Scan(&i.ID, &i.Token, &i.PreviousToken); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPlaintextSigningSecrets = `-- name: ListPlaintextSigningSecrets :many
+SELECT id, signing_secret::text AS signing_secret FROM endpoints
+WHERE signing_secret IS NOT NULL
+  AND signing_secret NOT LIKE 'v1.%'
+LIMIT $1
+`
+
+type ListPlaintextSigningSecretsRow struct {
+	ID            uuid.UUID
+	SigningSecret string
+}
+
+// 암호화하지 않은 서명 secret (backfill용). 'v1.'은 token.Sealer의 형식
+func (q *Queries) ListPlaintextSigningSecrets(ctx context.Context, limit int32) ([]ListPlaintextSigningSecretsRow, error) {
+	rows, err := q.db.Query(ctx, listPlaintextSigningSecrets, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPlaintextSigningSecretsRow
+	for rows.Next() {
+		var i ListPlaintextSigningSecretsRow
+		if err := rows.Scan(&i.ID, &i.SigningSecret); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateEndpointToken = `-- name: RotateEndpointToken :one
+WITH rotated AS (
+    UPDATE endpoints
+    SET token_hash = $1,
+        token_prefix = $2,
+        previous_token_hash = CASE WHEN $3::timestamp IS NULL THEN NULL ELSE endpoints.token_hash END,
+        previous_token_expires_at = $3,
+        token = NULL,
+        previous_token = NULL
+    WHERE endpoints.id = $4
+      AND (
+          endpoints.user_id = $5::uuid
+          OR endpoints.org_id IN (
+              SELECT om.org_id FROM organization_members om
+              WHERE om.user_id = $5
+                AND om.role IN ('owner', 'admin')
+          )
+      )
+    RETURNING endpoints.id, endpoints.previous_token_expires_at
+), logged AS (
+    INSERT INTO endpoint_token_rotations (endpoint_id, rotated_by, grace_until)
+    SELECT rotated.id, $5, rotated.previous_token_expires_at
+    FROM rotated
+)
+SELECT id, previous_token_expires_at FROM rotated
+`
+
+type RotateEndpointTokenParams struct {
+	NewTokenHash   *string
+	NewTokenPrefix *string
+	GraceUntil     *time.Time
+	ID             uuid.UUID
+	UserID         uuid.UUID
+}
+
+type RotateEndpointTokenRow struct {
+	ID                     uuid.UUID
+	PreviousTokenExpiresAt *time.Time
+}
+
+// grace_until이 NULL이면 이전 토큰은 즉시 무효. 관리 권한이 없으면 row가 없음
+func (q *Queries) RotateEndpointToken(ctx context.Context, arg RotateEndpointTokenParams) (RotateEndpointTokenRow, error) {
+	row := q.db.QueryRow(ctx, rotateEndpointToken,
+		arg.NewTokenHash,
+		arg.NewTokenPrefix,
+		arg.GraceUntil,
+		arg.ID,
+		arg.UserID,
+	)
+	var i RotateEndpointTokenRow
+	err := row.Scan(&i.ID, &i.PreviousTokenExpiresAt)
+	return i, err
+}
+
+const sealEndpointSigningSecret = `-- name: SealEndpointSigningSecret :exec
 UPDATE endpoints
+SET signing_secret = $1::text
+WHERE id = $2
+  AND signing_secret = $3::text
+`
+
+type SealEndpointSigningSecretParams struct {
+	Sealed    string
+	ID        uuid.UUID
+	Plaintext string
+}
+
+// 그 사이 secret이 다시 발급되었으면 바꾸지 않는다
+func (q *Queries) SealEndpointSigningSecret(ctx context.Context, arg SealEndpointSigningSecretParams) error {
+	_, err := q.db.Exec(ctx, sealEndpointSigningSecret, arg.Sealed, arg.ID, arg.Plaintext)
+	return err
+}
+
+const setEndpointTokenHash = `-- name: SetEndpointTokenHash :exec
+UPDATE endpoints
+SET token_hash = COALESCE($1, token_hash),
+    token_prefix = COALESCE($2, token_prefix),
+    previous_token_hash = COALESCE($3, previous_token_hash),
+    token = NULL,
+    previous_token = NULL
+WHERE id = $4
+`
+
+type SetEndpointTokenHashParams struct {
+	TokenHash         *string
+	TokenPrefix       *string
+	PreviousTokenHash *string
+	ID                uuid.UUID
+}
+
+func (q *Queries) SetEndpointTokenHash(ctx context.Context, arg SetEndpointTokenHashParams) error {
+	_, err := q.db.Exec(ctx, setEndpointTokenHash,
+		arg.TokenHash,
+		arg.TokenPrefix,
+		arg.PreviousTokenHash,
+		arg.ID,
+	)
+	return err
+}
+
+const transferEndpointToOrg = `-- name: TransferEndpointToOrg :one
+WITH moved AS (
+    UPDATE endpoints
+    SET user_id = NULL,
+        org_id = $1
+    WHERE id = $2
+      AND user_id = $3::uuid
+      AND EXISTS (
+          SELECT 1 FROM organization_members om
+          WHERE om.org_id = $1
+            AND om.user_id = $3
+            AND om.role IN ('owner', 'admin')
+      )
+    RETURNING id
+), demoted AS (
+    UPDATE endpoint_members m
+    SET role = 'member'
+    FROM moved
+    WHERE m.endpoint_id = moved.id
+)
+SELECT id FROM moved
+`
+
+type TransferEndpointToOrgParams struct {
+	OrgID  *uuid.UUID
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+// 개인 endpoint를 조직으로 옮긴다. 기존 owner는 member로 남아 계속 알림을 받음.
+// 소유자이면서 대상 조직의 owner/admin이 아니면 row가 없음
+func (q *Queries) TransferEndpointToOrg(ctx context.Context, arg TransferEndpointToOrgParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, transferEndpointToOrg, arg.OrgID, arg.ID, arg.UserID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const unmuteExpiredEndpoints = `-- name: UnmuteExpiredEndpoints :many
+UPDATE endpoint_members m
+SET notification_enabled = true,
+    notification_disabled_at = null,
+    muted_until = null
+FROM endpoints e
+WHERE e.id = m.endpoint_id
+  AND m.notification_enabled = false
+  AND m.muted_until <= now()
+RETURNING m.endpoint_id, m.user_id, e.name
+`
+
+type UnmuteExpiredEndpointsRow struct {
+	EndpointID uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+}
+
+// 기한이 지난 음소거를 해제하고 해제된 멤버를 반환
+func (q *Queries) UnmuteExpiredEndpoints(ctx context.Context) ([]UnmuteExpiredEndpointsRow, error) {
+	rows, err := q.db.Query(ctx, unmuteExpiredEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UnmuteExpiredEndpointsRow
+	for rows.Next() {
+		var i UnmuteExpiredEndpointsRow
+		if err := rows.Scan(&i.EndpointID, &i.UserID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateEndpoint = `-- name: UpdateEndpoint :one
+UPDATE endpoints
+SET name = COALESCE($1::text, name),
+    description = CASE WHEN $2::text IS NULL THEN description ELSE NULLIF($2::text, '') END,
+    icon = CASE WHEN $3::text IS NULL THEN icon ELSE NULLIF($3::text, '') END,
+    color = CASE WHEN $4::text IS NULL THEN color ELSE NULLIF($4::text, '') END,
+    default_ttl = CASE WHEN $5::int IS NULL THEN default_ttl ELSE NULLIF($5::int, 0) END,
+    default_urgency = CASE WHEN $6::text IS NULL THEN default_urgency ELSE NULLIF($6::text, '') END,
+    default_click_url = CASE WHEN $7::text IS NULL THEN default_click_url ELSE NULLIF($7::text, '') END
+WHERE id = $8
+  AND (
+      endpoints.user_id = $9::uuid
+      OR endpoints.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = $9
+            AND om.role IN ('owner', 'admin')
+      )
+  )
+RETURNING id, user_id, name, token, notification_enabled, notification_disabled_at, created_at, previous_token, previous_token_expires_at, description, icon, color, default_ttl, default_urgency, default_click_url, allowed_cidrs, ask_enabled, max_ask_timeout, max_body_size, expires_at, signing_secret, signing_required, token_hash, token_prefix, previous_token_hash, org_id
+`
+
+type UpdateEndpointParams struct {
+	Name            *string
+	Description     *string
+	Icon            *string
+	Color           *string
+	DefaultTtl      *int32
+	DefaultUrgency  *string
+	DefaultClickUrl *string
+	ID              uuid.UUID
+	UserID          uuid.UUID
+}
+
+// NULL인 항목은 변경하지 않음. 빈 문자열이나 0은 설정 해제. 관리 권한이 없으면 row가 없음
+func (q *Queries) UpdateEndpoint(ctx context.Context, arg UpdateEndpointParams) (Endpoint, error) {
+	row := q.db.QueryRow(ctx, updateEndpoint,
+		arg.Name,
+		arg.Description,
+		arg.Icon,
+		arg.Color,
+		arg.DefaultTtl,
+		arg.DefaultUrgency,
+		arg.DefaultClickUrl,
+		arg.ID,
+		arg.UserID,
+	)
+	var i Endpoint
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Token,
+		&i.NotificationEnabled,
+		&i.NotificationDisabledAt,
+		&i.CreatedAt,
+		&i.PreviousToken,
+		&i.PreviousTokenExpiresAt,
+		&i.Description,
+		&i.Icon,
+		&i.Color,
+		&i.DefaultTtl,
+		&i.DefaultUrgency,
+		&i.DefaultClickUrl,
+		&i.AllowedCidrs,
+		&i.AskEnabled,
+		&i.MaxAskTimeout,
+		&i.MaxBodySize,
+		&i.ExpiresAt,
+		&i.SigningSecret,
+		&i.SigningRequired,
+		&i.TokenHash,
+		&i.TokenPrefix,
+		&i.PreviousTokenHash,
+		&i.OrgID,
+	)
+	return i, err
+}
+
+const updateEndpointMute = `-- name: UpdateEndpointMute :execrows
+UPDATE endpoint_members
 SET notification_enabled = false, 
-  notification_disabled_at = $2
-WHERE token = $1
+  notification_disabled_at = $2,
+  muted_until = $4
+WHERE endpoint_id = $1
+  AND user_id = $3
 `
 
 type UpdateEndpointMuteParams struct {
-	Token                  string
+	EndpointID             uuid.UUID
 	NotificationDisabledAt *time.Time
+	UserID                 uuid.UUID
+	MutedUntil             *time.Time
 }
 
-func (q *Queries) UpdateEndpointMute(ctx context.Context, arg UpdateEndpointMuteParams) error {
-	_, err := q.db.Exec(ctx, updateEndpointMute, arg.Token, arg.NotificationDisabledAt)
-	return err
+// muted_until이 NULL이면 직접 해제할 때까지 음소거
+func (q *Queries) UpdateEndpointMute(ctx context.Context, arg UpdateEndpointMuteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateEndpointMute,
+		arg.EndpointID,
+		arg.NotificationDisabledAt,
+		arg.UserID,
+		arg.MutedUntil,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const updateEndpointUnmute = `-- name: UpdateEndpointUnmute :exec
+const updateEndpointPolicy = `-- name: UpdateEndpointPolicy :one
 UPDATE endpoints
+SET allowed_cidrs = $1::text[],
+    ask_enabled = $2,
+    max_ask_timeout = $3,
+    max_body_size = $4,
+    expires_at = $5
+WHERE id = $6
+  AND (
+      endpoints.user_id = $7::uuid
+      OR endpoints.org_id IN (
+          SELECT om.org_id FROM organization_members om
+          WHERE om.user_id = $7
+            AND om.role IN ('owner', 'admin')
+      )
+  )
+RETURNING id, user_id, name, token, notification_enabled, notification_disabled_at, created_at, previous_token, previous_token_expires_at, description, icon, color, default_ttl, default_urgency, default_click_url, allowed_cidrs, ask_enabled, max_ask_timeout, max_body_size, expires_at, signing_secret, signing_required, token_hash, token_prefix, previous_token_hash, org_id
+`
+
+type UpdateEndpointPolicyParams struct {
+	AllowedCidrs  []string
+	AskEnabled    bool
+	MaxAskTimeout *int32
+	MaxBodySize   *int32
+	ExpiresAt     *time.Time
+	ID            uuid.UUID
+	UserID        uuid.UUID
+}
+
+// 관리 권한이 없으면 row가 없음
+func (q *Queries) UpdateEndpointPolicy(ctx context.Context, arg UpdateEndpointPolicyParams) (Endpoint, error) {
+	row := q.db.QueryRow(ctx, updateEndpointPolicy,
+		arg.AllowedCidrs,
+		arg.AskEnabled,
+		arg.MaxAskTimeout,
+		arg.MaxBodySize,
+		arg.ExpiresAt,
+		arg.ID,
+		arg.UserID,
+	)
+	var i Endpoint
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Token,
+		&i.NotificationEnabled,
+		&i.NotificationDisabledAt,
+		&i.CreatedAt,
+		&i.PreviousToken,
+		&i.PreviousTokenExpiresAt,
+		&i.Description,
+		&i.Icon,
+		&i.Color,
+		&i.DefaultTtl,
+		&i.DefaultUrgency,
+		&i.DefaultClickUrl,
+		&i.AllowedCidrs,
+		&i.AskEnabled,
+		&i.MaxAskTimeout,
+		&i.MaxBodySize,
+		&i.ExpiresAt,
+		&i.SigningSecret,
+		&i.SigningRequired,
+		&i.TokenHash,
+		&i.TokenPrefix,
+		&i.PreviousTokenHash,
+		&i.OrgID,
+	)
+	return i, err
+}
+
+const updateEndpointUnmute = `-- name: UpdateEndpointUnmute :execrows
+UPDATE endpoint_members
 SET notification_enabled = true, 
-  notification_disabled_at = null
-WHERE token = $1
+  notification_disabled_at = null,
+  muted_until = null
+WHERE endpoint_id = $1
+  AND user_id = $2
 `
 
-func (q *Queries) UpdateEndpointUnmute(ctx context.Context, token string) error {
-	_, err := q.db.Exec(ctx, updateEndpointUnmute, token)
-	return err
+type UpdateEndpointUnmuteParams struct {
+	EndpointID uuid.UUID
+	UserID     uuid.UUID
+}
+
+func (q *Queries) UpdateEndpointUnmute(ctx context.Context, arg UpdateEndpointUnmuteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateEndpointUnmute, arg.EndpointID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
@@ -12,27 +12,154 @@ import (
 
 type Endpoint struct {
 	ID                     uuid.UUID
-	UserID                 uuid.UUID
+	UserID                 *uuid.UUID
 	Name                   string
-	Token                  string
+	Token                  *string
+	NotificationEnabled    bool
+	NotificationDisabledAt *time.Time
+	CreatedAt              time.Time
+	PreviousToken          *string
+	PreviousTokenExpiresAt *time.Time
+	Description            *string
+	Icon                   *string
+	Color                  *string
+	DefaultTtl             *int32
+	DefaultUrgency         *string
+	DefaultClickUrl        *string
+	AllowedCidrs           []string
+	AskEnabled             bool
+	MaxAskTimeout          *int32
+	MaxBodySize            *int32
+	ExpiresAt              *time.Time
+	SigningSecret          *string
+	SigningRequired        bool
+	TokenHash              *string
+	TokenPrefix            *string
+	PreviousTokenHash      *string
+	OrgID                  *uuid.UUID
+}
+
+type EndpointInvite struct {
+	ID         uuid.UUID
+	EndpointID uuid.UUID
+	CodeHash   string
+	CodePrefix string
+	CreatedBy  *uuid.UUID
+	ExpiresAt  time.Time
+	MaxUses    *int32
+	UseCount   int32
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+type EndpointMember struct {
+	EndpointID             uuid.UUID
+	UserID                 uuid.UUID
+	Role                   string
 	NotificationEnabled    bool
 	NotificationDisabledAt *time.Time
 	CreatedAt              time.Time
+	MutedUntil             *time.Time
+}
+
+type EndpointSenderKey struct {
+	ID         uuid.UUID
+	EndpointID uuid.UUID
+	Label      string
+	KeyHash    string
+	KeyPrefix  string
+	Scopes     []string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+type EndpointTokenRotation struct {
+	ID         uuid.UUID
+	EndpointID uuid.UUID
+	RotatedBy  *uuid.UUID
+	GraceUntil *time.Time
+	CreatedAt  time.Time
 }
 
 type Notification struct {
-	ID           uuid.UUID
-	EndpointID   *uuid.UUID
-	EndpointName string
-	UserID       uuid.UUID
-	Body         string
-	Actions      []string
-	Reaction     *string
-	ReactionAt   *time.Time
-	Status       *string
-	ReadAt       *time.Time
-	IsDeleted    bool
-	CreatedAt    time.Time
+	ID                  uuid.UUID
+	EndpointID          *uuid.UUID
+	EndpointName        string
+	UserID              uuid.UUID
+	Body                string
+	Actions             []string
+	Reaction            *string
+	ReactionAt          *time.Time
+	Status              *string
+	ReadAt              *time.Time
+	IsDeleted           bool
+	CreatedAt           time.Time
+	Sensitive           bool
+	ReactionAuthMethod  *string
+	ReactionAuthAt      *time.Time
+	ReactionComment     *string
+	ReactionUserID      *uuid.UUID
+	ReactionPushTokenID *uuid.UUID
+	ReactionIp          *string
+	ReactionUserAgent   *string
+	SenderKeyID         *uuid.UUID
+	SenderKeyLabel      *string
+	MessageID           uuid.UUID
+	SearchVector        interface{}
+	DeletedAt           *time.Time
+	ChangeSeq           int64
+	CreatedSeq          int64
+	ChangeXid           uint64
+	CreatedXid          uint64
+	Delivery            *string
+}
+
+type NotificationReaction struct {
+	ID             uuid.UUID
+	NotificationID *uuid.UUID
+	Reaction       string
+	Comment        *string
+	UserID         *uuid.UUID
+	PushTokenID    *uuid.UUID
+	Ip             *string
+	UserAgent      *string
+	AuthMethod     *string
+	AuthAt         *time.Time
+	CreatedAt      time.Time
+	MessageID      uuid.UUID
+	EndpointID     *uuid.UUID
+}
+
+type NotificationTombstone struct {
+	NotificationID uuid.UUID
+	UserID         uuid.UUID
+	ChangeSeq      int64
+	DeletedAt      time.Time
+	ChangeXid      uint64
+}
+
+type Organization struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+}
+
+type OrganizationInvite struct {
+	ID        uuid.UUID
+	OrgID     uuid.UUID
+	Email     string
+	Role      string
+	InvitedBy *uuid.UUID
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+type OrganizationMember struct {
+	OrgID     uuid.UUID
+	UserID    uuid.UUID
+	Role      string
+	CreatedAt time.Time
 }
 
 type PushToken struct {
@@ -45,6 +172,15 @@ type PushToken struct {
 	CreatedAt time.Time
 }
 
+type RetentionPolicy struct {
+	ID         uuid.UUID
+	UserID     *uuid.UUID
+	EndpointID *uuid.UUID
+	KeepDays   *int32
+	KeepCount  *int32
+	UpdatedAt  time.Time
+}
+
 type User struct {
 	ID          uuid.UUID
 	Email       *string
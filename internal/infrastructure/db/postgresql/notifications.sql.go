@@ -12,7 +12,56 @@ import (
 	"github.com/google/uuid"
 )
 
-const createNotification = `-- name: CreateNotification :one
+const countActiveNotificationsByMessageID = `-- name: CountActiveNotificationsByMessageID :one
+SELECT count(*) FROM notifications
+WHERE message_id = $1
+  AND is_deleted = false
+`
+
+func (q *Queries) CountActiveNotificationsByMessageID(ctx context.Context, messageID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveNotificationsByMessageID, messageID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUnreadNotifications = `-- name: CountUnreadNotifications :many
+SELECT user_id, endpoint_id, count(*) AS count
+FROM notifications
+WHERE user_id = ANY($1::uuid[])
+  AND read_at IS NULL
+  AND is_deleted = false
+GROUP BY user_id, endpoint_id
+`
+
+type CountUnreadNotificationsRow struct {
+	UserID     uuid.UUID
+	EndpointID *uuid.UUID
+	Count      int64
+}
+
+// 여러 유저를 한 번에 집계 (발송 시 멤버 전체). endpoint가 삭제된 알림은 endpoint_id가 NULL
+func (q *Queries) CountUnreadNotifications(ctx context.Context, userIds []uuid.UUID) ([]CountUnreadNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, countUnreadNotifications, userIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountUnreadNotificationsRow
+	for rows.Next() {
+		var i CountUnreadNotificationsRow
+		if err := rows.Scan(&i.UserID, &i.EndpointID, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createNotifications = `-- name: CreateNotifications :many
 INSERT INTO notifications (
     endpoint_id,
     endpoint_name,
@@ -20,65 +69,146 @@ INSERT INTO notifications (
     body,
     actions,
     status,
-    read_at
+    read_at,
+    sensitive,
+    sender_key_id,
+    sender_key_label,
+    message_id,
+    delivery
 )
 SELECT
     e.id,
     e.name,
+    m.user_id,
     $1,
     $2,
+    CASE WHEN m.notification_enabled OR m.muted_until <= now() THEN 'pending' ELSE 'mute' END,
+    CASE WHEN m.notification_enabled OR m.muted_until <= now() THEN NULL ELSE now() END,
     $3,
+    $4,
     $5,
-    $6
+    $6,
+    CASE WHEN m.notification_enabled OR m.muted_until <= now() THEN NULL ELSE 'mute' END
 FROM endpoints e
-WHERE e.id = $4
-RETURNING id, endpoint_id, body, actions, created_at, status, read_at
+JOIN endpoint_members m ON m.endpoint_id = e.id
+WHERE e.id = $7
+RETURNING id, endpoint_id, user_id, body, actions, created_at, status, read_at, sensitive, sender_key_id, sender_key_label, message_id
 `
 
-type CreateNotificationParams struct {
-	UserID  uuid.UUID
-	Body    string
-	Actions []string
-	ID      uuid.UUID
-	Status  *string
-	ReadAt  *time.Time
+type CreateNotificationsParams struct {
+	Body           string
+	Actions        []string
+	Sensitive      bool
+	SenderKeyID    *uuid.UUID
+	SenderKeyLabel *string
+	MessageID      uuid.UUID
+	EndpointID     uuid.UUID
 }
 
-type CreateNotificationRow struct {
-	ID         uuid.UUID
-	EndpointID *uuid.UUID
-	Body       string
-	Actions    []string
-	CreatedAt  time.Time
-	Status     *string
-	ReadAt     *time.Time
+type CreateNotificationsRow struct {
+	ID             uuid.UUID
+	EndpointID     *uuid.UUID
+	UserID         uuid.UUID
+	Body           string
+	Actions        []string
+	CreatedAt      time.Time
+	Status         *string
+	ReadAt         *time.Time
+	Sensitive      bool
+	SenderKeyID    *uuid.UUID
+	SenderKeyLabel *string
+	MessageID      uuid.UUID
 }
 
-func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) (CreateNotificationRow, error) {
-	row := q.db.QueryRow(ctx, createNotification,
-		arg.UserID,
+// endpoint 멤버마다 한 row. 음소거한 멤버의 알림은 mute 상태로 읽음 처리.
+// 기한이 지난 음소거는 해제 작업 전이라도 음소거로 취급하지 않는다
+func (q *Queries) CreateNotifications(ctx context.Context, arg CreateNotificationsParams) ([]CreateNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, createNotifications,
 		arg.Body,
 		arg.Actions,
-		arg.ID,
-		arg.Status,
-		arg.ReadAt,
+		arg.Sensitive,
+		arg.SenderKeyID,
+		arg.SenderKeyLabel,
+		arg.MessageID,
+		arg.EndpointID,
 	)
-	var i CreateNotificationRow
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CreateNotificationsRow
+	for rows.Next() {
+		var i CreateNotificationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EndpointID,
+			&i.UserID,
+			&i.Body,
+			&i.Actions,
+			&i.CreatedAt,
+			&i.Status,
+			&i.ReadAt,
+			&i.Sensitive,
+			&i.SenderKeyID,
+			&i.SenderKeyLabel,
+			&i.MessageID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findNotificationByID = `-- name: FindNotificationByID :one
+SELECT id, endpoint_id, endpoint_name, user_id, body, actions, reaction, reaction_at, status, read_at, is_deleted, created_at, sensitive, reaction_auth_method, reaction_auth_at, reaction_comment, reaction_user_id, reaction_push_token_id, reaction_ip, reaction_user_agent, sender_key_id, sender_key_label, message_id, search_vector, deleted_at, change_seq, created_seq, change_xid, created_xid, delivery FROM notifications
+WHERE id = $1
+`
+
+func (q *Queries) FindNotificationByID(ctx context.Context, id uuid.UUID) (Notification, error) {
+	row := q.db.QueryRow(ctx, findNotificationByID, id)
+	var i Notification
 	err := row.Scan(
 		&i.ID,
 		&i.EndpointID,
+		&i.EndpointName,
+		&i.UserID,
 		&i.Body,
 		&i.Actions,
-		&i.CreatedAt,
+		&i.Reaction,
+		&i.ReactionAt,
 		&i.Status,
 		&i.ReadAt,
+		&i.IsDeleted,
+		&i.CreatedAt,
+		&i.Sensitive,
+		&i.ReactionAuthMethod,
+		&i.ReactionAuthAt,
+		&i.ReactionComment,
+		&i.ReactionUserID,
+		&i.ReactionPushTokenID,
+		&i.ReactionIp,
+		&i.ReactionUserAgent,
+		&i.SenderKeyID,
+		&i.SenderKeyLabel,
+		&i.MessageID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.ChangeSeq,
+		&i.CreatedSeq,
+		&i.ChangeXid,
+		&i.CreatedXid,
+		&i.Delivery,
 	)
 	return i, err
 }
 
 const findNotificationByUserID = `-- name: FindNotificationByUserID :many
 SELECT 
-    n.id, n.endpoint_id, n.endpoint_name, n.user_id, n.body, n.actions, n.reaction, n.reaction_at, n.status, n.read_at, n.is_deleted, n.created_at,
+    n.id, n.endpoint_id, n.endpoint_name, n.user_id, n.body, n.actions, n.reaction, n.reaction_at, n.status, n.read_at, n.is_deleted, n.created_at, n.sensitive, n.reaction_auth_method, n.reaction_auth_at, n.reaction_comment, n.reaction_user_id, n.reaction_push_token_id, n.reaction_ip, n.reaction_user_agent, n.sender_key_id, n.sender_key_label, n.message_id, n.search_vector, n.deleted_at, n.change_seq, n.created_seq, n.change_xid, n.created_xid, n.delivery,
     e.name as endpoint_name
 FROM notifications n
 JOIN endpoints e ON n.endpoint_id = e.id
@@ -86,19 +216,37 @@ WHERE n.user_id = $1
 `
 
 type FindNotificationByUserIDRow struct {
-	ID             uuid.UUID
-	EndpointID     *uuid.UUID
-	EndpointName   string
-	UserID         uuid.UUID
-	Body           string
-	Actions        []string
-	Reaction       *string
-	ReactionAt     *time.Time
-	Status         *string
-	ReadAt         *time.Time
-	IsDeleted      bool
-	CreatedAt      time.Time
-	EndpointName_2 string
+	ID                  uuid.UUID
+	EndpointID          *uuid.UUID
+	EndpointName        string
+	UserID              uuid.UUID
+	Body                string
+	Actions             []string
+	Reaction            *string
+	ReactionAt          *time.Time
+	Status              *string
+	ReadAt              *time.Time
+	IsDeleted           bool
+	CreatedAt           time.Time
+	Sensitive           bool
+	ReactionAuthMethod  *string
+	ReactionAuthAt      *time.Time
+	ReactionComment     *string
+	ReactionUserID      *uuid.UUID
+	ReactionPushTokenID *uuid.UUID
+	ReactionIp          *string
+	ReactionUserAgent   *string
+	SenderKeyID         *uuid.UUID
+	SenderKeyLabel      *string
+	MessageID           uuid.UUID
+	SearchVector        interface{}
+	DeletedAt           *time.Time
+	ChangeSeq           int64
+	CreatedSeq          int64
+	ChangeXid           uint64
+	CreatedXid          uint64
+	Delivery            *string
+	EndpointName_2      string
 }
 
 func (q *Queries) FindNotificationByUserID(ctx context.Context, userID uuid.UUID) ([]FindNotificationByUserIDRow, error) {
@@ -123,6 +271,24 @@ func (q *Queries) FindNotificationByUserID(ctx context.Context, userID uuid.UUID
 			&i.ReadAt,
 			&i.IsDeleted,
 			&i.CreatedAt,
+			&i.Sensitive,
+			&i.ReactionAuthMethod,
+			&i.ReactionAuthAt,
+			&i.ReactionComment,
+			&i.ReactionUserID,
+			&i.ReactionPushTokenID,
+			&i.ReactionIp,
+			&i.ReactionUserAgent,
+			&i.SenderKeyID,
+			&i.SenderKeyLabel,
+			&i.MessageID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.ChangeSeq,
+			&i.CreatedSeq,
+			&i.ChangeXid,
+			&i.CreatedXid,
+			&i.Delivery,
 			&i.EndpointName_2,
 		); err != nil {
 			return nil, err
@@ -135,6 +301,54 @@ func (q *Queries) FindNotificationByUserID(ctx context.Context, userID uuid.UUID
 	return items, nil
 }
 
+const findReactionsByNotificationID = `-- name: FindReactionsByNotificationID :many
+SELECT r.id, r.notification_id, r.reaction, r.comment, r.user_id, r.push_token_id, r.ip, r.user_agent, r.auth_method, r.auth_at, r.created_at, r.message_id, r.endpoint_id FROM notification_reactions r
+JOIN notifications n ON n.message_id = r.message_id
+WHERE n.id = $1
+  AND n.user_id = $2
+ORDER BY r.id
+`
+
+type FindReactionsByNotificationIDParams struct {
+	NotificationID uuid.UUID
+	UserID         uuid.UUID
+}
+
+// 다른 멤버가 응답한 이력도 같은 발송이면 포함. 응답한 알림이 영구 삭제된 이력도 남아있다
+func (q *Queries) FindReactionsByNotificationID(ctx context.Context, arg FindReactionsByNotificationIDParams) ([]NotificationReaction, error) {
+	rows, err := q.db.Query(ctx, findReactionsByNotificationID, arg.NotificationID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationReaction
+	for rows.Next() {
+		var i NotificationReaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.NotificationID,
+			&i.Reaction,
+			&i.Comment,
+			&i.UserID,
+			&i.PushTokenID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.AuthMethod,
+			&i.AuthAt,
+			&i.CreatedAt,
+			&i.MessageID,
+			&i.EndpointID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNotificationsWithCursor = `-- name: GetNotificationsWithCursor :many
 SELECT 
     n.id,
@@ -147,42 +361,71 @@ SELECT
     n.endpoint_name,
     n.actions,
     n.reaction,
-    n.reaction_at
+    n.reaction_at,
+    n.sensitive,
+    n.reaction_comment,
+    n.sender_key_id,
+    n.sender_key_label
 FROM notifications n
 WHERE n.user_id = $1 
   AND n.is_deleted = false
-  AND ($3::uuid IS NULL OR n.endpoint_id = $3)
-  AND ($4::uuid IS NULL OR n.id < $4)
+  AND ($1::uuid IS NULL OR n.endpoint_id = $1)
+  AND ($2::uuid IS NULL OR n.id < $2)
   AND (
-    $5::text IS NULL 
-    OR n.body ILIKE '%' || $5 || '%'
-)
+    $3::text IS NULL
+    OR n.search_vector @@ websearch_to_tsquery('simple', $3)
+    OR ($4::text IS NOT NULL AND n.body ILIKE '%' || $4 || '%')
+  )
+  AND ($5::text[] IS NULL OR n.status = ANY($5::text[]))
+  AND (NOT $6::bool OR n.read_at IS NULL)
+  AND (NOT $7::bool OR n.actions IS NOT NULL)
+  AND (
+    NOT $8::bool
+    OR (n.actions IS NOT NULL AND n.reaction IS NULL AND n.status NOT IN ('timeout_reply', 'cancelled'))
+  )
+  AND ($9::timestamp IS NULL OR n.created_at >= $9)
+  AND ($10::timestamp IS NULL OR n.created_at < $10)
+  AND ($11::text IS NULL OR n.reaction = $11)
 ORDER BY n.id DESC
 LIMIT $2
 `
 
 type GetNotificationsWithCursorParams struct {
-	UserID     uuid.UUID
-	Limit      int32
-	EndpointID *uuid.UUID
-	LastID     *uuid.UUID
-	Query      *string
+	UserID      uuid.UUID
+	Limit       int32
+	EndpointID  *uuid.UUID
+	LastID      *uuid.UUID
+	Query       *string
+	Substring   *string
+	Statuses    []string
+	UnreadOnly  bool
+	HasActions  bool
+	PendingAsks bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Reaction    *string
 }
 
 type GetNotificationsWithCursorRow struct {
-	ID           uuid.UUID
-	EndpointID   *uuid.UUID
-	UserID       uuid.UUID
-	Body         string
-	Status       *string
-	ReadAt       *time.Time
-	CreatedAt    time.Time
-	EndpointName string
-	Actions      []string
-	Reaction     *string
-	ReactionAt   *time.Time
+	ID              uuid.UUID
+	EndpointID      *uuid.UUID
+	UserID          uuid.UUID
+	Body            string
+	Status          *string
+	ReadAt          *time.Time
+	CreatedAt       time.Time
+	EndpointName    string
+	Actions         []string
+	Reaction        *string
+	ReactionAt      *time.Time
+	Sensitive       bool
+	ReactionComment *string
+	SenderKeyID     *uuid.UUID
+	SenderKeyLabel  *string
 }
 
+// 필터는 모두 AND로 조합. created_to는 포함하지 않는다.
+// query는 검색과 같이 tsvector로 찾고, substring이 있으면 부분 일치도 포함한다 (trigram 인덱스)
 func (q *Queries) GetNotificationsWithCursor(ctx context.Context, arg GetNotificationsWithCursorParams) ([]GetNotificationsWithCursorRow, error) {
 	rows, err := q.db.Query(ctx, getNotificationsWithCursor,
 		arg.UserID,
@@ -190,6 +433,14 @@ func (q *Queries) GetNotificationsWithCursor(ctx context.Context, arg GetNotific
 		arg.EndpointID,
 		arg.LastID,
 		arg.Query,
+		arg.Substring,
+		arg.Statuses,
+		arg.UnreadOnly,
+		arg.HasActions,
+		arg.PendingAsks,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Reaction,
 	)
 	if err != nil {
 		return nil, err
@@ -210,6 +461,10 @@ func (q *Queries) GetNotificationsWithCursor(ctx context.Context, arg GetNotific
 			&i.Actions,
 			&i.Reaction,
 			&i.ReactionAt,
+			&i.Sensitive,
+			&i.ReactionComment,
+			&i.SenderKeyID,
+			&i.SenderKeyLabel,
 		); err != nil {
 			return nil, err
 		}
@@ -221,11 +476,369 @@ func (q *Queries) GetNotificationsWithCursor(ctx context.Context, arg GetNotific
 	return items, nil
 }
 
+const listDeletedNotifications = `-- name: ListDeletedNotifications :many
+SELECT
+    n.id,
+    n.endpoint_id,
+    n.user_id,
+    n.body,
+    n.status,
+    n.read_at,
+    n.created_at,
+    n.endpoint_name,
+    n.actions,
+    n.reaction,
+    n.reaction_at,
+    n.sensitive,
+    n.reaction_comment,
+    n.sender_key_id,
+    n.sender_key_label,
+    n.deleted_at
+FROM notifications n
+WHERE n.user_id = $1
+  AND n.is_deleted = true
+  AND ($2::uuid IS NULL OR n.id < $2)
+ORDER BY n.id DESC
+LIMIT $3
+`
+
+type ListDeletedNotificationsParams struct {
+	UserID uuid.UUID
+	LastID *uuid.UUID
+	Limit  int32
+}
+
+type ListDeletedNotificationsRow struct {
+	ID              uuid.UUID
+	EndpointID      *uuid.UUID
+	UserID          uuid.UUID
+	Body            string
+	Status          *string
+	ReadAt          *time.Time
+	CreatedAt       time.Time
+	EndpointName    string
+	Actions         []string
+	Reaction        *string
+	ReactionAt      *time.Time
+	Sensitive       bool
+	ReactionComment *string
+	SenderKeyID     *uuid.UUID
+	SenderKeyLabel  *string
+	DeletedAt       *time.Time
+}
+
+// 휴지통. 목록과 같은 id 내림차순 커서
+func (q *Queries) ListDeletedNotifications(ctx context.Context, arg ListDeletedNotificationsParams) ([]ListDeletedNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, listDeletedNotifications, arg.UserID, arg.LastID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDeletedNotificationsRow
+	for rows.Next() {
+		var i ListDeletedNotificationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EndpointID,
+			&i.UserID,
+			&i.Body,
+			&i.Status,
+			&i.ReadAt,
+			&i.CreatedAt,
+			&i.EndpointName,
+			&i.Actions,
+			&i.Reaction,
+			&i.ReactionAt,
+			&i.Sensitive,
+			&i.ReactionComment,
+			&i.SenderKeyID,
+			&i.SenderKeyLabel,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEndpointFeed = `-- name: ListEndpointFeed :many
+SELECT feed.kind, feed.id, feed.message_id, feed.body, feed.actions, feed.sensitive, feed.reaction, feed.comment, feed.at
+FROM (
+    (
+        SELECT DISTINCT ON (n.message_id)
+            'message'::text AS kind,
+            n.message_id AS id,
+            n.message_id,
+            n.body,
+            n.actions,
+            n.sensitive,
+            NULL::text AS reaction,
+            NULL::text AS comment,
+            n.created_at AS at
+        FROM notifications n
+        WHERE n.endpoint_id = $1
+          AND (n.created_at, n.message_id) > ($2::timestamp, $3::uuid)
+          AND n.created_at < clock_timestamp()::timestamp - interval '10 seconds'
+        ORDER BY n.message_id
+    )
+    UNION ALL
+    (
+        SELECT
+            'reaction'::text AS kind,
+            r.id,
+            r.message_id,
+            coalesce(n.body, '') AS body,
+            n.actions,
+            coalesce(n.sensitive, false) AS sensitive,
+            r.reaction,
+            r.comment,
+            r.created_at AS at
+        FROM notification_reactions r
+        LEFT JOIN LATERAL (
+            SELECT m.body, m.actions, m.sensitive FROM notifications m
+            WHERE m.message_id = r.message_id
+            LIMIT 1
+        ) n ON true
+        WHERE r.endpoint_id = $1
+          AND (r.created_at, r.id) > ($2::timestamp, $3::uuid)
+          AND r.created_at < clock_timestamp()::timestamp - interval '10 seconds'
+    )
+) feed
+ORDER BY feed.at, feed.id
+LIMIT $4
+`
+
+type ListEndpointFeedParams struct {
+	EndpointID *uuid.UUID
+	SinceAt    time.Time
+	SinceID    uuid.UUID
+	Limit      int32
+}
+
+type ListEndpointFeedRow struct {
+	Kind      string
+	ID        uuid.UUID
+	MessageID uuid.UUID
+	Body      string
+	Actions   []string
+	Sensitive bool
+	Reaction  *string
+	Comment   *string
+	At        time.Time
+}
+
+// endpoint로 온 발송(멤버별 알림 중 하나)과 응답 이력을 (at, id) 순으로. 휴지통으로 옮긴 알림도 포함.
+// 알림이 영구 삭제돼 notification_id가 비어도 응답 이력은 endpoint_id로 찾고, 본문은 남은 알림이 있을 때만 채운다.
+// at은 트랜잭션 시작 시각이라 늦게 커밋된 행이 이미 지나간 커서 앞에 끼어들 수 있으므로 10초가 지난 행만 내려준다
+func (q *Queries) ListEndpointFeed(ctx context.Context, arg ListEndpointFeedParams) ([]ListEndpointFeedRow, error) {
+	rows, err := q.db.Query(ctx, listEndpointFeed,
+		arg.EndpointID,
+		arg.SinceAt,
+		arg.SinceID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEndpointFeedRow
+	for rows.Next() {
+		var i ListEndpointFeedRow
+		if err := rows.Scan(
+			&i.Kind,
+			&i.ID,
+			&i.MessageID,
+			&i.Body,
+			&i.Actions,
+			&i.Sensitive,
+			&i.Reaction,
+			&i.Comment,
+			&i.At,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationChanges = `-- name: ListNotificationChanges :many
+SELECT
+    n.id,
+    n.endpoint_id,
+    n.user_id,
+    n.body,
+    n.status,
+    n.read_at,
+    n.created_at,
+    n.endpoint_name,
+    n.actions,
+    n.reaction,
+    n.reaction_at,
+    n.sensitive,
+    n.reaction_comment,
+    n.sender_key_id,
+    n.sender_key_label,
+    n.is_deleted,
+    n.deleted_at,
+    n.change_seq,
+    n.created_seq,
+    n.change_xid,
+    n.created_xid
+FROM notifications n
+WHERE n.user_id = $1
+  AND (n.change_xid, n.change_seq) > ($2::xid8, $3::bigint)
+  AND n.change_xid < $4::xid8
+ORDER BY n.change_xid, n.change_seq
+LIMIT $5
+`
+
+type ListNotificationChangesParams struct {
+	UserID   uuid.UUID
+	SinceXid uint64
+	SinceSeq int64
+	Horizon  uint64
+	Limit    int32
+}
+
+type ListNotificationChangesRow struct {
+	ID              uuid.UUID
+	EndpointID      *uuid.UUID
+	UserID          uuid.UUID
+	Body            string
+	Status          *string
+	ReadAt          *time.Time
+	CreatedAt       time.Time
+	EndpointName    string
+	Actions         []string
+	Reaction        *string
+	ReactionAt      *time.Time
+	Sensitive       bool
+	ReactionComment *string
+	SenderKeyID     *uuid.UUID
+	SenderKeyLabel  *string
+	IsDeleted       bool
+	DeletedAt       *time.Time
+	ChangeSeq       int64
+	CreatedSeq      int64
+	ChangeXid       uint64
+	CreatedXid      uint64
+}
+
+// 변경 피드. since 이후 만들어지거나 바뀐 알림 (휴지통으로 옮긴 것 포함)을 (change_xid, change_seq) 순으로
+func (q *Queries) ListNotificationChanges(ctx context.Context, arg ListNotificationChangesParams) ([]ListNotificationChangesRow, error) {
+	rows, err := q.db.Query(ctx, listNotificationChanges,
+		arg.UserID,
+		arg.SinceXid,
+		arg.SinceSeq,
+		arg.Horizon,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNotificationChangesRow
+	for rows.Next() {
+		var i ListNotificationChangesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EndpointID,
+			&i.UserID,
+			&i.Body,
+			&i.Status,
+			&i.ReadAt,
+			&i.CreatedAt,
+			&i.EndpointName,
+			&i.Actions,
+			&i.Reaction,
+			&i.ReactionAt,
+			&i.Sensitive,
+			&i.ReactionComment,
+			&i.SenderKeyID,
+			&i.SenderKeyLabel,
+			&i.IsDeleted,
+			&i.DeletedAt,
+			&i.ChangeSeq,
+			&i.CreatedSeq,
+			&i.ChangeXid,
+			&i.CreatedXid,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationTombstones = `-- name: ListNotificationTombstones :many
+SELECT notification_id, change_seq, change_xid
+FROM notification_tombstones
+WHERE user_id = $1
+  AND (change_xid, change_seq) > ($2::xid8, $3::bigint)
+  AND change_xid < $4::xid8
+ORDER BY change_xid, change_seq
+LIMIT $5
+`
+
+type ListNotificationTombstonesParams struct {
+	UserID   uuid.UUID
+	SinceXid uint64
+	SinceSeq int64
+	Horizon  uint64
+	Limit    int32
+}
+
+type ListNotificationTombstonesRow struct {
+	NotificationID uuid.UUID
+	ChangeSeq      int64
+	ChangeXid      uint64
+}
+
+// 변경 피드. since 이후 영구 삭제된 알림
+func (q *Queries) ListNotificationTombstones(ctx context.Context, arg ListNotificationTombstonesParams) ([]ListNotificationTombstonesRow, error) {
+	rows, err := q.db.Query(ctx, listNotificationTombstones,
+		arg.UserID,
+		arg.SinceXid,
+		arg.SinceSeq,
+		arg.Horizon,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNotificationTombstonesRow
+	for rows.Next() {
+		var i ListNotificationTombstonesRow
+		if err := rows.Scan(&i.NotificationID, &i.ChangeSeq, &i.ChangeXid); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const markDeleteNotificationByID = `-- name: MarkDeleteNotificationByID :exec
 UPDATE notifications
-SET is_deleted = true
+SET is_deleted = true,
+    deleted_at = now()
 WHERE user_id = $1
   AND id = $2
+  AND is_deleted = false
 `
 
 type MarkDeleteNotificationByIDParams struct {
@@ -238,6 +851,114 @@ func (q *Queries) MarkDeleteNotificationByID(ctx context.Context, arg MarkDelete
 	return err
 }
 
+const markDeleteNotifications = `-- name: MarkDeleteNotifications :many
+UPDATE notifications
+SET is_deleted = true,
+    deleted_at = now()
+WHERE user_id = $1
+  AND id = ANY($2::uuid[])
+  AND is_deleted = false
+RETURNING message_id
+`
+
+type MarkDeleteNotificationsParams struct {
+	UserID uuid.UUID
+	Ids    []uuid.UUID
+}
+
+// 대기 중인 ask를 끝낼지 확인하도록 지운 알림의 message_id를 반환
+func (q *Queries) MarkDeleteNotifications(ctx context.Context, arg MarkDeleteNotificationsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, markDeleteNotifications, arg.UserID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var message_id uuid.UUID
+		if err := rows.Scan(&message_id); err != nil {
+			return nil, err
+		}
+		items = append(items, message_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markDeleteNotificationsByFilter = `-- name: MarkDeleteNotificationsByFilter :many
+UPDATE notifications n
+SET is_deleted = true,
+    deleted_at = now()
+WHERE n.user_id = $1
+  AND n.is_deleted = false
+  AND ($2::uuid IS NULL OR n.endpoint_id = $2)
+  AND (
+    $3::text IS NULL
+    OR n.search_vector @@ websearch_to_tsquery('simple', $3)
+    OR ($4::text IS NOT NULL AND n.body ILIKE '%' || $4 || '%')
+  )
+  AND ($5::text[] IS NULL OR n.status = ANY($5::text[]))
+  AND (NOT $6::bool OR n.read_at IS NULL)
+  AND (NOT $7::bool OR n.actions IS NOT NULL)
+  AND (
+    NOT $8::bool
+    OR (n.actions IS NOT NULL AND n.reaction IS NULL AND n.status NOT IN ('timeout_reply', 'cancelled'))
+  )
+  AND ($9::timestamp IS NULL OR n.created_at >= $9)
+  AND ($10::timestamp IS NULL OR n.created_at < $10)
+  AND ($11::text IS NULL OR n.reaction = $11)
+RETURNING n.message_id
+`
+
+type MarkDeleteNotificationsByFilterParams struct {
+	UserID      uuid.UUID
+	EndpointID  *uuid.UUID
+	Query       *string
+	Substring   *string
+	Statuses    []string
+	UnreadOnly  bool
+	HasActions  bool
+	PendingAsks bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Reaction    *string
+}
+
+// 필터 조건은 GetNotificationsWithCursor와 같다
+func (q *Queries) MarkDeleteNotificationsByFilter(ctx context.Context, arg MarkDeleteNotificationsByFilterParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, markDeleteNotificationsByFilter,
+		arg.UserID,
+		arg.EndpointID,
+		arg.Query,
+		arg.Substring,
+		arg.Statuses,
+		arg.UnreadOnly,
+		arg.HasActions,
+		arg.PendingAsks,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Reaction,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var message_id uuid.UUID
+		if err := rows.Scan(&message_id); err != nil {
+			return nil, err
+		}
+		items = append(items, message_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const markNotificationsAsReadBefore = `-- name: MarkNotificationsAsReadBefore :exec
 UPDATE notifications
 SET read_at = now()
@@ -258,6 +979,62 @@ func (q *Queries) MarkNotificationsAsReadBefore(ctx context.Context, arg MarkNot
 	return err
 }
 
+const notificationChangeHorizon = `-- name: NotificationChangeHorizon :one
+SELECT pg_snapshot_xmin(pg_current_snapshot())::xid8 AS horizon
+`
+
+// 진행 중인 트랜잭션 중 가장 오래된 것. 이보다 앞선 트랜잭션은 모두 끝났으므로 그 변경은 더 늘어나지 않는다.
+// 변경 피드는 이 앞까지만 내려줘 먼저 받은 change_seq가 늦게 커밋되어도 건너뛰지 않는다
+func (q *Queries) NotificationChangeHorizon(ctx context.Context) (uint64, error) {
+	row := q.db.QueryRow(ctx, notificationChangeHorizon)
+	var horizon uint64
+	err := row.Scan(&horizon)
+	return horizon, err
+}
+
+const purgeDeletedNotifications = `-- name: PurgeDeletedNotifications :execrows
+DELETE FROM notifications
+WHERE user_id = $1
+  AND is_deleted = true
+  AND deleted_at < $2
+`
+
+type PurgeDeletedNotificationsParams struct {
+	UserID        uuid.UUID
+	DeletedBefore *time.Time
+}
+
+// 휴지통에서 영구 삭제. 리액션 이력은 notification_id만 비워진 채 남는다
+func (q *Queries) PurgeDeletedNotifications(ctx context.Context, arg PurgeDeletedNotificationsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedNotifications, arg.UserID, arg.DeletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreNotifications = `-- name: RestoreNotifications :execrows
+UPDATE notifications
+SET is_deleted = false,
+    deleted_at = NULL
+WHERE user_id = $1
+  AND id = ANY($2::uuid[])
+  AND is_deleted = true
+`
+
+type RestoreNotificationsParams struct {
+	UserID uuid.UUID
+	Ids    []uuid.UUID
+}
+
+func (q *Queries) RestoreNotifications(ctx context.Context, arg RestoreNotificationsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreNotifications, arg.UserID, arg.Ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const saveReaction = `-- name: SaveReaction :exec
 UPDATE notifications
 SET reaction = $2,
@@ -275,28 +1052,346 @@ func (q *Queries) SaveReaction(ctx context.Context, arg SaveReactionParams) erro
 	return err
 }
 
-const saveReactionIfActive = `-- name: SaveReactionIfActive :exec
-UPDATE notifications
-SET reaction = $2,
-    reaction_at = now(),
-    status = 'reacted'
-WHERE id = $1
-  AND status NOT IN ('timeout_reply', 'cancelled')
+const saveReactionIfActive = `-- name: SaveReactionIfActive :one
+WITH updated AS (
+    UPDATE notifications
+    SET reaction = $1,
+        reaction_at = now(),
+        reaction_comment = $2,
+        reaction_user_id = $3,
+        reaction_push_token_id = $4,
+        reaction_ip = $5,
+        reaction_user_agent = $6,
+        reaction_auth_method = $7,
+        reaction_auth_at = $8,
+        status = 'reacted'
+    WHERE notifications.message_id = (
+        SELECT message_id FROM notifications WHERE notifications.id = $9
+    )
+      AND status NOT IN ('timeout_reply', 'cancelled')
+    RETURNING
+        notifications.id,
+        message_id,
+        endpoint_id,
+        reaction,
+        reaction_comment,
+        reaction_user_id,
+        reaction_push_token_id,
+        reaction_ip,
+        reaction_user_agent,
+        reaction_auth_method,
+        reaction_auth_at,
+        reaction_at
+)
+INSERT INTO notification_reactions (
+    notification_id,
+    message_id,
+    endpoint_id,
+    reaction,
+    comment,
+    user_id,
+    push_token_id,
+    ip,
+    user_agent,
+    auth_method,
+    auth_at,
+    created_at
+)
+SELECT
+    id,
+    message_id,
+    endpoint_id,
+    reaction,
+    reaction_comment,
+    reaction_user_id,
+    reaction_push_token_id,
+    reaction_ip,
+    reaction_user_agent,
+    reaction_auth_method,
+    reaction_auth_at,
+    reaction_at
+FROM updated
+WHERE id = $9
+RETURNING id, created_at
 `
 
 type SaveReactionIfActiveParams struct {
-	ID       uuid.UUID
-	Reaction *string
+	Reaction    *string
+	Comment     *string
+	UserID      *uuid.UUID
+	PushTokenID *uuid.UUID
+	Ip          *string
+	UserAgent   *string
+	AuthMethod  *string
+	AuthAt      *time.Time
+	ID          uuid.UUID
+}
+
+type SaveReactionIfActiveRow struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+}
+
+// 같은 발송의 멤버별 알림에 모두 반영하고, 이력은 응답한 알림 기준으로 한 번만 남긴다.
+// 응답할 수 없는 상태면 행이 없다
+func (q *Queries) SaveReactionIfActive(ctx context.Context, arg SaveReactionIfActiveParams) (SaveReactionIfActiveRow, error) {
+	row := q.db.QueryRow(ctx, saveReactionIfActive,
+		arg.Reaction,
+		arg.Comment,
+		arg.UserID,
+		arg.PushTokenID,
+		arg.Ip,
+		arg.UserAgent,
+		arg.AuthMethod,
+		arg.AuthAt,
+		arg.ID,
+	)
+	var i SaveReactionIfActiveRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
+const searchNotifications = `-- name: SearchNotifications :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1) AS tsq
+), matched AS (
+    SELECT
+        n.id,
+        n.endpoint_id,
+        n.user_id,
+        n.body,
+        n.status,
+        n.read_at,
+        n.created_at,
+        n.endpoint_name,
+        n.actions,
+        n.reaction,
+        n.reaction_at,
+        n.sensitive,
+        n.reaction_comment,
+        n.sender_key_id,
+        n.sender_key_label,
+        ts_rank(n.search_vector, q.tsq) AS rank
+    FROM notifications n, q
+    WHERE n.user_id = $2
+      AND n.is_deleted = false
+      AND ($3::uuid IS NULL OR n.endpoint_id = $3)
+      AND (
+        n.search_vector @@ q.tsq
+        OR ($4::text IS NOT NULL AND n.body ILIKE '%' || $4 || '%')
+      )
+), page AS (
+    SELECT * FROM matched
+    WHERE $5::uuid IS NULL
+       OR (matched.rank, matched.id) < ($6::real, $5::uuid)
+    ORDER BY matched.rank DESC, matched.id DESC
+    LIMIT $7
+)
+SELECT
+    page.id,
+    page.endpoint_id,
+    page.user_id,
+    page.body,
+    page.status,
+    page.read_at,
+    page.created_at,
+    page.endpoint_name,
+    page.actions,
+    page.reaction,
+    page.reaction_at,
+    page.sensitive,
+    page.reaction_comment,
+    page.sender_key_id,
+    page.sender_key_label,
+    page.rank,
+    ts_headline('simple', page.body, q.tsq, 'StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5, MaxFragments=2')::text AS snippet
+FROM page, q
+ORDER BY page.rank DESC, page.id DESC
+`
+
+type SearchNotificationsParams struct {
+	Query      string
+	UserID     uuid.UUID
+	EndpointID *uuid.UUID
+	Substring  *string
+	CursorID   *uuid.UUID
+	CursorRank *float32
+	Limit      int32
+}
+
+type SearchNotificationsRow struct {
+	ID              uuid.UUID
+	EndpointID      *uuid.UUID
+	UserID          uuid.UUID
+	Body            string
+	Status          *string
+	ReadAt          *time.Time
+	CreatedAt       time.Time
+	EndpointName    string
+	Actions         []string
+	Reaction        *string
+	ReactionAt      *time.Time
+	Sensitive       bool
+	ReactionComment *string
+	SenderKeyID     *uuid.UUID
+	SenderKeyLabel  *string
+	Rank            float32
+	Snippet         string
+}
+
+// websearch_to_tsquery 문법("구문", -제외, or)으로 검색. substring이 있으면 부분 일치도 포함한다.
+// (rank, id) 내림차순이며 커서도 같은 순서로 비교
+func (q *Queries) SearchNotifications(ctx context.Context, arg SearchNotificationsParams) ([]SearchNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, searchNotifications,
+		arg.Query,
+		arg.Substring,
+		arg.UserID,
+		arg.EndpointID,
+		arg.Substring,
+		arg.CursorID,
+		arg.CursorRank,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchNotificationsRow
+	for rows.Next() {
+		var i SearchNotificationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EndpointID,
+			&i.UserID,
+			&i.Body,
+			&i.Status,
+			&i.ReadAt,
+			&i.CreatedAt,
+			&i.EndpointName,
+			&i.Actions,
+			&i.Reaction,
+			&i.ReactionAt,
+			&i.Sensitive,
+			&i.ReactionComment,
+			&i.SenderKeyID,
+			&i.SenderKeyLabel,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setNotificationsRead = `-- name: SetNotificationsRead :execrows
+UPDATE notifications
+SET read_at = CASE WHEN $1::bool THEN coalesce(read_at, now()) ELSE NULL END
+WHERE user_id = $2
+  AND id = ANY($3::uuid[])
+  AND is_deleted = false
+`
+
+type SetNotificationsReadParams struct {
+	Read   bool
+	UserID uuid.UUID
+	Ids    []uuid.UUID
 }
 
-func (q *Queries) SaveReactionIfActive(ctx context.Context, arg SaveReactionIfActiveParams) error {
-	_, err := q.db.Exec(ctx, saveReactionIfActive, arg.ID, arg.Reaction)
+// 이미 같은 상태인 알림도 영향받은 수에 포함된다 (읽은 시각은 처음 값 유지)
+func (q *Queries) SetNotificationsRead(ctx context.Context, arg SetNotificationsReadParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setNotificationsRead, arg.Read, arg.UserID, arg.Ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setNotificationsReadByFilter = `-- name: SetNotificationsReadByFilter :execrows
+UPDATE notifications n
+SET read_at = CASE WHEN $1::bool THEN coalesce(n.read_at, now()) ELSE NULL END
+WHERE n.user_id = $2
+  AND n.is_deleted = false
+  AND ($3::uuid IS NULL OR n.endpoint_id = $3)
+  AND (
+    $4::text IS NULL
+    OR n.search_vector @@ websearch_to_tsquery('simple', $4)
+    OR ($5::text IS NOT NULL AND n.body ILIKE '%' || $5 || '%')
+  )
+  AND ($6::text[] IS NULL OR n.status = ANY($6::text[]))
+  AND (NOT $7::bool OR n.read_at IS NULL)
+  AND (NOT $8::bool OR n.actions IS NOT NULL)
+  AND (
+    NOT $9::bool
+    OR (n.actions IS NOT NULL AND n.reaction IS NULL AND n.status NOT IN ('timeout_reply', 'cancelled'))
+  )
+  AND ($10::timestamp IS NULL OR n.created_at >= $10)
+  AND ($11::timestamp IS NULL OR n.created_at < $11)
+  AND ($12::text IS NULL OR n.reaction = $12)
+`
+
+type SetNotificationsReadByFilterParams struct {
+	Read        bool
+	UserID      uuid.UUID
+	EndpointID  *uuid.UUID
+	Query       *string
+	Substring   *string
+	Statuses    []string
+	UnreadOnly  bool
+	HasActions  bool
+	PendingAsks bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Reaction    *string
+}
+
+// 필터 조건은 GetNotificationsWithCursor와 같다
+func (q *Queries) SetNotificationsReadByFilter(ctx context.Context, arg SetNotificationsReadByFilterParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setNotificationsReadByFilter,
+		arg.Read,
+		arg.UserID,
+		arg.EndpointID,
+		arg.Query,
+		arg.Substring,
+		arg.Statuses,
+		arg.UnreadOnly,
+		arg.HasActions,
+		arg.PendingAsks,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Reaction,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateStatusByMessageID = `-- name: UpdateStatusByMessageID :exec
+UPDATE notifications
+SET status = $2
+WHERE message_id = $1
+`
+
+type UpdateStatusByMessageIDParams struct {
+	MessageID uuid.UUID
+	Status    *string
+}
+
+// 같은 발송의 멤버별 알림 상태를 한 번에 변경 (ask 타임아웃, 취소)
+func (q *Queries) UpdateStatusByMessageID(ctx context.Context, arg UpdateStatusByMessageIDParams) error {
+	_, err := q.db.Exec(ctx, updateStatusByMessageID, arg.MessageID, arg.Status)
 	return err
 }
 
 const updateStatusNotification = `-- name: UpdateStatusNotification :exec
 UPDATE notifications
-SET status = $2
+SET status = $2,
+    delivery = $2
 WHERE id = $1
 `
 
@@ -305,6 +1400,7 @@ type UpdateStatusNotificationParams struct {
 	Status *string
 }
 
+// web push 결과 (sent, failed). status는 ask 응답으로 바뀌므로 통계용으로 delivery에도 남긴다
 func (q *Queries) UpdateStatusNotification(ctx context.Context, arg UpdateStatusNotificationParams) error {
 	_, err := q.db.Exec(ctx, updateStatusNotification, arg.ID, arg.Status)
 	return err
@@ -0,0 +1,93 @@
+package postgresql
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// testDB 스키마가 적용된 DB. TEST_DATABASE_URL이 없으면 건너뛴다.
+// 모든 변경은 트랜잭션 안에서 하고 끝나면 되돌린다
+func testDB(t *testing.T) pgx.Tx {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	conn, err := pgx.Connect(ctx, url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close(context.Background()) })
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	t.Cleanup(func() { tx.Rollback(context.Background()) })
+	return tx
+}
+
+// execSavepoint 실패해도 바깥 트랜잭션을 계속 쓸 수 있도록 savepoint 안에서 실행
+func execSavepoint(tx pgx.Tx, sql string, args ...any) error {
+	ctx := context.Background()
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := sp.Exec(ctx, sql, args...); err != nil {
+		sp.Rollback(ctx)
+		return err
+	}
+	return sp.Commit(ctx)
+}
+
+func TestNotificationReactions_AppendOnly(t *testing.T) {
+	tx := testDB(t)
+	ctx := context.Background()
+
+	id := uuid.New()
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO notification_reactions (id, notification_id, reaction, message_id) VALUES ($1, NULL, '승인', $2)`,
+		id, uuid.New(),
+	); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	rejected := []struct {
+		name string
+		sql  string
+	}{
+		{"update", `UPDATE notification_reactions SET reaction = '거절' WHERE id = $1`},
+		{"delete", `DELETE FROM notification_reactions WHERE id = $1`},
+	}
+	for _, c := range rejected {
+		err := execSavepoint(tx, c.sql, id)
+		if err == nil || !strings.Contains(err.Error(), "append-only") {
+			t.Errorf("%s expected append-only error, got: %v", c.name, err)
+		}
+	}
+	if err := execSavepoint(tx, `TRUNCATE notification_reactions`); err == nil || !strings.Contains(err.Error(), "append-only") {
+		t.Errorf("truncate expected append-only error, got: %v", err)
+	}
+
+	// 알림이 지워질 때처럼 notification_id만 비우는 것은 허용
+	if err := execSavepoint(tx, `UPDATE notification_reactions SET notification_id = NULL WHERE id = $1`, id); err != nil {
+		t.Errorf("detach expected ok, got: %v", err)
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, `SELECT count(*) FROM notification_reactions WHERE id = $1`, id).Scan(&count); err != nil || count != 1 {
+		t.Errorf("reaction should remain, got: %d %v", count, err)
+	}
+}
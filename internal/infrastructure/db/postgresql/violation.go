@@ -15,6 +15,15 @@ func IsUniqueViolation(err error) bool {
 	return false
 }
 
+// IsUniqueViolationOn 특정 unique 제약조건 위반인지 확인
+func IsUniqueViolationOn(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+	}
+	return false
+}
+
 func IsNoRows(err error) bool {
 	if errors.Is(err, pgx.ErrNoRows) {
 		return true
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sethvargo/go-envconfig"
@@ -18,6 +19,9 @@ type Env struct {
 	Service Service `env:", prefix=SERVICE_"`
 	Vapid   Vapid   `env:", prefix=VAPID_"`
 	Github  Github  `env:", prefix=GITHUB_"`
+	Auth    Auth    `env:", prefix=AUTH_"`
+
+	Retention Retention `env:", prefix=RETENTION_"`
 
 	DB DB `env:", prefix=DB_"`
 }
@@ -31,6 +35,8 @@ type Log struct {
 
 type Service struct {
 	Port int `env:"PORT,default=8282"`
+	// 리버스 프록시 뒤에 있을 때만 true. X-Forwarded-For 헤더를 신뢰함
+	TrustProxy bool `env:"TRUST_PROXY,default=false"`
 }
 
 type Vapid struct {
@@ -42,6 +48,24 @@ type Github struct {
 	ClientSecret string `env:"CLIENT_SECRET"`
 }
 
+type Auth struct {
+	// sensitive 요청에 응답하려면 이 시간 안에 다시 로그인했어야 함
+	StepUpMaxAge time.Duration `env:"STEP_UP_MAX_AGE,default=5m"`
+	// 서명 요청의 timestamp 허용 오차. 이 범위 안에서 같은 서명은 한 번만 허용
+	SignatureMaxSkew time.Duration `env:"SIGNATURE_MAX_SKEW,default=5m"`
+	// endpoint 토큰 해시와 서명 secret 암호화에 쓰는 키. 바꾸면 기존 토큰과 서명 secret이 모두 무효가 됨
+	EndpointTokenKey string `env:"ENDPOINT_TOKEN_KEY"`
+}
+
+type Retention struct {
+	// 모든 알림의 최대 보관 일수. 유저, endpoint 보관 정책은 이보다 길게 잡을 수 없음. 0이면 최대 없음
+	MaxDays int `env:"MAX_DAYS,default=0"`
+	// 보관 정책에 따른 삭제 작업 주기
+	PurgeInterval time.Duration `env:"PURGE_INTERVAL,default=10m"`
+	// 한 번에 지우는 행 수. 크게 잡으면 잠금이 길어진다
+	PurgeBatchSize int `env:"PURGE_BATCH_SIZE,default=1000"`
+}
+
 func NewEnv() (Env, error) {
 	var env Env
 
@@ -67,6 +91,12 @@ func validateEnv(e *Env) error {
 	if e.Stage == StageUnknown {
 		return errUnknownStage
 	}
+	if e.Auth.EndpointTokenKey == "" {
+		return errMissingEndpointTokenKey
+	}
+	if e.Retention.MaxDays < 0 || e.Retention.PurgeInterval <= 0 || e.Retention.PurgeBatchSize < 1 {
+		return errInvalidRetention
+	}
 
 	return nil
 
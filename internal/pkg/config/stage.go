@@ -15,6 +15,9 @@ const (
 
 var (
 	errUnknownStage = errors.New("unknown stage")
+
+	errMissingEndpointTokenKey = errors.New("AUTH_ENDPOINT_TOKEN_KEY is required")
+	errInvalidRetention        = errors.New("RETENTION_MAX_DAYS must not be negative, RETENTION_PURGE_INTERVAL and RETENTION_PURGE_BATCH_SIZE must be positive")
 )
 
 // 문자열로 변환
@@ -3,6 +3,7 @@ package pkg
 import (
 	"time"
 	"torchi/internal/pkg/config"
+	"torchi/internal/pkg/signature"
 	"torchi/internal/pkg/token"
 
 	"go.uber.org/fx"
@@ -23,5 +24,13 @@ var Module = fx.Options(
 				// 20*time.Second,
 			)
 		},
+		signature.NewReplayCache,
+		func(env config.Env) *token.Hasher {
+			return token.NewHasher(env.Auth.EndpointTokenKey)
+		},
+		// endpoint 서명 secret 암호화. 토큰 해시와 같은 서버 키를 쓴다
+		func(env config.Env) *token.Sealer {
+			return token.NewSealer(env.Auth.EndpointTokenKey, "endpoint-signing-secret")
+		},
 	),
 )
@@ -0,0 +1,46 @@
+package signature
+
+import (
+	"sync"
+	"time"
+)
+
+// 이 개수를 넘으면 만료된 항목을 정리
+const sweepThreshold = 1024
+
+// ReplayCache 허용 시각 범위 안에서 같은 서명이 다시 쓰이는 것을 막는다.
+// 서명은 timestamp를 포함하므로 범위를 벗어난 요청은 Verify에서 걸러짐.
+// 프로세스 메모리 캐시라 서버를 여러 대 띄우면 다른 서버로 보낸 재전송은 막지 못하고, 재시작하면 비워진다.
+type ReplayCache struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastSweep int
+}
+
+func NewReplayCache() *ReplayCache {
+	return &ReplayCache{
+		seen: make(map[string]time.Time),
+	}
+}
+
+// Seen key가 이미 쓰였으면 true. 처음이면 expiresAt까지 기록하고 false.
+func (c *ReplayCache) Seen(key string, now, expiresAt time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if exp, ok := c.seen[key]; ok && now.Before(exp) {
+		return true
+	}
+
+	if len(c.seen)-c.lastSweep >= sweepThreshold {
+		for k, exp := range c.seen {
+			if !now.Before(exp) {
+				delete(c.seen, k)
+			}
+		}
+		c.lastSweep = len(c.seen)
+	}
+
+	c.seen[key] = expiresAt
+	return false
+}
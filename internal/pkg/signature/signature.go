@@ -0,0 +1,76 @@
+// Package signature push/ask 요청의 HMAC-SHA256 서명 생성 및 검증.
+//
+// 서명 대상: "{timestamp}\n{METHOD}\n{request URI}\n{body}"
+// timestamp는 unix 초, request URI는 query string을 포함한 경로.
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderTimestamp = "X-Torchi-Timestamp"
+	HeaderSignature = "X-Torchi-Signature"
+
+	// 서명 헤더에 붙일 수 있는 접두사 (GitHub webhook 형식과 동일)
+	prefix = "sha256="
+)
+
+var (
+	ErrMissing = errors.New("signature: missing timestamp or signature")
+	ErrStale   = errors.New("signature: timestamp outside allowed skew")
+	ErrInvalid = errors.New("signature: invalid signature")
+)
+
+// Sign hex 인코딩된 서명 반환
+func Sign(secret string, timestamp int64, method, requestURI string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(strings.ToUpper(method)))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(requestURI))
+	mac.Write([]byte{'\n'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type Request struct {
+	Timestamp  string // HeaderTimestamp 값
+	Signature  string // HeaderSignature 값
+	Method     string
+	RequestURI string
+	Body       []byte
+}
+
+// Verify 시각 차이가 maxSkew 이내이고 서명이 일치하는지 확인.
+// 재사용 방지는 ReplayCache로 따로 해야 한다.
+func Verify(secret string, req Request, now time.Time, maxSkew time.Duration) error {
+	if req.Timestamp == "" || req.Signature == "" {
+		return ErrMissing
+	}
+
+	ts, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalid
+	}
+	if diff := now.Sub(time.Unix(ts, 0)); diff > maxSkew || diff < -maxSkew {
+		return ErrStale
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(req.Signature, prefix))
+	if err != nil {
+		return ErrInvalid
+	}
+	want, _ := hex.DecodeString(Sign(secret, ts, req.Method, req.RequestURI, req.Body))
+	if !hmac.Equal(got, want) {
+		return ErrInvalid
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package signature
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newRequest(secret string, ts time.Time, body string) Request {
+	unix := ts.Unix()
+	return Request{
+		Timestamp:  strconv.FormatInt(unix, 10),
+		Signature:  Sign(secret, unix, "POST", "/api/v1/push/abc", []byte(body)),
+		Method:     "POST",
+		RequestURI: "/api/v1/push/abc",
+		Body:       []byte(body),
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	skew := 5 * time.Minute
+
+	valid := newRequest("secret", now, "hello")
+	if err := Verify("secret", valid, now, skew); err != nil {
+		t.Fatalf("expected valid, got: %v", err)
+	}
+
+	prefixed := valid
+	prefixed.Signature = "sha256=" + valid.Signature
+	if err := Verify("secret", prefixed, now, skew); err != nil {
+		t.Errorf("expected prefixed signature valid, got: %v", err)
+	}
+
+	tampered := valid
+	tampered.Body = []byte("hello!")
+	if err := Verify("secret", tampered, now, skew); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected invalid for tampered body, got: %v", err)
+	}
+
+	otherPath := valid
+	otherPath.RequestURI = "/api/v1/push/abc/ask"
+	if err := Verify("secret", otherPath, now, skew); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected invalid for other path, got: %v", err)
+	}
+
+	if err := Verify("other", valid, now, skew); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected invalid for wrong secret, got: %v", err)
+	}
+
+	stale := newRequest("secret", now.Add(-10*time.Minute), "hello")
+	if err := Verify("secret", stale, now, skew); !errors.Is(err, ErrStale) {
+		t.Errorf("expected stale, got: %v", err)
+	}
+
+	future := newRequest("secret", now.Add(10*time.Minute), "hello")
+	if err := Verify("secret", future, now, skew); !errors.Is(err, ErrStale) {
+		t.Errorf("expected stale for future timestamp, got: %v", err)
+	}
+
+	if err := Verify("secret", Request{Method: "POST"}, now, skew); !errors.Is(err, ErrMissing) {
+		t.Errorf("expected missing, got: %v", err)
+	}
+}
+
+func TestReplayCache(t *testing.T) {
+	c := NewReplayCache()
+	now := time.Now()
+
+	if c.Seen("a", now, now.Add(time.Minute)) {
+		t.Fatal("first use should not be seen")
+	}
+	if !c.Seen("a", now.Add(time.Second), now.Add(time.Minute)) {
+		t.Error("second use within window should be seen")
+	}
+	if c.Seen("a", now.Add(2*time.Minute), now.Add(3*time.Minute)) {
+		t.Error("use after expiry should not be seen")
+	}
+}
@@ -0,0 +1,35 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// 화면 표시용으로 남기는 endpoint 토큰 앞자리 수
+const prefixLength = 4
+
+// Hasher endpoint 토큰을 DB에 저장할 때 쓰는 keyed hash.
+// 키가 없으면 DB 덤프만으로는 토큰을 대입해 볼 수 없다.
+type Hasher struct {
+	key []byte
+}
+
+func NewHasher(key string) *Hasher {
+	return &Hasher{key: []byte(key)}
+}
+
+// Hash hex 인코딩된 HMAC-SHA256
+func (h *Hasher) Hash(t string) string {
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(t))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Prefix 토큰 구분용 앞자리. 비밀이 아니므로 평문 저장
+func Prefix(t string) string {
+	if len(t) <= prefixLength {
+		return t
+	}
+	return t[:prefixLength]
+}
@@ -0,0 +1,21 @@
+package token
+
+import "testing"
+
+func TestHasher(t *testing.T) {
+	h := NewHasher("key")
+
+	if h.Hash("abc") != h.Hash("abc") {
+		t.Error("same token should give same hash")
+	}
+	if h.Hash("abc") == NewHasher("other").Hash("abc") {
+		t.Error("hash should depend on key")
+	}
+	if h.Hash("abc") == "abc" {
+		t.Error("hash should not be plaintext")
+	}
+
+	if got := Prefix("aB3dE6gH9jK"); got != "aB3d" {
+		t.Errorf("expected prefix aB3d, got: %s", got)
+	}
+}
@@ -14,12 +14,49 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+const (
+	AuthMethodGithub = "github"
+	AuthMethodGuest  = "guest"
+)
+
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
+	UserID     uuid.UUID        `json:"user_id"`
+	Email      string           `json:"email"`
+	AuthMethod string           `json:"auth_method,omitempty"` // 로그인 수단
+	AuthTime   *jwt.NumericDate `json:"auth_time,omitempty"`   // 실제 로그인한 시각, refresh 해도 유지됨
 	jwt.RegisteredClaims
 }
 
+// AuthInfo 토큰이 어떤 로그인으로 발급됐는지. refresh 시 그대로 이어받는다.
+type AuthInfo struct {
+	Method string
+	Time   time.Time
+}
+
+// NewAuthInfo 방금 로그인한 경우의 AuthInfo
+func NewAuthInfo(method string) AuthInfo {
+	return AuthInfo{Method: method, Time: time.Now()}
+}
+
+// AuthInfo 기존 토큰의 로그인 정보를 꺼낸다. auth_time이 없는 예전 토큰은 zero time.
+func (c *Claims) AuthInfo() AuthInfo {
+	info := AuthInfo{Method: c.AuthMethod}
+	if c.AuthTime != nil {
+		info.Time = c.AuthTime.Time
+	}
+	return info
+}
+
+// AuthenticatedWithin maxAge 이내에 실제 로그인(step-up)을 했는지 확인.
+// 게스트 로그인은 브라우저에 저장된 id만으로 재발급되므로 재인증으로 보지 않는다.
+// step-up은 다시 로그인하는 것뿐이며 WebAuthn 확인은 지원하지 않는다.
+func (c *Claims) AuthenticatedWithin(maxAge time.Duration) bool {
+	if c.AuthTime == nil || c.AuthMethod == "" || c.AuthMethod == AuthMethodGuest {
+		return false
+	}
+	return time.Since(c.AuthTime.Time) <= maxAge
+}
+
 type TokenProvider struct {
 	secret        []byte
 	issuer        string
@@ -37,21 +74,21 @@ func NewTokenProvider(secret string, issuer string, accessExp time.Duration, ref
 }
 
 // CreateAccessToken: 짧은 수명의 액세스 토큰 생성
-func (p *TokenProvider) CreateAccessToken(userID uuid.UUID, email string) (string, error) {
-	return p.generateToken(userID, email, p.accessExpiry)
+func (p *TokenProvider) CreateAccessToken(userID uuid.UUID, email string, auth AuthInfo) (string, error) {
+	return p.generateToken(userID, email, auth, p.accessExpiry)
 }
 
 // CreateRefreshToken: 긴 수명의 리프레시 토큰 생성
-func (p *TokenProvider) CreateRefreshToken(userID uuid.UUID, email string) (string, error) {
-	return p.generateToken(userID, email, p.refreshExpiry)
+func (p *TokenProvider) CreateRefreshToken(userID uuid.UUID, email string, auth AuthInfo) (string, error) {
+	return p.generateToken(userID, email, auth, p.refreshExpiry)
 }
 
-func (p *TokenProvider) CreatePairToken(userID uuid.UUID, email string) (string, string, error) {
-	access, err := p.CreateAccessToken(userID, email)
+func (p *TokenProvider) CreatePairToken(userID uuid.UUID, email string, auth AuthInfo) (string, string, error) {
+	access, err := p.CreateAccessToken(userID, email, auth)
 	if err != nil {
 		return "", "", err
 	}
-	refresh, err := p.CreateRefreshToken(userID, email)
+	refresh, err := p.CreateRefreshToken(userID, email, auth)
 	if err != nil {
 		return "", "", err
 	}
@@ -59,10 +96,11 @@ func (p *TokenProvider) CreatePairToken(userID uuid.UUID, email string) (string,
 }
 
 // 내부 공통 토큰 생성 로직
-func (p *TokenProvider) generateToken(userID uuid.UUID, email string, expiry time.Duration) (string, error) {
+func (p *TokenProvider) generateToken(userID uuid.UUID, email string, auth AuthInfo, expiry time.Duration) (string, error) {
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:     userID,
+		Email:      email,
+		AuthMethod: auth.Method,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -70,6 +108,10 @@ func (p *TokenProvider) generateToken(userID uuid.UUID, email string, expiry tim
 		},
 	}
 
+	if !auth.Time.IsZero() {
+		claims.AuthTime = jwt.NewNumericDate(auth.Time)
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(p.secret)
 }
@@ -0,0 +1,71 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newTestProvider() *TokenProvider {
+	return NewTokenProvider("secret", "torchi-test", time.Hour, 24*time.Hour)
+}
+
+// refresh로 재발급해도 auth_time은 유지되어야 함
+func TestAuthInfo_KeptOnRenew(t *testing.T) {
+	p := newTestProvider()
+	loginAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	at, err := p.CreateAccessToken(uuid.New(), "a@b.c", AuthInfo{Method: AuthMethodGithub, Time: loginAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := p.Validate(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renewed, err := p.CreateAccessToken(claims.UserID, claims.Email, claims.AuthInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	renewedClaims, err := p.Validate(renewed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !renewedClaims.AuthInfo().Time.Equal(loginAt) {
+		t.Errorf("auth_time mismatch, expected: %v, got: %v", loginAt, renewedClaims.AuthInfo().Time)
+	}
+	if renewedClaims.AuthenticatedWithin(5 * time.Minute) {
+		t.Error("1시간 전 로그인은 step-up으로 인정되면 안 됨")
+	}
+}
+
+func TestAuthenticatedWithin(t *testing.T) {
+	p := newTestProvider()
+
+	cases := []struct {
+		name     string
+		auth     AuthInfo
+		expected bool
+	}{
+		{"fresh github login", NewAuthInfo(AuthMethodGithub), true},
+		{"fresh guest login", NewAuthInfo(AuthMethodGuest), false},
+		{"legacy token", AuthInfo{}, false},
+	}
+
+	for _, c := range cases {
+		at, err := p.CreateAccessToken(uuid.New(), "", c.auth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims, err := p.Validate(at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := claims.AuthenticatedWithin(5 * time.Minute); got != c.expected {
+			t.Errorf("%s: expected: %v, got: %v", c.name, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package token
+
+import "strings"
+
+// 로그에 남길 앞자리 수
+const redactVisible = 3
+
+// Redact 로그용. 앞 몇 글자만 남기고 가린다. 짧은 값은 전부 가림
+func Redact(t string) string {
+	if t == "" {
+		return ""
+	}
+	if len(t) <= redactVisible*2 {
+		return "***"
+	}
+	return t[:redactVisible] + "***"
+}
+
+// RedactIn s 안에 있는 t를 모두 Redact(t)로 치환
+func RedactIn(s, t string) string {
+	if t == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, t, Redact(t))
+}
@@ -0,0 +1,22 @@
+package token
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	cases := map[string]string{
+		"":            "",
+		"abc":         "***",
+		"abcdef":      "***",
+		"aB3dE6gH9jK": "aB3***",
+	}
+	for in, want := range cases {
+		if got := Redact(in); got != want {
+			t.Errorf("Redact(%q) expected: %q, got: %q", in, want, got)
+		}
+	}
+
+	path := "/api/v1/push/aB3dE6gH9jK/ask"
+	if got := RedactIn(path, "aB3dE6gH9jK"); got != "/api/v1/push/aB3***/ask" {
+		t.Errorf("unexpected redacted path: %s", got)
+	}
+}
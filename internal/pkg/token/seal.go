@@ -0,0 +1,75 @@
+package token
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+)
+
+// sealedPrefix 암호화한 값의 형식 버전. base62 평문에는 '.'이 없으므로 평문과 구분된다
+const sealedPrefix = "v1."
+
+var ErrUnsealed = errors.New("token: value is not sealed")
+
+// Sealer 서명 secret처럼 다시 꺼내 써야 하는 값을 DB에 저장할 때 쓰는 AES-256-GCM.
+// 키는 서버 키와 용도 이름으로 만들어 같은 서버 키를 쓰는 Hasher와 겹치지 않는다.
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+func NewSealer(serverKey, purpose string) *Sealer {
+	mac := hmac.New(sha256.New, []byte(serverKey))
+	mac.Write([]byte(purpose))
+
+	// 32바이트 키와 기본 nonce 길이라 실패하지 않는다
+	block, _ := aes.NewCipher(mac.Sum(nil))
+	aead, _ := cipher.NewGCM(block)
+	return &Sealer{aead: aead}
+}
+
+// Seal 매번 새 nonce를 써서 같은 값도 결과가 다르다
+func (s *Sealer) Seal(plain string) (string, error) {
+	return s.seal(rand.Reader, plain)
+}
+
+func (s *Sealer) seal(r io.Reader, plain string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(r, nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return sealedPrefix + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Open Seal한 값이 아니면 ErrUnsealed. 키가 다르거나 값이 바뀌었으면 에러
+func (s *Sealer) Open(sealed string) (string, error) {
+	encoded, ok := strings.CutPrefix(sealed, sealedPrefix)
+	if !ok {
+		return "", ErrUnsealed
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	if len(raw) < s.aead.NonceSize() {
+		return "", errors.New("token: sealed value too short")
+	}
+
+	nonce, ciphertext := raw[:s.aead.NonceSize()], raw[s.aead.NonceSize():]
+	plain, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// IsSealed Seal한 형식인지만 본다
+func IsSealed(v string) bool {
+	return strings.HasPrefix(v, sealedPrefix)
+}
@@ -0,0 +1,37 @@
+package token
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSealer(t *testing.T) {
+	s := NewSealer("key", "signing-secret")
+
+	sealed, err := s.Seal("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) || sealed == "secret" {
+		t.Fatalf("expected sealed value, got: %s", sealed)
+	}
+	if again, _ := s.Seal("secret"); again == sealed {
+		t.Error("same value should seal differently")
+	}
+
+	if got, err := s.Open(sealed); err != nil || got != "secret" {
+		t.Errorf("expected secret, got: %q %v", got, err)
+	}
+	if _, err := NewSealer("other", "signing-secret").Open(sealed); err == nil {
+		t.Error("different key should not open")
+	}
+	if _, err := NewSealer("key", "other").Open(sealed); err == nil {
+		t.Error("different purpose should not open")
+	}
+	if _, err := s.Open("secret"); !errors.Is(err, ErrUnsealed) {
+		t.Errorf("plaintext expected: %v, got: %v", ErrUnsealed, err)
+	}
+	if _, err := s.Open(sealed[:len(sealed)-2]); err == nil {
+		t.Error("tampered value should not open")
+	}
+}